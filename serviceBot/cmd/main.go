@@ -1,10 +1,13 @@
 package main
 
 import (
+	"log"
 	clientsMatch "serviceBot/internal/clients/match_client"
 	clientsUser "serviceBot/internal/clients/user_client"
 	"serviceBot/internal/config"
+	"serviceBot/internal/delivery"
 	"serviceBot/internal/usecase"
+	_ "time/tzdata"
 )
 
 func main() {
@@ -12,5 +15,10 @@ func main() {
 	serviceUser := clientsUser.NewHTTPUserServiseClient(cfg.USER_SERVICE)
 	serviceMatch := clientsMatch.NewHTTPMatchServiseClient(cfg.MATCH_SERVICE)
 	uc := usecase.NewUseCase(serviceUser, serviceMatch)
-	uc.StartBot(cfg.TELEGRAM_BOT_TOKEN)
+	poller, err := delivery.NewPoller(cfg.UPDATES_MODE, cfg.WEBHOOK_URL, cfg.WEBHOOK_SECRET, cfg.WEBHOOK_LISTEN)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go delivery.NewInternalAPI(uc).Start(cfg.INTERNAL_ADDR)
+	uc.StartBot(cfg.TELEGRAM_BOT_TOKEN, poller)
 }
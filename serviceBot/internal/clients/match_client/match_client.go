@@ -1,8 +1,13 @@
 package clientsMatch
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"serviceBot/internal/entity"
 )
 
 type HTTPmatchServiseClient struct {
@@ -17,10 +22,198 @@ func NewHTTPMatchServiseClient(baseURL string) *HTTPmatchServiseClient {
 	}
 }
 
-func (c *HTTPmatchServiseClient) LikeUser(fromUserID, toUserID int64) error {
-	url := fmt.Sprintf("%s/like/%d/%d", c.baseURL, fromUserID, toUserID)
+var (
+	// ErrInvalidLike - serviceMatch не принял лайк, например из-за ссылки в комментарии
+	ErrInvalidLike = errors.New("invalid like")
+	// ErrQuotaExceeded - дневной лимит лайков или суперлайков исчерпан
+	ErrQuotaExceeded = errors.New("daily like quota exceeded")
+	// ErrTooManySwipes - антиспам serviceMatch: пользователь свайпает слишком часто
+	ErrTooManySwipes = errors.New("too many swipes")
+	// ErrProfileNotFound - анкеты уже нет в serviceUser
+	ErrProfileNotFound = errors.New("profile not found")
+)
+
+// LikeUser - лайк анкеты; цель, комментарий и признак суперлайка передаются в теле, если они указаны.
+// Возвращает остаток дневных квот (nil, если serviceMatch их не ограничивает)
+func (c *HTTPmatchServiseClient) LikeUser(like entity.Like) (*entity.Quota, error) {
+	url := fmt.Sprintf("%s/like/%d/%d", c.baseURL, like.FromUserID, like.ToUserID)
+
+	var body io.Reader
+	if like.Target != "" || like.PromptID != nil || like.Comment != "" || like.Super {
+		data, err := json.Marshal(like)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest {
+		return nil, ErrInvalidLike
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrProfileNotFound
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusTooManyRequests {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Remaining  *entity.Quota `json:"remaining"`
+		RetryAfter int           `json:"retry_after"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		// 429 бывает от дневной квоты и от антиспама; антиспам говорит, когда повторить
+		if result.RetryAfter > 0 {
+			return nil, ErrTooManySwipes
+		}
+		return result.Remaining, ErrQuotaExceeded
+	}
+	return result.Remaining, nil
+}
+
+// Quota - остаток дневных квот пользователя
+func (c *HTTPmatchServiseClient) Quota(userID int64) (*entity.Quota, error) {
+	var result struct {
+		Remaining *entity.Quota `json:"remaining"`
+	}
+	err := c.getList(fmt.Sprintf("%s/users/%d/quota", c.baseURL, userID), &result)
+	return result.Remaining, err
+}
+
+var (
+	// ErrNothingToUndo - последний свайп уже отменен или окно отмены прошло
+	ErrNothingToUndo = errors.New("nothing to undo")
+	// ErrUndoLimitReached - дневной лимит отмен исчерпан
+	ErrUndoLimitReached = errors.New("daily undo limit reached")
+)
+
+// Dislike - дизлайк анкеты, нужен для отмены свайпа
+func (c *HTTPmatchServiseClient) Dislike(fromUserID, toUserID int64) error {
+	resp, err := c.client.Post(fmt.Sprintf("%s/dislike/%d/%d", c.baseURL, fromUserID, toUserID), "application/json", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil
+	case http.StatusTooManyRequests:
+		return ErrTooManySwipes
+	case http.StatusNotFound:
+		return ErrProfileNotFound
+	}
+	return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+}
+
+// Undo - отменяет последний свайп пользователя
+func (c *HTTPmatchServiseClient) Undo(userID int64) (*entity.Swipe, *entity.Quota, error) {
+	resp, err := c.client.Post(fmt.Sprintf("%s/swipes/%d/undo", c.baseURL, userID), "application/json", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusTooManyRequests:
+	case http.StatusNotFound:
+		return nil, nil, ErrNothingToUndo
+	default:
+		return nil, nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Swipe     *entity.Swipe `json:"swipe"`
+		Remaining *entity.Quota `json:"remaining"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, result.Remaining, ErrUndoLimitReached
+	}
+	return result.Swipe, result.Remaining, nil
+}
+
+// HasLiked - есть ли лайк fromUserID во входящих toUserID
+func (c *HTTPmatchServiseClient) HasLiked(fromUserID, toUserID int64) (bool, error) {
+	resp, err := c.client.Get(fmt.Sprintf("%s/like/%d/%d", c.baseURL, fromUserID, toUserID))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+}
+
+// IncomingLikes - кто лайкнул пользователя, страница начиная с offset
+func (c *HTTPmatchServiseClient) IncomingLikes(userID int64, offset, limit int) ([]entity.LikeItem, bool, error) {
+	var page struct {
+		Items   []entity.LikeItem `json:"items"`
+		HasMore bool              `json:"has_more"`
+	}
+	err := c.getList(fmt.Sprintf("%s/users/%d/likes/incoming?offset=%d&limit=%d", c.baseURL, userID, offset, limit), &page)
+	return page.Items, page.HasMore, err
+}
+
+// OutgoingLikes - кого лайкнул пользователь
+func (c *HTTPmatchServiseClient) OutgoingLikes(userID int64, offset, limit int) ([]entity.LikeItem, bool, error) {
+	var page struct {
+		Items   []entity.LikeItem `json:"items"`
+		HasMore bool              `json:"has_more"`
+	}
+	err := c.getList(fmt.Sprintf("%s/users/%d/likes/outgoing?offset=%d&limit=%d", c.baseURL, userID, offset, limit), &page)
+	return page.Items, page.HasMore, err
+}
+
+// Matches - пары пользователя
+func (c *HTTPmatchServiseClient) Matches(userID int64, offset, limit int) ([]entity.MatchItem, bool, error) {
+	var page struct {
+		Items   []entity.MatchItem `json:"items"`
+		HasMore bool               `json:"has_more"`
+	}
+	err := c.getList(fmt.Sprintf("%s/users/%d/matches?offset=%d&limit=%d", c.baseURL, userID, offset, limit), &page)
+	return page.Items, page.HasMore, err
+}
+
+// ErrMatchNotFound - пары нет, она уже завершена или пользователь в ней не участвует
+var ErrMatchNotFound = errors.New("match not found")
+
+// EndMatch - разрыв пары пользователем
+func (c *HTTPmatchServiseClient) EndMatch(matchID, userID int64) error {
+	return c.matchAction(http.MethodDelete, fmt.Sprintf("%s/matches/%d?user_id=%d", c.baseURL, matchID, userID))
+}
 
-	req, err := http.NewRequest(http.MethodPost, url, nil)
+// FollowUpMatch - пара продолжает общение и не должна истекать
+func (c *HTTPmatchServiseClient) FollowUpMatch(matchID, userID int64) error {
+	return c.matchAction(http.MethodPost, fmt.Sprintf("%s/matches/%d/followup?user_id=%d", c.baseURL, matchID, userID))
+}
+
+func (c *HTTPmatchServiseClient) matchAction(method, url string) error {
+	req, err := http.NewRequest(method, url, nil)
 	if err != nil {
 		return err
 	}
@@ -31,9 +224,24 @@ func (c *HTTPmatchServiseClient) LikeUser(fromUserID, toUserID int64) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrMatchNotFound
+	}
+	if resp.StatusCode != http.StatusNoContent {
 		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
-
 	return nil
 }
+
+func (c *HTTPmatchServiseClient) getList(url string, out interface{}) error {
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -3,14 +3,24 @@ package clientsUser
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"serviceBot/internal/entity"
+	"strconv"
+	"strings"
 )
 
+// ErrInvalidValue - serviceUser не принял значение поля анкеты
+var ErrInvalidValue = errors.New("invalid value")
+
+// ErrUserNotFound - анкеты с таким Telegram ID еще нет
+var ErrUserNotFound = errors.New("user not found")
+
 type HTTPUserServiseClient struct {
 	baseURL string
 	client  *http.Client
@@ -23,18 +33,20 @@ func NewHTTPUserServiseClient(baseURL string) *HTTPUserServiseClient {
 	}
 }
 
-func (c *HTTPUserServiseClient) CreateUser(name, city, gender, description string, age int, telegramID int64, file []byte, filename string) error {
+func (c *HTTPUserServiseClient) CreateUser(name, city, gender, description string, interestedIn []string, age int, telegramID int64, file []byte, filename, languageCode string) error {
 	var requestBody bytes.Buffer
 	writer := multipart.NewWriter(&requestBody)
 
 	// --- 1. Создаем JSON-объект ---
 	data := map[string]interface{}{
-		"name":        name,
-		"city":        city,
-		"gender":      gender,
-		"description": description,
-		"age":         age,
-		"telegram_id": telegramID,
+		"name":          name,
+		"city":          city,
+		"gender":        gender,
+		"interested_in": interestedIn,
+		"description":   description,
+		"age":           age,
+		"telegram_id":   telegramID,
+		"language_code": languageCode,
 	}
 
 	jsonData, err := json.Marshal(data)
@@ -141,10 +153,26 @@ func (c *HTTPUserServiseClient) Delete(id int64) error {
 	return nil
 }
 
-func (c *HTTPUserServiseClient) SearchUser(MinAge, MaxAge int, City, Gender string) ([]entity.User, error) {
-	url := fmt.Sprintf("%s/users/search?min_age=%d&max_age=%d&city=%s&gender=%s", c.baseURL, MinAge, MaxAge, City, Gender)
+// SearchUser - поиск анкет; ExcludeFor - ID того, кто смотрит выдачу (его анкета и скрытые им не вернутся)
+func (c *HTTPUserServiseClient) SearchUser(MinAge, MaxAge int, City string, Genders []string, InterestedIn string, ExcludeFor int64) ([]entity.User, error) {
+	query := url.Values{}
+	query.Set("min_age", strconv.Itoa(MinAge))
+	query.Set("max_age", strconv.Itoa(MaxAge))
+	query.Set("city", City)
+	if len(Genders) > 0 {
+		query.Set("gender", strings.Join(Genders, ","))
+	}
+	if InterestedIn != "" {
+		query.Set("interested_in", InterestedIn)
+	}
+	if ExcludeFor > 0 {
+		// Выдача ранжируется для того, кто ее смотрит
+		query.Set("exclude_for", strconv.FormatInt(ExcludeFor, 10))
+		query.Set("sort", "rank")
+	}
+	searchURL := fmt.Sprintf("%s/users/search?%s", c.baseURL, query.Encode())
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest("GET", searchURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -166,3 +194,220 @@ func (c *HTTPUserServiseClient) SearchUser(MinAge, MaxAge int, City, Gender stri
 
 	return users, nil
 }
+
+// TouchActivity отмечает, что пользователь только что был активен
+func (c *HTTPUserServiseClient) TouchActivity(userID int64) error {
+	url := fmt.Sprintf("%s/users/%d/activity", c.baseURL, userID)
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ListAttributes возвращает реестр расширенных атрибутов анкеты
+func (c *HTTPUserServiseClient) ListAttributes() ([]entity.AttributeDefinition, error) {
+	url := fmt.Sprintf("%s/attributes", c.baseURL)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var defs []entity.AttributeDefinition
+	if err := json.NewDecoder(resp.Body).Decode(&defs); err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return defs, nil
+}
+
+// SetAttribute сохраняет значения атрибута анкеты. ErrInvalidValue означает,
+// что serviceUser отклонил значение.
+func (c *HTTPUserServiseClient) SetAttribute(userID int64, key string, values []string) error {
+	body, err := json.Marshal(map[string][]string{"values": values})
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/users/%d/attributes/%s", c.baseURL, userID, key)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest {
+		return ErrInvalidValue
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SetLanguage сохраняет в анкете язык интерфейса из Telegram.
+// ErrUserNotFound означает, что пользователь еще не зарегистрирован
+func (c *HTTPUserServiseClient) SetLanguage(userID int64, languageCode string) error {
+	body, err := json.Marshal(map[string]string{"language_code": languageCode})
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/users/%d/language", c.baseURL, userID)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrUserNotFound
+	}
+	if resp.StatusCode == http.StatusBadRequest {
+		return ErrInvalidValue
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Reactivate возвращает анкету в выдачу после того, как пользователь разблокировал бота
+func (c *HTTPUserServiseClient) Reactivate(userID int64) error {
+	url := fmt.Sprintf("%s/users/%d/reactivate", c.baseURL, userID)
+	resp, err := c.client.Post(url, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NotificationSettings возвращает настройки уведомлений пользователя
+func (c *HTTPUserServiseClient) NotificationSettings(userID int64) (*entity.NotificationSettings, error) {
+	url := fmt.Sprintf("%s/users/%d/notification-settings", c.baseURL, userID)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var settings entity.NotificationSettings
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return &settings, nil
+}
+
+// SaveNotificationSettings сохраняет настройки уведомлений целиком. ErrInvalidValue означает,
+// что serviceUser отклонил настройки.
+func (c *HTTPUserServiseClient) SaveNotificationSettings(userID int64, settings entity.NotificationSettings) error {
+	body, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/users/%d/notification-settings", c.baseURL, userID)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest {
+		return ErrInvalidValue
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NextQuestions возвращает вопросы анкеты совместимости, на которые пользователь еще не ответил
+func (c *HTTPUserServiseClient) NextQuestions(userID int64, limit int) ([]entity.Question, error) {
+	url := fmt.Sprintf("%s/users/%d/questions/next?limit=%d", c.baseURL, userID, limit)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var questions []entity.Question
+	if err := json.NewDecoder(resp.Body).Decode(&questions); err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return questions, nil
+}
+
+// AnswerQuestion сохраняет ответ на вопрос анкеты совместимости. ErrInvalidValue означает,
+// что serviceUser отклонил ответ.
+func (c *HTTPUserServiseClient) AnswerQuestion(userID int64, answer entity.QuestionAnswer) error {
+	body, err := json.Marshal(answer)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/users/%d/questions/%d", c.baseURL, userID, answer.QuestionID)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusNotFound {
+		return ErrInvalidValue
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
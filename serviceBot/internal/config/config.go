@@ -6,6 +6,13 @@ type Config struct {
 	TELEGRAM_BOT_TOKEN string
 	USER_SERVICE       string
 	MATCH_SERVICE      string
+	// INTERNAL_ADDR - адрес внутреннего API для serviceNotification
+	INTERNAL_ADDR string
+	// UPDATES_MODE - polling или webhook
+	UPDATES_MODE   string
+	WEBHOOK_URL    string
+	WEBHOOK_SECRET string
+	WEBHOOK_LISTEN string
 }
 
 func NewConfig() *Config {
@@ -13,6 +20,11 @@ func NewConfig() *Config {
 		TELEGRAM_BOT_TOKEN: getEnv("TELEGRAM_BOT_TOKEN", ""),
 		USER_SERVICE:       getEnv("USER_SERVICE", ""),
 		MATCH_SERVICE:      getEnv("MATCH_SERVICE", ""),
+		INTERNAL_ADDR:      getEnv("INTERNAL_ADDR", ":8083"),
+		UPDATES_MODE:       getEnv("UPDATES_MODE", "polling"),
+		WEBHOOK_URL:        getEnv("WEBHOOK_URL", ""),
+		WEBHOOK_SECRET:     getEnv("WEBHOOK_SECRET", ""),
+		WEBHOOK_LISTEN:     getEnv("WEBHOOK_LISTEN", ":8443"),
 	}
 }
 
@@ -0,0 +1,86 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"serviceBot/internal/entity"
+	"serviceBot/internal/usecase"
+	"strconv"
+
+	"gopkg.in/telebot.v4"
+)
+
+// HandoffSender - отправка уведомлений, кнопки которых обрабатывает бот
+type HandoffSender interface {
+	SendHandoff(h entity.Handoff) (*entity.SentMessage, error)
+}
+
+// InternalAPI - внутренний HTTP API бота для других сервисов.
+// Наружу не публикуется: порт доступен только в сети backend2
+type InternalAPI struct {
+	sender HandoffSender
+}
+
+func NewInternalAPI(sender HandoffSender) *InternalAPI {
+	return &InternalAPI{sender: sender}
+}
+
+// Handler - маршруты внутреннего API
+func (a *InternalAPI) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /internal/handoffs", a.handoff)
+	return mux
+}
+
+// Start - запускает внутренний API; пустой адрес отключает его
+func (a *InternalAPI) Start(addr string) {
+	if addr == "" {
+		log.Println("Внутренний API бота отключен")
+		return
+	}
+	log.Println("Внутренний API бота слушает", addr)
+	if err := http.ListenAndServe(addr, a.Handler()); err != nil {
+		log.Fatalf("Ошибка внутреннего API бота: %v", err)
+	}
+}
+
+func (a *InternalAPI) handoff(w http.ResponseWriter, r *http.Request) {
+	var h entity.Handoff
+	if err := json.NewDecoder(r.Body).Decode(&h); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	sent, err := a.sender.SendHandoff(h)
+	var flood telebot.FloodError
+	var tgErr *telebot.Error
+	switch {
+	case errors.As(err, &flood):
+		// Отдаем retry_after Telegram, чтобы очередь serviceNotification подождала
+		w.Header().Set("Retry-After", strconv.Itoa(flood.RetryAfter))
+		writeJSON(w, http.StatusTooManyRequests, map[string]any{"error": err.Error(), "retry_after": flood.RetryAfter})
+	case errors.Is(err, usecase.ErrInvalidHandoff):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrBotNotStarted):
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+	case errors.As(err, &tgErr):
+		// description нужен serviceNotification, чтобы отличить блокировку бота от сбоя
+		log.Println("Ошибка отправки уведомления:", err)
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error(), "description": tgErr.Description})
+	case err != nil:
+		log.Println("Ошибка отправки уведомления:", err)
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+	default:
+		writeJSON(w, http.StatusOK, sent)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Println("Ошибка записи ответа:", err)
+	}
+}
@@ -0,0 +1,179 @@
+package delivery
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/telebot.v4"
+)
+
+// Режимы получения обновлений от Telegram
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+)
+
+// secretHeader - заголовок, в котором Telegram присылает secret_token вебхука
+const secretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// recentUpdatesLimit - сколько последних update_id помнить для отсечения повторов
+const recentUpdatesLimit = 10000
+
+var ErrWebhookConfig = errors.New("для режима webhook нужны WEBHOOK_URL, WEBHOOK_SECRET и WEBHOOK_LISTEN")
+
+// NewPoller - источник обновлений по режиму из конфига
+func NewPoller(mode, publicURL, secret, listen string) (telebot.Poller, error) {
+	switch mode {
+	case "", ModePolling:
+		return &Polling{LongPoller: telebot.LongPoller{Timeout: 10 * time.Second}}, nil
+	case ModeWebhook:
+		return NewWebhook(publicURL, secret, listen)
+	default:
+		return nil, errors.New("неизвестный режим получения обновлений: " + mode)
+	}
+}
+
+// Polling - long polling. Перед запуском снимает вебхук, иначе Telegram
+// отклоняет getUpdates, если бот раньше работал в режиме webhook
+type Polling struct {
+	telebot.LongPoller
+}
+
+func (p *Polling) Poll(b *telebot.Bot, dest chan telebot.Update, stop chan struct{}) {
+	if err := b.RemoveWebhook(); err != nil {
+		log.Println("Ошибка deleteWebhook:", err)
+	}
+	p.LongPoller.Poll(b, dest, stop)
+}
+
+// Webhook - получение обновлений через вебхук. При запуске регистрирует URL
+// через setWebhook; при остановке вебхук не снимается, чтобы другие экземпляры
+// бота продолжали получать обновления, а Telegram копил их до перезапуска.
+// Запросы без верного secret_token отклоняются, повторная доставка
+// обновления с тем же update_id не обрабатывается
+type Webhook struct {
+	publicURL string
+	secret    string
+	listen    string
+
+	ready chan struct{}
+	dest  chan<- telebot.Update
+	seen  *recentUpdates
+}
+
+// NewWebhook - без адреса listen Telegram слал бы обновления на URL, который
+// никто не слушает, поэтому пустой адрес - ошибка конфигурации
+func NewWebhook(publicURL, secret, listen string) (*Webhook, error) {
+	if publicURL == "" || secret == "" || listen == "" {
+		return nil, ErrWebhookConfig
+	}
+	return &Webhook{
+		publicURL: publicURL,
+		secret:    secret,
+		listen:    listen,
+		ready:     make(chan struct{}),
+		seen:      newRecentUpdates(recentUpdatesLimit),
+	}, nil
+}
+
+func (w *Webhook) Poll(b *telebot.Bot, dest chan telebot.Update, stop chan struct{}) {
+	err := b.SetWebhook(&telebot.Webhook{
+		SecretToken: w.secret,
+		Endpoint:    &telebot.WebhookEndpoint{PublicURL: w.publicURL},
+	})
+	if err != nil {
+		log.Fatalf("Ошибка setWebhook: %v", err)
+	}
+	w.dest = dest
+	close(w.ready)
+
+	server := &http.Server{Addr: w.listen, Handler: w}
+	go func() {
+		log.Println("Вебхук слушает", w.listen)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Ошибка сервера вебхука: %v", err)
+		}
+	}()
+	<-stop
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Println("Ошибка остановки сервера вебхука:", err)
+	}
+}
+
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(secretHeader)), []byte(w.secret)) != 1 {
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	select {
+	case <-w.ready:
+	default:
+		// setWebhook еще не выполнен, Telegram повторит доставку
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var update telebot.Update
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !w.seen.add(update.ID) {
+		rw.WriteHeader(http.StatusOK)
+		return
+	}
+	select {
+	case w.dest <- update:
+		rw.WriteHeader(http.StatusOK)
+	case <-r.Context().Done():
+		// Обновление не принято: разрешаем повторную доставку
+		w.seen.forget(update.ID)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+// recentUpdates - последние принятые update_id, самые старые вытесняются
+type recentUpdates struct {
+	mu    sync.Mutex
+	ids   map[int]struct{}
+	order []int
+	limit int
+}
+
+func newRecentUpdates(limit int) *recentUpdates {
+	return &recentUpdates{ids: make(map[int]struct{}), limit: limit}
+}
+
+// add - запоминает update_id; false, если он уже был
+func (r *recentUpdates) add(id int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.ids[id]; ok {
+		return false
+	}
+	r.ids[id] = struct{}{}
+	r.order = append(r.order, id)
+	if len(r.order) > r.limit {
+		delete(r.ids, r.order[0])
+		r.order = r.order[1:]
+	}
+	return true
+}
+
+func (r *recentUpdates) forget(id int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.ids, id)
+}
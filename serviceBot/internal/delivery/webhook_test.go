@@ -0,0 +1,188 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/telebot.v4"
+)
+
+// fakeBotAPI - поддельный Bot API: запоминает вызванные методы и их параметры
+type fakeBotAPI struct {
+	mu    sync.Mutex
+	calls map[string]map[string]any
+}
+
+func newFakeBotAPI(t *testing.T) (*fakeBotAPI, string) {
+	api := &fakeBotAPI{calls: make(map[string]map[string]any)}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return api, server.URL
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	params := make(map[string]any)
+	json.NewDecoder(r.Body).Decode(&params)
+	f.mu.Lock()
+	f.calls[method] = params
+	f.mu.Unlock()
+
+	switch method {
+	case "getMe":
+		fmt.Fprint(w, `{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"test"}}`)
+	case "getUpdates":
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `{"ok":true,"result":[]}`)
+	default:
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}
+}
+
+func (f *fakeBotAPI) call(method string) (map[string]any, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	params, ok := f.calls[method]
+	return params, ok
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("условие не выполнилось за 2 секунды")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func startBot(t *testing.T, apiURL string, poller telebot.Poller) chan string {
+	t.Helper()
+	b, err := telebot.NewBot(telebot.Settings{URL: apiURL, Token: "token", Poller: poller})
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := make(chan string, 10)
+	b.Handle(telebot.OnText, func(ctx telebot.Context) error {
+		received <- ctx.Text()
+		return nil
+	})
+	go b.Start()
+	t.Cleanup(b.Stop)
+	return received
+}
+
+func postUpdate(t *testing.T, h http.Handler, secret string, id int, text string) int {
+	t.Helper()
+	body := fmt.Sprintf(`{"update_id":%d,"message":{"message_id":%d,"date":0,"chat":{"id":5,"type":"private"},"from":{"id":5},"text":%q}}`, id, id, text)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if secret != "" {
+		req.Header.Set(secretHeader, secret)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhook(t *testing.T) {
+	api, apiURL := newFakeBotAPI(t)
+	webhook, err := NewWebhook("https://bot.example.com/telegram", "s3cret", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := startBot(t, apiURL, webhook)
+
+	waitFor(t, func() bool {
+		_, ok := api.call("setWebhook")
+		return ok
+	})
+	params, _ := api.call("setWebhook")
+	if params["url"] != "https://bot.example.com/telegram" || params["secret_token"] != "s3cret" {
+		t.Fatalf("неверные параметры setWebhook: %v", params)
+	}
+	waitFor(t, func() bool {
+		select {
+		case <-webhook.ready:
+			return true
+		default:
+			return false
+		}
+	})
+
+	t.Run("secret token", func(t *testing.T) {
+		if code := postUpdate(t, webhook, "", 1, "hello"); code != http.StatusUnauthorized {
+			t.Fatalf("без secret token: код %d", code)
+		}
+		if code := postUpdate(t, webhook, "wrong", 1, "hello"); code != http.StatusUnauthorized {
+			t.Fatalf("с чужим secret token: код %d", code)
+		}
+		select {
+		case text := <-received:
+			t.Fatalf("обработано обновление без secret token: %q", text)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("dedupe", func(t *testing.T) {
+		for _, update := range []struct {
+			id   int
+			text string
+		}{{1, "hello"}, {1, "hello"}, {2, "again"}} {
+			if code := postUpdate(t, webhook, "s3cret", update.id, update.text); code != http.StatusOK {
+				t.Fatalf("update %d: код %d", update.id, code)
+			}
+		}
+		var got []string
+		timeout := time.After(200 * time.Millisecond)
+	collect:
+		for {
+			select {
+			case text := <-received:
+				got = append(got, text)
+			case <-timeout:
+				break collect
+			}
+		}
+		// Бот обрабатывает обновления параллельно, порядок не важен
+		sort.Strings(got)
+		if strings.Join(got, ",") != "again,hello" {
+			t.Fatalf("обработаны обновления %v, ожидались hello и again по одному разу", got)
+		}
+	})
+}
+
+func TestPolling_DeletesWebhook(t *testing.T) {
+	api, apiURL := newFakeBotAPI(t)
+	poller, err := NewPoller(ModePolling, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	startBot(t, apiURL, poller)
+
+	waitFor(t, func() bool {
+		_, deleted := api.call("deleteWebhook")
+		_, polled := api.call("getUpdates")
+		return deleted && polled
+	})
+}
+
+func TestNewPoller(t *testing.T) {
+	if _, err := NewPoller(ModeWebhook, "https://bot.example.com/telegram", "", ":8443"); !errors.Is(err, ErrWebhookConfig) {
+		t.Fatalf("вебхук без secret token: %v", err)
+	}
+	if _, err := NewPoller(ModeWebhook, "https://bot.example.com/telegram", "s3cret", ""); !errors.Is(err, ErrWebhookConfig) {
+		t.Fatalf("вебхук без адреса сервера: %v", err)
+	}
+	if _, err := NewPoller("sse", "", "", ""); err == nil {
+		t.Fatal("неизвестный режим принят")
+	}
+}
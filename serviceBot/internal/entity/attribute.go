@@ -0,0 +1,20 @@
+package entity
+
+// Типы атрибутов анкеты из реестра serviceUser
+const (
+	AttributeInt       = "int"
+	AttributeString    = "string"
+	AttributeEnum      = "enum"
+	AttributeMultiEnum = "multi_enum"
+)
+
+type AttributeDefinition struct {
+	Key           string            `json:"key"`
+	Label         string            `json:"label"`
+	Type          string            `json:"type"`
+	AllowedValues []string          `json:"allowed_values,omitempty"`
+	ValueLabels   map[string]string `json:"value_labels,omitempty"`
+	Min           *int              `json:"min,omitempty"`
+	Max           *int              `json:"max,omitempty"`
+	Searchable    bool              `json:"searchable"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+// Виды интерактивных уведомлений, которые serviceNotification передает боту
+const (
+	// HandoffLiker - уведомление о лайке с кнопкой «Показать анкету»
+	HandoffLiker = "liker"
+	// HandoffMatch - уведомление о новой паре с кнопкой «Мои пары»
+	HandoffMatch = "match"
+)
+
+// Handoff - уведомление, кнопки которого обрабатывает serviceBot.
+// TargetID - второй участник: тот, кто лайкнул, или новая пара
+type Handoff struct {
+	UserID    int64  `json:"user_id"`
+	Kind      string `json:"kind"`
+	TargetID  int64  `json:"target_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode,omitempty"`
+	// LanguageCode - язык получателя из анкеты, на нем подписываются кнопки
+	LanguageCode string `json:"language_code,omitempty"`
+}
+
+// SentMessage - отправленное сообщение, по нему уведомление можно отозвать
+type SentMessage struct {
+	ChatID    int64 `json:"chat_id"`
+	MessageID int   `json:"message_id"`
+}
@@ -1,7 +1,52 @@
 package entity
 
+import "time"
+
 type Match struct {
 	FromUserID int64
 	ToUserID   int64
 	CreatedAt  int64
 }
+
+// Like - лайк с необязательным комментарием и целью (фото или вопрос-карточка)
+type Like struct {
+	FromUserID int64  `json:"-"`
+	ToUserID   int64  `json:"-"`
+	Target     string `json:"target,omitempty"`
+	PromptID   *int   `json:"prompt_id,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+	Super      bool   `json:"super,omitempty"`
+}
+
+// Swipe - отмененный свайп: кому и какой (like, superlike, dislike)
+type Swipe struct {
+	ToUserID int64  `json:"to_user_id"`
+	Kind     string `json:"kind"`
+}
+
+// Quota - остаток дневных лайков и суперлайков
+type Quota struct {
+	Likes      int       `json:"likes"`
+	SuperLikes int       `json:"super_likes"`
+	Undos      int       `json:"undos"`
+	ResetsAt   time.Time `json:"resets_at"`
+}
+
+// LikeItem - входящий или исходящий лайк; UserID - второй участник
+type LikeItem struct {
+	UserID    int64     `json:"user_id"`
+	Target    string    `json:"target,omitempty"`
+	PromptID  *int      `json:"prompt_id,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Answered  bool      `json:"answered"`
+	Profile   *User     `json:"profile,omitempty"`
+}
+
+// MatchItem - пара с другим пользователем
+type MatchItem struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Profile   *User     `json:"profile,omitempty"`
+}
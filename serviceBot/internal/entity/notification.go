@@ -0,0 +1,20 @@
+package entity
+
+// Режимы доставки уведомлений
+const (
+	NotifyInstant = "instant"
+	NotifyDigest  = "digest"
+)
+
+// NotificationTypes - типы уведомлений, которые можно отключить в /settings
+var NotificationTypes = []string{"like", "superlike", "match", "match_expiring", "inactivity_reminder"}
+
+// NotificationSettings - настройки уведомлений пользователя в serviceUser
+type NotificationSettings struct {
+	Disabled []string `json:"disabled"`
+	// QuietFrom и QuietTo - тихие часы по местному времени; nil - тихих часов нет
+	QuietFrom *int   `json:"quiet_from,omitempty"`
+	QuietTo   *int   `json:"quiet_to,omitempty"`
+	TimeZone  string `json:"time_zone"`
+	Mode      string `json:"mode"`
+}
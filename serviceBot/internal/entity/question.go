@@ -0,0 +1,17 @@
+package entity
+
+// Question - вопрос анкеты совместимости с вариантами ответа
+type Question struct {
+	ID      int      `json:"id"`
+	Text    string   `json:"text"`
+	Options []string `json:"options"`
+}
+
+// QuestionAnswer - свой вариант ответа, варианты, которые устроят у партнера,
+// и важность вопроса от 0 (неважно) до 4 (обязательно). Варианты - индексы в Question.Options
+type QuestionAnswer struct {
+	QuestionID int   `json:"question_id"`
+	Answer     int   `json:"answer"`
+	Acceptable []int `json:"acceptable"`
+	Importance int   `json:"importance"`
+}
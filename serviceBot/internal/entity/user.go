@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type User struct {
 	ID          int    `json:"id"`
 	TelegramID  int64  `json:"telegram_id"`
@@ -9,4 +11,20 @@ type User struct {
 	Gender      string `json:"gender,omitempty"`
 	Description string `json:"description"`
 	Photo       string `json:"photo"`
+
+	InterestedIn []string            `json:"interested_in"`
+	Attributes   map[string][]string `json:"attributes,omitempty"`
+	LastActiveAt time.Time           `json:"last_active_at"`
+	// UnreachableAt - serviceNotification не смог доставить сообщение (бот заблокирован)
+	UnreachableAt *time.Time     `json:"unreachable_at,omitempty"`
+	Prompts       []PromptAnswer `json:"prompts,omitempty"`
+	// Compatibility - процент совместимости с тем, кто смотрит ленту; nil, если общих вопросов нет
+	Compatibility *int `json:"compatibility,omitempty"`
+}
+
+// PromptAnswer - ответ пользователя на вопрос-карточку
+type PromptAnswer struct {
+	PromptID int    `json:"prompt_id"`
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
 }
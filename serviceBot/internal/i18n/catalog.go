@@ -0,0 +1,92 @@
+// Package i18n - каталог текстов бота на text/template с сообщениями по языкам
+// и правилами множественного числа
+package i18n
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// DefaultLocale - язык анкет, для которых language_code неизвестен
+const DefaultLocale = "ru"
+
+// Args - данные для шаблона сообщения
+type Args map[string]any
+
+// Catalog - шаблоны сообщений по языкам. Если сообщения нет в языке
+// пользователя, берется DefaultLocale
+type Catalog struct {
+	locales map[string]*template.Template
+}
+
+// New - каталог из встроенных наборов сообщений
+func New() *Catalog {
+	return NewCatalog(bundles)
+}
+
+// NewCatalog - каталог из наборов locale -> ключ -> шаблон. Ошибка в шаблоне -
+// ошибка программиста, поэтому паникует при старте
+func NewCatalog(messages map[string]map[string]string) *Catalog {
+	c := &Catalog{locales: make(map[string]*template.Template, len(messages))}
+	for locale, bundle := range messages {
+		root := template.New(locale).Funcs(template.FuncMap{"plural": pluralFunc(locale)})
+		for key, text := range bundle {
+			template.Must(root.New(key).Parse(text))
+		}
+		c.locales[locale] = root
+	}
+	return c
+}
+
+// Locales - языки каталога по алфавиту
+func (c *Catalog) Locales() []string {
+	locales := make([]string, 0, len(c.locales))
+	for locale := range c.locales {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Text - сообщение key на языке locale. Неизвестный ключ возвращается как есть,
+// чтобы пропущенный перевод был виден, но не ломал ответ
+func (c *Catalog) Text(locale, key string, args Args) string {
+	t := c.lookup(locale, key)
+	if t == nil {
+		log.Printf("i18n: нет сообщения %q", key)
+		return key
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, args); err != nil {
+		log.Printf("i18n: ошибка шаблона %q (%s): %v", key, locale, err)
+		return key
+	}
+	return sb.String()
+}
+
+func (c *Catalog) lookup(locale, key string) *template.Template {
+	if root, ok := c.locales[locale]; ok {
+		if t := root.Lookup(key); t != nil {
+			return t
+		}
+	}
+	if root, ok := c.locales[DefaultLocale]; ok {
+		return root.Lookup(key)
+	}
+	return nil
+}
+
+// Locale - язык каталога по language_code из Telegram: "ru-RU" -> "ru".
+// Пустой код - DefaultLocale, любой другой неизвестный язык - английский
+func Locale(languageCode string) string {
+	if languageCode == "" {
+		return DefaultLocale
+	}
+	lang, _, _ := strings.Cut(strings.ToLower(languageCode), "-")
+	if _, ok := bundles[lang]; ok {
+		return lang
+	}
+	return "en"
+}
@@ -0,0 +1,80 @@
+package i18n
+
+import "testing"
+
+func TestCatalog_Plural(t *testing.T) {
+	c := NewCatalog(map[string]map[string]string{
+		"ru": {"likes": `{{.N}} {{plural .N "лайк" "лайка" "лайков"}}`},
+		"en": {"likes": `{{.N}} {{plural .N "like" "likes"}}`},
+	})
+
+	tests := []struct {
+		locale string
+		n      int
+		want   string
+	}{
+		{"ru", 1, "1 лайк"},
+		{"ru", 2, "2 лайка"},
+		{"ru", 5, "5 лайков"},
+		{"ru", 11, "11 лайков"},
+		{"ru", 21, "21 лайк"},
+		{"ru", 24, "24 лайка"},
+		{"ru", 112, "112 лайков"},
+		{"ru", 0, "0 лайков"},
+		{"en", 1, "1 like"},
+		{"en", 0, "0 likes"},
+		{"en", 21, "21 likes"},
+	}
+	for _, tt := range tests {
+		if got := c.Text(tt.locale, "likes", Args{"N": tt.n}); got != tt.want {
+			t.Errorf("Text(%s, %d) = %q, want %q", tt.locale, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCatalog_Fallback(t *testing.T) {
+	c := NewCatalog(map[string]map[string]string{
+		"ru": {"hello": "Привет", "only_ru": "Только по-русски"},
+		"en": {"hello": "Hello"},
+	})
+
+	if got := c.Text("en", "hello", nil); got != "Hello" {
+		t.Errorf("en hello = %q", got)
+	}
+	if got := c.Text("en", "only_ru", nil); got != "Только по-русски" {
+		t.Errorf("missing en message should fall back to ru, got %q", got)
+	}
+	if got := c.Text("en", "missing", nil); got != "missing" {
+		t.Errorf("unknown key should be returned as is, got %q", got)
+	}
+}
+
+func TestLocale(t *testing.T) {
+	tests := map[string]string{
+		"":      DefaultLocale,
+		"ru":    "ru",
+		"ru-RU": "ru",
+		"EN-us": "en",
+		"de":    "en",
+	}
+	for code, want := range tests {
+		if got := Locale(code); got != want {
+			t.Errorf("Locale(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestBundles_SameKeys(t *testing.T) {
+	for locale, bundle := range bundles {
+		for key := range bundles[DefaultLocale] {
+			if _, ok := bundle[key]; !ok {
+				t.Errorf("%s: нет сообщения %q", locale, key)
+			}
+		}
+		for key := range bundle {
+			if _, ok := bundles[DefaultLocale][key]; !ok {
+				t.Errorf("%s: лишнее сообщение %q", locale, key)
+			}
+		}
+	}
+}
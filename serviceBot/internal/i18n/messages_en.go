@@ -0,0 +1,153 @@
+package i18n
+
+var en = map[string]string{
+	"error":          "Something went wrong, please try again :(",
+	"need_profile":   "Create your profile first: /start",
+	"no_such_option": "There is no such option",
+	"photo_error":    "Couldn't load the photo",
+	"stale_button":   "This button is out of date",
+
+	"menu":   "1. Browse profiles 🚀.\n2. My profile 📱.\n3. Edit profile.\n4. Who liked me 💌.\n5. My likes.\n6. My matches 💞.",
+	"menu.1": "🚀 Browse profiles",
+	"menu.2": "📱 My profile",
+	"menu.3": "✏️ Edit profile",
+	"menu.4": "💌 Who liked me",
+	"menu.5": "❤ My likes",
+	"menu.6": "💞 My matches",
+
+	"search.error":  "Something went wrong! Please try again",
+	"search.found":  `We found people you may like, tap "Start"`,
+	"search.empty":  "We couldn't find anyone for you yet :(",
+	"search.start":  "Start",
+	"browse.empty":  "No more profiles :(",
+	"browse.paused": "Browsing is paused. Resume with /browse",
+
+	"start.greeting": "Hi, this is a dating bot!",
+	"start.create":   "Let's create your profile!",
+	"start.profile":  "This is how your profile looks:",
+
+	"registration.name":             "What's your name?",
+	"registration.age":              "Now tell me your age:",
+	"registration.age_invalid":      "Age must be a number!",
+	"registration.city":             "Which city do you live in?",
+	"registration.gender":           "Choose your gender:",
+	"registration.gender_invalid":   "There is no such gender option!",
+	"registration.interests":        `Who are you interested in? You can pick several options, then tap "Done"`,
+	"registration.interests_empty":  "Pick at least one option",
+	"registration.interest_toggled": `Got it. Pick more or tap "Done"`,
+	"registration.description":      "Write a short description for your profile:",
+	"registration.photo":            "Send a photo for your profile:",
+	"registration.photo_error":      "Couldn't get the photo. Please send it again",
+	"registration.file_error":       "Couldn't get the file. Please try again.",
+	"registration.file_path_error":  "Error: the file path is missing.",
+	"registration.file_read_error":  "Couldn't read the file. Please try again.",
+	"registration.save_error":       "Couldn't save your profile. Please try again.",
+	"registration.done":             "Your profile is ready! 🎉\nTell more about yourself: /details",
+	"registration.your_profile":     "Your profile:",
+
+	"gender.male":        "Man",
+	"gender.female":      "Woman",
+	"gender.nonbinary":   "Non-binary",
+	"gender.genderfluid": "Genderfluid",
+	"gender.agender":     "Agender",
+
+	"cmd.start":     "Start or show your profile",
+	"cmd.help":      "List of commands",
+	"cmd.profile":   "My profile",
+	"cmd.browse":    "Browse profiles",
+	"cmd.matches":   "My matches",
+	"cmd.settings":  "Settings",
+	"cmd.pause":     "Pause browsing",
+	"cmd.delete":    "Delete profile",
+	"cmd.details":   "Profile details",
+	"cmd.questions": "Compatibility questions",
+	"help.title":    "Bot commands:",
+	"settings":      "Profile settings:\n/details - tell more about yourself\n/questions - compatibility questions\n/pause - pause browsing\n/delete - delete your profile",
+
+	"notify.button":                   "🔔 Notifications",
+	"notify.title":                    "Notifications\nMode: {{.Mode}}\nQuiet hours: {{.Quiet}}\nTime zone: {{.Zone}}\n\nTap a notification type to turn it on or off. In digest mode notifications arrive as one message every few hours.",
+	"notify.type.like":                "Likes",
+	"notify.type.superlike":           "Super likes",
+	"notify.type.match":               "Matches",
+	"notify.type.match_expiring":      "Expiring matches",
+	"notify.type.inactivity_reminder": "Reminders",
+	"notify.mode.instant":             "instant",
+	"notify.mode.digest":              "digest",
+	"notify.mode.button":              "📬 Mode: {{.Mode}}",
+	"notify.quiet.off":                "off",
+	"notify.quiet.hours":              `{{printf "%02d" .From}}:00–{{printf "%02d" .To}}:00`,
+	"notify.quiet.button":             "🌙 Quiet hours: {{.Quiet}}",
+	"notify.zone.choose":              "Choose your time zone:",
+	"notify.done":                     "Done",
+	"notify.saved":                    "Notification settings saved",
+
+	"delete.confirm":   "Delete your profile? Your likes and matches will be gone, this can't be undone.",
+	"delete.yes":       "🗑 Delete",
+	"delete.no":        "Cancel",
+	"delete.cancelled": "Deletion cancelled",
+	"delete.done":      "Your profile was deleted. Create a new one: /start",
+
+	"handoff.liker":       "👀 Show profile",
+	"handoff.matches":     "💞 My matches",
+	"handoff.unavailable": "This profile is no longer available",
+
+	"swipe.too_fast":        "Too fast! Take a minute and look at the profile more closely 🙂",
+	"swipe.comment":         "Write a short message for your like (no links):",
+	"swipe.comment_invalid": "The message is too long or contains a link, please try again:",
+	"swipe.comment_retry":   "Then send your message again:",
+
+	"quota.left":       `Left for today: {{.Likes}} {{plural .Likes "like" "likes"}} and {{.SuperLikes}} {{plural .SuperLikes "super like" "super likes"}}.`,
+	"quota.likes_out":  "You're out of likes for today.",
+	"quota.supers_out": "You're out of super likes for today.",
+	"quota.tomorrow":   "Come back tomorrow!",
+	"quota.resets_at":  "New ones arrive at {{.Time}}.",
+
+	"undo.nothing":    "Nothing to undo: the last swipe was already undone or too much time has passed.",
+	"undo.out":        "You're out of undos for today.",
+	"undo.no_profile": "Swipe undone, but the profile couldn't be loaded.",
+	"undo.done":       `Swipe undone. {{.Undos}} {{plural .Undos "undo" "undos"}} left for today.`,
+
+	"button.done": "Done",
+	"button.skip": "Skip",
+	"button.menu": "Menu",
+
+	"card.compatibility":   "💞 Compatibility: {{.Percent}}%",
+	"card.active_recently": "active recently",
+	"card.active_week":     "active this week",
+
+	"details.empty":        "Nothing to fill in yet",
+	"details.invalid":      "That value doesn't fit, please try again",
+	"details.pick_or_skip": `Pick at least one option or tap "Skip"`,
+	"details.number":       "{{.Label}}? Enter a number",
+	"details.number_range": "{{.Label}}? Enter a number from {{.Min}} to {{.Max}}",
+	"details.multi":        `{{.Label}}? You can pick several, then tap "Done"`,
+	"details.done":         "Done! Your profile is complete 🎉",
+
+	"list.incoming":   "💌 Who liked you:",
+	"list.outgoing":   "❤ You liked:",
+	"list.matches":    "💞 Your matches:",
+	"list.mutual":     " — mutual 💞",
+	"list.match_line": "{{.Profile}} — <a href=\"tg://user?id={{.UserID}}\">message</a>\n   keep: {{.Keep}}, unmatch: {{.Unmatch}}",
+	"list.profile_id": "• profile {{.ID}}",
+	"list.empty":      "Nothing here yet",
+	"list.end":        "No one else",
+	"list.next":       "Next ➡️",
+
+	"match.not_found": "This match doesn't exist or has already ended",
+	"match.ended":     "Unmatched. You won't see each other in the feed anymore.",
+	"match.kept":      "Great! The match is saved and won't expire 💞",
+
+	"questions.all_answered":    "You've answered all the questions! Compatibility is already shown on profiles 💞",
+	"questions.acceptable":      `Which partner answers work for you? You can pick several, then tap "Done"`,
+	"questions.pick_or_any":     `Pick at least one option or tap "Any"`,
+	"questions.importance":      "How important is this to you?",
+	"questions.pick_importance": "Pick the importance with a button",
+	"questions.saved":           "Answers saved! Answer a few more questions?",
+	"questions.any":             "Any",
+	"questions.more":            "More questions",
+	"questions.importance.0":    "Irrelevant",
+	"questions.importance.1":    "A little",
+	"questions.importance.2":    "Important",
+	"questions.importance.3":    "Very important",
+	"questions.importance.4":    "Mandatory",
+}
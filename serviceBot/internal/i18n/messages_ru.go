@@ -0,0 +1,159 @@
+package i18n
+
+// bundles - встроенные наборы сообщений по языкам
+var bundles = map[string]map[string]string{
+	"ru": ru,
+	"en": en,
+}
+
+var ru = map[string]string{
+	"error":          "произошла ошибка в боте:(",
+	"need_profile":   "Сначала создай анкету: /start",
+	"no_such_option": "Нет такого варианта ответа",
+	"photo_error":    "Ошибка загрузки фотографии из бд",
+	"stale_button":   "Эта кнопка устарела",
+
+	"menu":   "1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.\n4. Кто меня лайкнул 💌.\n5. Мои лайки.\n6. Мои пары 💞.",
+	"menu.1": "🚀 Смотреть анкеты",
+	"menu.2": "📱 Моя анкета",
+	"menu.3": "✏️ Изменить анкету",
+	"menu.4": "💌 Кто меня лайкнул",
+	"menu.5": "❤ Мои лайки",
+	"menu.6": "💞 Мои пары",
+
+	"search.error":  "Произошла ошибка! Попробуй еще раз",
+	"search.found":  `Смогли подобрать идеальную пару для тебя нажми "Начать"`,
+	"search.empty":  "Не смогли подобрать тебе пару :(",
+	"search.start":  "Начать",
+	"browse.empty":  "Анкеты закончились :(",
+	"browse.paused": "Просмотр анкет на паузе. Продолжить: /browse",
+
+	"start.greeting": "Привет, это бот для знакомств!",
+	"start.create":   "Давай создадим твою анкету!",
+	"start.profile":  "Вот так выглядит твоя анкета:",
+
+	"registration.name":             "Как тебя зовут?",
+	"registration.age":              "Теперь укажи свой возраст:",
+	"registration.age_invalid":      "Возраст должен быть числом!",
+	"registration.city":             "В каком городе ты живешь?",
+	"registration.gender":           "Выбери свой пол:",
+	"registration.gender_invalid":   "Такого пола нет!",
+	"registration.interests":        `Кто тебе интересен? Можно выбрать несколько вариантов, затем нажми "Готово"`,
+	"registration.interests_empty":  "Выбери хотя бы один вариант",
+	"registration.interest_toggled": `Отметил. Выбери еще или нажми "Готово"`,
+	"registration.description":      "Напиши описание к своей анкете:",
+	"registration.photo":            "Пришли фото для анкеты:",
+	"registration.photo_error":      "Ошибка при получении фотографии. Отправь фото еще раз",
+	"registration.file_error":       "Ошибка при получении файла. Попробуй еще раз.",
+	"registration.file_path_error":  "Ошибка: путь к файлу отсутствует.",
+	"registration.file_read_error":  "Ошибка при чтении файла. Попробуй еще раз.",
+	"registration.save_error":       "Ошибка при отправке в базу. Попробуй еще раз.",
+	"registration.done":             "Анкета Успешно создана! 🎉\nРасскажи о себе подробнее: /details",
+	"registration.your_profile":     "Твоя анкета:",
+
+	"gender.male":        "Парень",
+	"gender.female":      "Девушка",
+	"gender.nonbinary":   "Небинарная персона",
+	"gender.genderfluid": "Гендерфлюид",
+	"gender.agender":     "Агендер",
+
+	"cmd.start":     "Начать или показать анкету",
+	"cmd.help":      "Список команд",
+	"cmd.profile":   "Моя анкета",
+	"cmd.browse":    "Смотреть анкеты",
+	"cmd.matches":   "Мои пары",
+	"cmd.settings":  "Настройки",
+	"cmd.pause":     "Пауза в просмотре анкет",
+	"cmd.delete":    "Удалить анкету",
+	"cmd.details":   "Рассказать о себе подробнее",
+	"cmd.questions": "Вопросы на совместимость",
+	"help.title":    "Команды бота:",
+	"settings":      "Настройки анкеты:\n/details - рассказать о себе подробнее\n/questions - вопросы на совместимость\n/pause - пауза в просмотре анкет\n/delete - удалить анкету",
+
+	"notify.button":                   "🔔 Уведомления",
+	"notify.title":                    "Уведомления\nРежим: {{.Mode}}\nТихие часы: {{.Quiet}}\nЧасовой пояс: {{.Zone}}\n\nНажми на тип уведомлений, чтобы включить или выключить его. В режиме сводки уведомления приходят одним сообщением раз в несколько часов.",
+	"notify.type.like":                "Лайки",
+	"notify.type.superlike":           "Суперлайки",
+	"notify.type.match":               "Пары",
+	"notify.type.match_expiring":      "Истекающие пары",
+	"notify.type.inactivity_reminder": "Напоминания",
+	"notify.mode.instant":             "сразу",
+	"notify.mode.digest":              "сводкой",
+	"notify.mode.button":              "📬 Режим: {{.Mode}}",
+	"notify.quiet.off":                "выключены",
+	"notify.quiet.hours":              `{{printf "%02d" .From}}:00–{{printf "%02d" .To}}:00`,
+	"notify.quiet.button":             "🌙 Тихие часы: {{.Quiet}}",
+	"notify.zone.choose":              "Выбери часовой пояс:",
+	"notify.done":                     "Готово",
+	"notify.saved":                    "Настройки уведомлений сохранены",
+
+	"delete.confirm":   "Удалить анкету? Лайки и пары пропадут, это нельзя отменить.",
+	"delete.yes":       "🗑 Удалить",
+	"delete.no":        "Отмена",
+	"delete.cancelled": "Удаление отменено",
+	"delete.done":      "Анкета удалена. Создать новую: /start",
+
+	"handoff.liker":       "👀 Показать анкету",
+	"handoff.matches":     "💞 Мои пары",
+	"handoff.unavailable": "Анкета больше недоступна",
+
+	"swipe.too_fast":        "Слишком быстро! Передохни минутку и посмотри анкету повнимательнее 🙂",
+	"swipe.comment":         "Напиши короткое сообщение к лайку (без ссылок):",
+	"swipe.comment_invalid": "Сообщение слишком длинное или содержит ссылку, попробуй еще раз:",
+	"swipe.comment_retry":   "Потом отправь сообщение еще раз:",
+
+	"quota.left":       `Осталось на сегодня: {{.Likes}} {{plural .Likes "лайк" "лайка" "лайков"}} и {{.SuperLikes}} {{plural .SuperLikes "суперлайк" "суперлайка" "суперлайков"}}.`,
+	"quota.likes_out":  "Лайки на сегодня закончились.",
+	"quota.supers_out": "Суперлайки на сегодня закончились.",
+	"quota.tomorrow":   "Возвращайся завтра!",
+	"quota.resets_at":  "Новые будут в {{.Time}}.",
+
+	"undo.nothing":    "Отменить нечего: последний свайп уже отменен или прошло слишком много времени.",
+	"undo.out":        "Отмены на сегодня закончились.",
+	"undo.no_profile": "Свайп отменен, но анкету не удалось загрузить.",
+	"undo.done":       `Свайп отменен. На сегодня {{plural .Undos "осталась" "осталось" "осталось"}} {{.Undos}} {{plural .Undos "отмена" "отмены" "отмен"}}.`,
+
+	"button.done": "Готово",
+	"button.skip": "Пропустить",
+	"button.menu": "В меню",
+
+	"card.compatibility":   "💞 Совместимость: {{.Percent}}%",
+	"card.active_recently": "был(а) недавно",
+	"card.active_week":     "был(а) на этой неделе",
+
+	"details.empty":        "Пока нечего заполнять",
+	"details.invalid":      "Такое значение не подходит, попробуй еще раз",
+	"details.pick_or_skip": `Выбери хотя бы один вариант или нажми "Пропустить"`,
+	"details.number":       "{{.Label}}? Напиши число",
+	"details.number_range": "{{.Label}}? Напиши число от {{.Min}} до {{.Max}}",
+	"details.multi":        `{{.Label}}? Можно выбрать несколько, затем нажми "Готово"`,
+	"details.done":         "Готово! Анкета дополнена 🎉",
+
+	"list.incoming":   "💌 Тебя лайкнули:",
+	"list.outgoing":   "❤ Ты лайкнул(а):",
+	"list.matches":    "💞 Твои пары:",
+	"list.mutual":     " — взаимно 💞",
+	"list.match_line": "{{.Profile}} — <a href=\"tg://user?id={{.UserID}}\">написать</a>\n   сохранить: {{.Keep}}, разорвать: {{.Unmatch}}",
+	"list.profile_id": "• анкета {{.ID}}",
+	"list.empty":      "Здесь пока пусто",
+	"list.end":        "Больше никого нет",
+	"list.next":       "Дальше ➡️",
+
+	"match.not_found": "Такой пары нет или она уже завершена",
+	"match.ended":     "Пара разорвана. Вы больше не увидите друг друга в подборке.",
+	"match.kept":      "Отлично! Пара сохранена и больше не истечет 💞",
+
+	"questions.all_answered":    "Ты ответил на все вопросы! Процент совместимости уже виден в анкетах 💞",
+	"questions.acceptable":      `Какие ответы партнера тебя устроят? Можно выбрать несколько, затем нажми "Готово"`,
+	"questions.pick_or_any":     `Выбери хотя бы один вариант или нажми "Любой"`,
+	"questions.importance":      "Насколько это для тебя важно?",
+	"questions.pick_importance": "Выбери важность кнопкой",
+	"questions.saved":           "Ответы сохранены! Ответить еще на несколько вопросов?",
+	"questions.any":             "Любой",
+	"questions.more":            "Ещё вопросы",
+	"questions.importance.0":    "Неважно",
+	"questions.importance.1":    "Немного",
+	"questions.importance.2":    "Важно",
+	"questions.importance.3":    "Очень важно",
+	"questions.importance.4":    "Обязательно",
+}
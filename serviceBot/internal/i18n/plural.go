@@ -0,0 +1,49 @@
+package i18n
+
+// pluralRules - индекс формы слова для числа n по правилам языка
+var pluralRules = map[string]func(n int) int{
+	"ru": russianPlural,
+	"en": englishPlural,
+}
+
+// russianPlural - 1 лайк, 2 лайка, 5 лайков, 21 лайк, 11 лайков
+func russianPlural(n int) int {
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// englishPlural - 1 like, 2 likes
+func englishPlural(n int) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+// pluralFunc - функция шаблона {{plural .Count "лайк" "лайка" "лайков"}}.
+// Форм передается столько, сколько их в языке; лишние формы не нужны
+func pluralFunc(locale string) func(n int, forms ...string) string {
+	rule, ok := pluralRules[locale]
+	if !ok {
+		rule = englishPlural
+	}
+	return func(n int, forms ...string) string {
+		if len(forms) == 0 {
+			return ""
+		}
+		i := rule(n)
+		if i >= len(forms) {
+			i = len(forms) - 1
+		}
+		return forms[i]
+	}
+}
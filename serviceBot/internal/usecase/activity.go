@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	clientsUser "serviceBot/internal/clients/user_client"
+	"time"
+
+	"gopkg.in/telebot.v4"
+)
+
+// activityInterval - как часто отмечать активность одного пользователя.
+// В карточке она показывается с точностью до дней, чаще слать незачем
+const activityInterval = 5 * time.Minute
+
+// trackActivity отмечает в serviceUser активность каждого, кто пишет боту.
+// Запрос уходит в фоне, чтобы не задерживать ответ пользователю.
+func (uc *UseCase) trackActivity(next telebot.HandlerFunc) telebot.HandlerFunc {
+	return func(ctx telebot.Context) error {
+		if sender := ctx.Sender(); sender != nil {
+			if uc.activityDue(sender.ID, time.Now()) {
+				go func(id int64) {
+					if err := uc.userService.TouchActivity(id); err != nil {
+						log.Printf("Ошибка при обновлении активности %d: %v", id, err)
+					}
+				}(sender.ID)
+			}
+			if uc.savedLanguage(sender.ID) != sender.LanguageCode {
+				uc.saveLanguage(sender)
+			}
+		}
+		return next(ctx)
+	}
+}
+
+// activityDue - пора ли снова отметить активность пользователя.
+// Отметка ставится сразу, чтобы параллельные апдейты не слали запрос дважды
+func (uc *UseCase) activityDue(userID int64, now time.Time) bool {
+	s := uc.session(userID)
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	if !s.activityAt.IsZero() && now.Sub(s.activityAt) < activityInterval {
+		return false
+	}
+	s.activityAt = now
+	return true
+}
+
+// saveLanguage сохраняет в анкете language_code из Telegram, чтобы
+// serviceNotification писал пользователю на его языке. В сессии код
+// запоминается только после записи: до регистрации анкеты еще нет,
+// и язык попадет в нее вместе с регистрацией
+func (uc *UseCase) saveLanguage(sender *telebot.User) {
+	if sender.LanguageCode == "" {
+		return
+	}
+	go func(id int64, code string) {
+		err := uc.userService.SetLanguage(id, code)
+		if errors.Is(err, clientsUser.ErrUserNotFound) {
+			return
+		}
+		if err != nil {
+			log.Printf("Ошибка при сохранении языка %d: %v", id, err)
+			return
+		}
+		uc.setSavedLanguage(id, code)
+	}(sender.ID, sender.LanguageCode)
+}
+
+// savedLanguage - language_code, который уже записан в анкете
+func (uc *UseCase) savedLanguage(userID int64) string {
+	s := uc.session(userID)
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	return s.languageCode
+}
+
+func (uc *UseCase) setSavedLanguage(userID int64, code string) {
+	s := uc.session(userID)
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	s.languageCode = code
+}
+
+// activityLabel - грубая отметка о последней активности для карточки анкеты.
+// Точное время не показываем намеренно.
+func (uc *UseCase) activityLabel(ctx telebot.Context, lastActive time.Time) string {
+	if lastActive.IsZero() {
+		return ""
+	}
+	switch since := time.Since(lastActive); {
+	case since <= 3*24*time.Hour:
+		return uc.tr(ctx, "card.active_recently")
+	case since <= 7*24*time.Hour:
+		return uc.tr(ctx, "card.active_week")
+	default:
+		return ""
+	}
+}
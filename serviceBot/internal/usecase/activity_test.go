@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivityDueOncePerInterval(t *testing.T) {
+	uc := &UseCase{sessions: make(map[int64]*session)}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if !uc.activityDue(1, now) {
+		t.Fatal("first update must be tracked")
+	}
+	if uc.activityDue(1, now.Add(activityInterval-time.Second)) {
+		t.Fatal("update within the interval must be skipped")
+	}
+	if !uc.activityDue(2, now) {
+		t.Fatal("other users must be tracked independently")
+	}
+	if !uc.activityDue(1, now.Add(activityInterval)) {
+		t.Fatal("update after the interval must be tracked")
+	}
+}
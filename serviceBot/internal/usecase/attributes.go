@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	clientsUser "serviceBot/internal/clients/user_client"
+	"serviceBot/internal/entity"
+	"serviceBot/internal/i18n"
+
+	"gopkg.in/telebot.v4"
+)
+
+// attributeFlow - пошаговое заполнение расширенных атрибутов анкеты (/details).
+// Вопросы строятся по реестру serviceUser, поэтому новые атрибуты появляются
+// в боте без изменения кода.
+type attributeFlow struct {
+	defs     []entity.AttributeDefinition
+	index    int
+	selected []string
+}
+
+func (f *attributeFlow) current() entity.AttributeDefinition {
+	return f.defs[f.index]
+}
+
+func (uc *UseCase) attributeFlow(userID int64) *attributeFlow {
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	return uc.attributeFlows[userID]
+}
+
+func (uc *UseCase) setAttributeFlow(userID int64, flow *attributeFlow) {
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	if flow == nil {
+		delete(uc.attributeFlows, userID)
+		return
+	}
+	uc.attributeFlows[userID] = flow
+}
+
+func (uc *UseCase) startAttributeFlow(ctx telebot.Context) error {
+	user, err := uc.userService.GetUserByID(ctx.Sender().ID)
+	if err != nil || user == nil {
+		return ctx.Send(uc.tr(ctx, "need_profile"))
+	}
+
+	defs, err := uc.userService.ListAttributes()
+	if err != nil {
+		log.Println("Ошибка при загрузке атрибутов:", err)
+		return ctx.Send(uc.tr(ctx, "error"))
+	}
+	if len(defs) == 0 {
+		return ctx.Send(uc.tr(ctx, "details.empty"))
+	}
+
+	flow := &attributeFlow{defs: defs}
+	uc.setAttributeFlow(ctx.Sender().ID, flow)
+	return uc.askAttribute(ctx, flow)
+}
+
+func (uc *UseCase) handleAttributeFlow(ctx telebot.Context, flow *attributeFlow) error {
+	def := flow.current()
+	text := ctx.Text()
+
+	if uc.isButton(text, "button.skip") {
+		return uc.nextAttribute(ctx, flow)
+	}
+
+	var values []string
+	switch def.Type {
+	case entity.AttributeEnum:
+		value, ok := attributeValueByLabel(def, text)
+		if !ok {
+			return ctx.Send(uc.tr(ctx, "no_such_option"))
+		}
+		values = []string{value}
+	case entity.AttributeMultiEnum:
+		if !uc.isButton(text, "button.done") {
+			value, ok := attributeValueByLabel(def, text)
+			if !ok {
+				return ctx.Send(uc.tr(ctx, "no_such_option"))
+			}
+			flow.selected = toggleCode(flow.selected, value)
+			return uc.askAttribute(ctx, flow)
+		}
+		if len(flow.selected) == 0 {
+			return ctx.Send(uc.tr(ctx, "details.pick_or_skip"))
+		}
+		values = flow.selected
+	default:
+		values = []string{text}
+	}
+
+	err := uc.userService.SetAttribute(ctx.Sender().ID, def.Key, values)
+	if errors.Is(err, clientsUser.ErrInvalidValue) {
+		return ctx.Send(uc.tr(ctx, "details.invalid"))
+	}
+	if err != nil {
+		log.Println("Ошибка при сохранении атрибута:", err)
+		uc.setAttributeFlow(ctx.Sender().ID, nil)
+		ctx.Send(uc.tr(ctx, "error"))
+		return uc.sendMainMenu(ctx)
+	}
+	return uc.nextAttribute(ctx, flow)
+}
+
+func (uc *UseCase) nextAttribute(ctx telebot.Context, flow *attributeFlow) error {
+	flow.index++
+	flow.selected = nil
+	if flow.index >= len(flow.defs) {
+		uc.setAttributeFlow(ctx.Sender().ID, nil)
+		ctx.Send(uc.tr(ctx, "details.done"), &telebot.ReplyMarkup{RemoveKeyboard: true})
+		return uc.sendMainMenu(ctx)
+	}
+	return uc.askAttribute(ctx, flow)
+}
+
+func (uc *UseCase) askAttribute(ctx telebot.Context, flow *attributeFlow) error {
+	def := flow.current()
+	skip := []telebot.ReplyButton{{Text: uc.tr(ctx, "button.skip")}}
+
+	switch def.Type {
+	case entity.AttributeInt:
+		question := uc.trf(ctx, "details.number", i18n.Args{"Label": def.Label})
+		if def.Min != nil && def.Max != nil {
+			question = uc.trf(ctx, "details.number_range", i18n.Args{"Label": def.Label, "Min": *def.Min, "Max": *def.Max})
+		}
+		return ctx.Send(question, &telebot.ReplyMarkup{ReplyKeyboard: [][]telebot.ReplyButton{skip}, ResizeKeyboard: true})
+	case entity.AttributeEnum:
+		var rows [][]telebot.ReplyButton
+		for _, value := range def.AllowedValues {
+			rows = append(rows, []telebot.ReplyButton{{Text: attributeLabel(def, value)}})
+		}
+		rows = append(rows, skip)
+		return ctx.Send(def.Label+"?", &telebot.ReplyMarkup{ReplyKeyboard: rows, ResizeKeyboard: true})
+	case entity.AttributeMultiEnum:
+		var rows [][]telebot.ReplyButton
+		for _, value := range def.AllowedValues {
+			text := attributeLabel(def, value)
+			if containsCode(flow.selected, value) {
+				text = selectedMark + text
+			}
+			rows = append(rows, []telebot.ReplyButton{{Text: text}})
+		}
+		rows = append(rows, []telebot.ReplyButton{{Text: uc.tr(ctx, "button.done")}}, skip)
+		return ctx.Send(uc.trf(ctx, "details.multi", i18n.Args{"Label": def.Label}), &telebot.ReplyMarkup{ReplyKeyboard: rows, ResizeKeyboard: true})
+	default:
+		return ctx.Send(def.Label+"?", &telebot.ReplyMarkup{ReplyKeyboard: [][]telebot.ReplyButton{skip}, ResizeKeyboard: true})
+	}
+}
+
+// attributeLabel - подпись значения атрибута, если админ ее задал
+func attributeLabel(def entity.AttributeDefinition, value string) string {
+	if label, ok := def.ValueLabels[value]; ok && label != "" {
+		return label
+	}
+	return value
+}
+
+func attributeValueByLabel(def entity.AttributeDefinition, text string) (string, bool) {
+	for _, value := range def.AllowedValues {
+		if attributeLabel(def, value) == text || selectedMark+attributeLabel(def, value) == text {
+			return value, true
+		}
+	}
+	return "", false
+}
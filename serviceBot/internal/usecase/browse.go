@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	clientsMatch "serviceBot/internal/clients/match_client"
+	"serviceBot/internal/entity"
+	"strconv"
+
+	"gopkg.in/telebot.v4"
+)
+
+// commentButton - кнопка лайка с сообщением
+const commentButton = "✉️"
+
+// Действия с карточкой анкеты
+const (
+	cardLike    = "like"
+	cardSuper   = "super"
+	cardPrompt  = "prompt"
+	cardComment = "comment"
+	cardDislike = "dislike"
+	cardUndo    = "undo"
+	cardPause   = "pause"
+)
+
+// showNextProfile - показывает следующую анкету из подборки или возвращает в меню,
+// если анкеты закончились. Состояние просмотра хранится в сессии s.
+// После нажатия кнопки карточки анкета меняется в том же сообщении
+func (uc *UseCase) showNextProfile(ctx telebot.Context, s *session) error {
+	if len(s.usersLike) <= 0 {
+		s.stopBrowsing()
+		dropCardKeyboard(ctx)
+		ctx.Send(uc.tr(ctx, "browse.empty"))
+		return uc.sendMainMenu(ctx)
+	}
+	outUser := s.usersLike[len(s.usersLike)-1]
+	s.outID = outUser.TelegramID
+	s.outPrompts = outUser.Prompts
+	s.outCard = outUser
+	s.usersLike = s.usersLike[:len(s.usersLike)-1]
+
+	markup := uc.cardKeyboard(ctx.Sender().ID, outUser)
+	s.likes = 2
+	caption := uc.profileCaption(ctx, &outUser)
+	var err error
+	if fromCard(ctx) {
+		err = uc.sendPhoto(outUser.Photo, caption, editPhoto(ctx, markup))
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, errPhotoUnavailable) {
+			log.Println("Не удалось обновить карточку, отправляем новую:", err)
+			err = uc.sendPhoto(outUser.Photo, caption, replyPhoto(ctx, markup))
+		}
+	} else {
+		err = uc.sendPhoto(outUser.Photo, caption, replyPhoto(ctx, markup))
+	}
+	if errors.Is(err, errPhotoUnavailable) {
+		log.Println(err)
+		s.stopBrowsing()
+		ctx.Send(uc.tr(ctx, "error"))
+		return uc.sendMainMenu(ctx)
+	}
+	return err
+}
+
+// cardKeyboard - inline-кнопки карточки: лайк, суперлайк, лайк с сообщением, дизлайк,
+// отмена последнего свайпа, пауза и лайки конкретных ответов на вопросы
+func (uc *UseCase) cardKeyboard(userID int64, card entity.User) *telebot.ReplyMarkup {
+	nonce := uc.newNonce(userID)
+	button := func(text, action, arg string) telebot.InlineButton {
+		data := callbackData{Action: action, Target: card.TelegramID, Nonce: nonce, Arg: arg}
+		return telebot.InlineButton{Unique: cardButton.Unique, Text: text, Data: data.String()}
+	}
+	rows := [][]telebot.InlineButton{
+		{button("❤", cardLike, ""), button(superLikeButton, cardSuper, ""), button(commentButton, cardComment, ""), button("👎", cardDislike, "")},
+		{button(undoButton, cardUndo, ""), button("💤", cardPause, "")},
+	}
+	if len(card.Prompts) > 0 {
+		row := make([]telebot.InlineButton, 0, len(card.Prompts))
+		for i := range card.Prompts {
+			row = append(row, button(promptButton(i+1), cardPrompt, strconv.Itoa(i+1)))
+		}
+		rows = append(rows, row)
+	}
+	return &telebot.ReplyMarkup{InlineKeyboard: rows}
+}
+
+// swipeAction - действие по тексту кнопки старой reply-клавиатуры карточки
+func swipeAction(text string, prompts []entity.PromptAnswer) (string, *int, bool) {
+	switch text {
+	case "❤":
+		return cardLike, nil, true
+	case superLikeButton:
+		return cardSuper, nil, true
+	case commentButton:
+		return cardComment, nil, true
+	case "👎":
+		return cardDislike, nil, true
+	case undoButton:
+		return cardUndo, nil, true
+	case "💤":
+		return cardPause, nil, true
+	}
+	if promptID, ok := promptReaction(text, prompts); ok {
+		return cardPrompt, promptID, true
+	}
+	return "", nil, false
+}
+
+// swipe - действие с текущей анкетой подборки
+func (uc *UseCase) swipe(ctx telebot.Context, action string, promptID *int, s *session) error {
+	switch action {
+	case cardLike, cardSuper, cardPrompt:
+		super := action == cardSuper
+		quota, err := uc.matchService.LikeUser(entity.Like{FromUserID: ctx.Sender().ID, ToUserID: s.outID, PromptID: promptID, Super: super})
+		if errors.Is(err, clientsMatch.ErrTooManySwipes) {
+			return ctx.Send(uc.tr(ctx, "swipe.too_fast"))
+		}
+		if errors.Is(err, clientsMatch.ErrQuotaExceeded) {
+			if super {
+				// Обычные лайки еще могут остаться, продолжаем показывать анкету
+				return ctx.Send(uc.quotaExceededText(ctx, quota, super))
+			}
+			s.stopBrowsing()
+			dropCardKeyboard(ctx)
+			ctx.Send(uc.quotaExceededText(ctx, quota, super))
+			return uc.sendMainMenu(ctx)
+		}
+		if err != nil {
+			log.Println("Ошибка отправки лайка:", err)
+			s.stopBrowsing()
+			ctx.Send(uc.tr(ctx, "error"))
+			return uc.sendMainMenu(ctx)
+		}
+		if notice := uc.quotaNotice(ctx, quota, super); notice != "" {
+			ctx.Send(notice)
+		}
+		return uc.showNextProfile(ctx, s)
+
+	case cardDislike:
+		// Дизлайк записывается, чтобы его можно было отменить кнопкой «↩️»
+		err := uc.matchService.Dislike(ctx.Sender().ID, s.outID)
+		if errors.Is(err, clientsMatch.ErrTooManySwipes) {
+			return ctx.Send(uc.tr(ctx, "swipe.too_fast"))
+		}
+		if err != nil {
+			log.Println("Ошибка записи дизлайка:", err)
+		}
+		return uc.showNextProfile(ctx, s)
+
+	case cardUndo:
+		return uc.undoSwipe(ctx, s)
+
+	case cardComment:
+		s.likes = 3
+		return ctx.Send(uc.tr(ctx, "swipe.comment"), &telebot.ReplyMarkup{RemoveKeyboard: true})
+
+	case cardPause:
+		s.stopBrowsing()
+		dropCardKeyboard(ctx)
+		return uc.sendMainMenu(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gopkg.in/telebot.v4"
+)
+
+// command - команда бота. Новая команда добавляется строкой в uc.commands()
+// и описанием cmd.<name> в каталоге: она сама регистрируется в боте, попадает
+// в /help и в список команд Telegram
+type command struct {
+	name string
+	// hidden - команда не публикуется в меню, но доступна
+	hidden bool
+	handle func(ctx telebot.Context, s *session) error
+}
+
+func (uc *UseCase) commands() []command {
+	return []command{
+		{name: "start", handle: uc.start},
+		{name: "help", handle: uc.help},
+		{name: "profile", handle: func(ctx telebot.Context, s *session) error {
+			return uc.openMenu(ctx, s, 2)
+		}},
+		{name: "browse", handle: func(ctx telebot.Context, s *session) error {
+			s.usersLike = nil
+			return uc.openMenu(ctx, s, 1)
+		}},
+		{name: "matches", handle: func(ctx telebot.Context, s *session) error {
+			return uc.openMenu(ctx, s, 6)
+		}},
+		{name: "settings", handle: uc.settings},
+		{name: "pause", handle: uc.pause},
+		{name: "delete", handle: uc.confirmDelete},
+		{name: "details", hidden: true, handle: func(ctx telebot.Context, _ *session) error {
+			return uc.startAttributeFlow(ctx)
+		}},
+		{name: "questions", hidden: true, handle: func(ctx telebot.Context, _ *session) error {
+			return uc.startQuestionFlow(ctx)
+		}},
+	}
+}
+
+// registerCommands - регистрирует обработчики команд и публикует их в Telegram
+func (uc *UseCase) registerCommands(b *telebot.Bot) {
+	commands := uc.commands()
+	for _, cmd := range commands {
+		handle := cmd.handle
+		b.Handle("/"+cmd.name, func(ctx telebot.Context) error {
+			// Команда прерывает начатый диалог: заполнение деталей, вопросы, списки
+			uc.setAttributeFlow(ctx.Sender().ID, nil)
+			uc.setQuestionFlow(ctx.Sender().ID, nil)
+			uc.setListFlow(ctx.Sender().ID, nil)
+			return handle(ctx, uc.session(ctx.Sender().ID))
+		})
+	}
+	b.Handle(&deleteButton, uc.handleDelete)
+	b.Handle(&notifyButton, uc.handleNotify)
+
+	// Список команд публикуется на каждом языке каталога; пользователям
+	// с другими языками Telegram покажет английский список без language_code
+	for _, locale := range uc.catalog.Locales() {
+		var published []telebot.Command
+		for _, cmd := range commands {
+			if !cmd.hidden {
+				published = append(published, telebot.Command{Text: cmd.name, Description: uc.catalog.Text(locale, "cmd."+cmd.name, nil)})
+			}
+		}
+		if err := b.SetCommands(published, locale); err != nil {
+			log.Printf("Ошибка публикации команд (%s): %v", locale, err)
+		}
+		if locale == "en" {
+			if err := b.SetCommands(published); err != nil {
+				log.Println("Ошибка публикации команд:", err)
+			}
+		}
+	}
+}
+
+// start - показывает анкету или начинает регистрацию, если анкеты нет
+func (uc *UseCase) start(ctx telebot.Context, s *session) error {
+	user, err := uc.userService.GetUserByID(ctx.Sender().ID)
+	if err != nil || user == nil {
+		s.state = 1
+		ctx.Send(uc.tr(ctx, "start.greeting"))
+		time.Sleep(1 * time.Second)
+		ctx.Send(uc.tr(ctx, "start.create"))
+		time.Sleep(1 * time.Second)
+		s.state = 1
+		return ctx.Send(uc.tr(ctx, "registration.name"), &telebot.ReplyMarkup{RemoveKeyboard: true})
+	}
+
+	if user.UnreachableAt != nil {
+		// Раз /start дошел, бот снова разблокирован - анкета возвращается в выдачу
+		if err := uc.userService.Reactivate(user.TelegramID); err != nil {
+			log.Printf("Ошибка при восстановлении анкеты %d: %v", user.TelegramID, err)
+		}
+	}
+
+	ctx.Send(uc.tr(ctx, "start.profile"), &telebot.ReplyMarkup{RemoveKeyboard: true})
+	fmt.Println(user)
+	if err := uc.sendPhoto(user.Photo, uc.profileCaption(ctx, user), replyPhoto(ctx)); err != nil {
+		log.Println(err)
+		if errors.Is(err, errPhotoUnavailable) {
+			return ctx.Send(uc.tr(ctx, "photo_error"))
+		}
+	}
+	s.autho = 1
+
+	return uc.sendMainMenu(ctx)
+}
+
+func (uc *UseCase) help(ctx telebot.Context, _ *session) error {
+	var sb strings.Builder
+	sb.WriteString(uc.tr(ctx, "help.title") + "\n")
+	for _, cmd := range uc.commands() {
+		fmt.Fprintf(&sb, "/%s - %s\n", cmd.name, uc.tr(ctx, "cmd."+cmd.name))
+	}
+	return ctx.Send(sb.String())
+}
+
+func (uc *UseCase) settings(ctx telebot.Context, _ *session) error {
+	return ctx.Send(uc.tr(ctx, "settings"), uc.settingsKeyboard(ctx))
+}
+
+// pause - прерывает просмотр подборки, продолжить можно командой /browse
+func (uc *UseCase) pause(ctx telebot.Context, s *session) error {
+	s.stopBrowsing()
+	s.usersLike = nil
+	ctx.Send(uc.tr(ctx, "browse.paused"), &telebot.ReplyMarkup{RemoveKeyboard: true})
+	return uc.sendMainMenu(ctx)
+}
+
+// deleteButton - подтверждение удаления анкеты
+var deleteButton = telebot.InlineButton{Unique: "delete"}
+
+const (
+	deleteYes = "yes"
+	deleteNo  = "no"
+)
+
+func (uc *UseCase) confirmDelete(ctx telebot.Context, _ *session) error {
+	user, err := uc.userService.GetUserByID(ctx.Sender().ID)
+	if err != nil || user == nil {
+		return ctx.Send(uc.tr(ctx, "need_profile"))
+	}
+	nonce := uc.newNonce(ctx.Sender().ID)
+	yes := callbackData{Action: deleteYes, Nonce: nonce}
+	no := callbackData{Action: deleteNo, Nonce: nonce}
+	return ctx.Send(uc.tr(ctx, "delete.confirm"), &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{{
+		{Unique: deleteButton.Unique, Text: uc.tr(ctx, "delete.yes"), Data: yes.String()},
+		{Unique: deleteButton.Unique, Text: uc.tr(ctx, "delete.no"), Data: no.String()},
+	}}})
+}
+
+func (uc *UseCase) handleDelete(ctx telebot.Context) error {
+	data, ok := uc.callback(ctx)
+	if !ok {
+		return uc.rejectCallback(ctx)
+	}
+	ctx.Respond()
+	s := uc.session(ctx.Sender().ID)
+
+	if data.Action != deleteYes {
+		ctx.Edit(uc.tr(ctx, "delete.cancelled"))
+		return uc.sendMainMenu(ctx)
+	}
+	if err := uc.userService.Delete(ctx.Sender().ID); err != nil {
+		log.Println("Ошибка удаления анкеты:", err)
+		return ctx.Send(uc.tr(ctx, "error"))
+	}
+	*s = session{}
+	return ctx.Edit(uc.tr(ctx, "delete.done"))
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"strings"
+
+	"gopkg.in/telebot.v4"
+)
+
+// genderCodes - коды пола из serviceUser; порядок задает порядок кнопок.
+// Подписи кнопок лежат в каталоге под ключами gender.<код>
+var genderCodes = []string{"male", "female", "nonbinary", "genderfluid", "agender"}
+
+// selectedMark - отметка выбранного варианта на кнопке мультивыбора
+const selectedMark = "✅ "
+
+// genderCode возвращает код пола по тексту кнопки на любом языке каталога:
+// клавиатура могла быть показана до смены языка в Telegram
+func (uc *UseCase) genderCode(label string) (string, bool) {
+	label = strings.TrimPrefix(label, selectedMark)
+	for _, locale := range uc.catalog.Locales() {
+		for _, code := range genderCodes {
+			if uc.catalog.Text(locale, "gender."+code, nil) == label {
+				return code, true
+			}
+		}
+	}
+	return "", false
+}
+
+// genderKeyboard - клавиатура выбора своего пола
+func (uc *UseCase) genderKeyboard(ctx telebot.Context) [][]telebot.ReplyButton {
+	label := func(i int) telebot.ReplyButton {
+		return telebot.ReplyButton{Text: uc.tr(ctx, "gender."+genderCodes[i])}
+	}
+	return [][]telebot.ReplyButton{
+		{label(0), label(1)},
+		{label(2), label(3), label(4)},
+	}
+}
+
+// interestKeyboard - клавиатура мультивыбора "кто интересен", выбранные
+// варианты отмечены галочкой
+func (uc *UseCase) interestKeyboard(ctx telebot.Context, selected []string) [][]telebot.ReplyButton {
+	var rows [][]telebot.ReplyButton
+	for _, code := range genderCodes {
+		text := uc.tr(ctx, "gender."+code)
+		if containsCode(selected, code) {
+			text = selectedMark + text
+		}
+		rows = append(rows, []telebot.ReplyButton{{Text: text}})
+	}
+	return append(rows, []telebot.ReplyButton{{Text: uc.tr(ctx, "button.done")}})
+}
+
+// toggleCode добавляет код в выбор или убирает его оттуда
+func toggleCode(selected []string, code string) []string {
+	for i, c := range selected {
+		if c == code {
+			return append(selected[:i], selected[i+1:]...)
+		}
+	}
+	return append(selected, code)
+}
+
+func containsCode(codes []string, code string) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
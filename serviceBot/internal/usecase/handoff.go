@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"serviceBot/internal/entity"
+	"serviceBot/internal/i18n"
+
+	"gopkg.in/telebot.v4"
+)
+
+var (
+	ErrBotNotStarted  = errors.New("бот еще не запущен")
+	ErrInvalidHandoff = errors.New("неверное уведомление")
+)
+
+// handoffButton - кнопки уведомлений из serviceNotification. Обновления от
+// Telegram получает только serviceBot, поэтому serviceNotification не отправляет
+// такие уведомления сам, а передает их боту через внутренний API
+var handoffButton = telebot.InlineButton{Unique: "handoff"}
+
+// SendHandoff - отправляет уведомление с кнопками, которые обработает бот
+func (uc *UseCase) SendHandoff(h entity.Handoff) (*entity.SentMessage, error) {
+	uc.flowsMu.Lock()
+	b := uc.bot
+	uc.flowsMu.Unlock()
+	if b == nil {
+		return nil, ErrBotNotStarted
+	}
+	if h.UserID == 0 || h.TargetID == 0 || h.Text == "" {
+		return nil, ErrInvalidHandoff
+	}
+
+	data := callbackData{Action: h.Kind, Target: h.TargetID}
+	locale := i18n.Locale(h.LanguageCode)
+	var button telebot.InlineButton
+	switch h.Kind {
+	case entity.HandoffLiker:
+		button = telebot.InlineButton{Unique: handoffButton.Unique, Text: uc.catalog.Text(locale, "handoff.liker", nil), Data: data.String()}
+	case entity.HandoffMatch:
+		button = telebot.InlineButton{Unique: handoffButton.Unique, Text: uc.catalog.Text(locale, "handoff.matches", nil), Data: data.String()}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidHandoff, h.Kind)
+	}
+
+	opts := &telebot.SendOptions{
+		ParseMode:   telebot.ParseMode(h.ParseMode),
+		ReplyMarkup: &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{{button}}},
+	}
+	sent, err := b.Send(&telebot.User{ID: h.UserID}, h.Text, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.SentMessage{ChatID: sent.Chat.ID, MessageID: sent.ID}, nil
+}
+
+// handleHandoff - нажатие кнопки уведомления. Nonce у таких кнопок нет:
+// уведомление может прийти в любой момент и не должно сбивать текущий просмотр.
+// Вместо nonce анкета лайкнувшего показывается только после проверки лайка:
+// данные кнопки подделываются тривиально, а serviceMatch скрывает лайки
+// под ограничением антиспама так же, как во входящих
+func (uc *UseCase) handleHandoff(ctx telebot.Context) error {
+	data, ok := parseCallback(ctx.Args())
+	if !ok {
+		return uc.rejectCallback(ctx)
+	}
+	ctx.Respond()
+	s := uc.session(ctx.Sender().ID)
+
+	switch data.Action {
+	case entity.HandoffLiker:
+		liked, err := uc.matchService.HasLiked(data.Target, ctx.Sender().ID)
+		if err != nil {
+			log.Println("Ошибка проверки входящего лайка:", err)
+			return ctx.Send(uc.tr(ctx, "error"))
+		}
+		if !liked {
+			return ctx.Send(uc.tr(ctx, "handoff.unavailable"))
+		}
+		liker, err := uc.userService.GetUserByID(data.Target)
+		if err != nil || liker == nil {
+			if err != nil {
+				log.Println("Ошибка загрузки анкеты лайкнувшего:", err)
+			}
+			return ctx.Send(uc.tr(ctx, "handoff.unavailable"))
+		}
+		if err := ctx.Edit(&telebot.ReplyMarkup{}); err != nil {
+			log.Println("Не удалось убрать кнопку с уведомления:", err)
+		}
+		// Анкета лайкнувшего показывается первой, после ответа просмотр подборки
+		// продолжится с карточки, которая была открыта
+		if s.likes == 2 {
+			s.usersLike = append(s.usersLike, s.outCard)
+		}
+		s.usersLike = append(s.usersLike, *liker)
+		s.autho = 0
+		return uc.showNextProfile(ctx, s)
+	case entity.HandoffMatch:
+		s.likes = 0
+		return uc.openMenu(ctx, s, 6)
+	}
+	return nil
+}
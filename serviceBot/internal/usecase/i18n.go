@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"serviceBot/internal/i18n"
+
+	"gopkg.in/telebot.v4"
+)
+
+// locale - язык интерфейса отправителя по language_code из Telegram
+func locale(ctx telebot.Context) string {
+	if ctx.Sender() == nil {
+		return i18n.DefaultLocale
+	}
+	return i18n.Locale(ctx.Sender().LanguageCode)
+}
+
+// tr - сообщение из каталога на языке отправителя
+func (uc *UseCase) tr(ctx telebot.Context, key string) string {
+	return uc.catalog.Text(locale(ctx), key, nil)
+}
+
+// trf - сообщение из каталога с данными для шаблона
+func (uc *UseCase) trf(ctx telebot.Context, key string, args i18n.Args) string {
+	return uc.catalog.Text(locale(ctx), key, args)
+}
+
+// isButton - текст совпадает с подписью кнопки key на любом языке каталога:
+// клавиатура могла быть показана до смены языка в Telegram
+func (uc *UseCase) isButton(text, key string) bool {
+	for _, locale := range uc.catalog.Locales() {
+		if uc.catalog.Text(locale, key, nil) == text {
+			return true
+		}
+	}
+	return false
+}
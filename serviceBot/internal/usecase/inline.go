@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"strings"
+
+	"gopkg.in/telebot.v4"
+)
+
+// Inline-кнопки меню и карточек анкет. В callback data лежат действие,
+// Telegram ID анкеты и nonce сессии: каждое новое меню или карточка выдает
+// новый nonce, поэтому нажатия на старые клавиатуры отклоняются
+var (
+	menuButton = telebot.InlineButton{Unique: "menu"}
+	cardButton = telebot.InlineButton{Unique: "card"}
+)
+
+// menuStart - кнопка «Начать» после подбора анкет
+const menuStart = "start"
+
+// callbackData - содержимое inline-кнопки: action|target|nonce|arg
+type callbackData struct {
+	Action string
+	Target int64
+	Nonce  string
+	Arg    string
+}
+
+func (d callbackData) String() string {
+	return strings.Join([]string{d.Action, strconv.FormatInt(d.Target, 10), d.Nonce, d.Arg}, "|")
+}
+
+func parseCallback(args []string) (callbackData, bool) {
+	if len(args) != 4 {
+		return callbackData{}, false
+	}
+	target, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return callbackData{}, false
+	}
+	return callbackData{Action: args[0], Target: target, Nonce: args[2], Arg: args[3]}, true
+}
+
+// newNonce - начинает новую сессию кнопок пользователя; прежние клавиатуры устаревают
+func (uc *UseCase) newNonce(userID int64) string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		log.Println("Ошибка генерации nonce:", err)
+	}
+	nonce := hex.EncodeToString(buf)
+
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	uc.nonces[userID] = nonce
+	return nonce
+}
+
+// callback - разбирает нажатие inline-кнопки; false - кнопка чужая или устарела
+func (uc *UseCase) callback(ctx telebot.Context) (callbackData, bool) {
+	data, ok := parseCallback(ctx.Args())
+	if !ok {
+		return callbackData{}, false
+	}
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	return data, data.Nonce != "" && uc.nonces[ctx.Sender().ID] == data.Nonce
+}
+
+func (uc *UseCase) rejectCallback(ctx telebot.Context) error {
+	return ctx.Respond(&telebot.CallbackResponse{Text: uc.tr(ctx, "stale_button")})
+}
+
+// fromCard - нажата кнопка карточки анкеты, ее сообщение можно редактировать
+func fromCard(ctx telebot.Context) bool {
+	return ctx.Callback() != nil && ctx.Callback().Unique == cardButton.Unique
+}
+
+// dropCardKeyboard - убирает кнопки с карточки, когда просмотр закончился
+func dropCardKeyboard(ctx telebot.Context) {
+	if !fromCard(ctx) {
+		return
+	}
+	if err := ctx.Edit(&telebot.ReplyMarkup{}); err != nil {
+		log.Println("Не удалось убрать кнопки с карточки:", err)
+	}
+}
+
+// startKeyboard - кнопка «Начать» просмотр подобранных анкет
+func (uc *UseCase) startKeyboard(ctx telebot.Context) *telebot.ReplyMarkup {
+	data := callbackData{Action: menuStart, Nonce: uc.newNonce(ctx.Sender().ID)}
+	return &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{
+		{{Unique: menuButton.Unique, Text: uc.tr(ctx, "search.start"), Data: data.String()}},
+	}}
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"serviceBot/internal/entity"
+	"serviceBot/internal/i18n"
+	"strings"
+
+	"gopkg.in/telebot.v4"
+)
+
+// Списки из меню: кто меня лайкнул, мои лайки, мои пары
+const (
+	listIncoming = "incoming"
+	listOutgoing = "outgoing"
+	listMatches  = "matches"
+)
+
+// listKinds - пункты меню 4, 5 и 6 по порядку
+var listKinds = []string{listIncoming, listOutgoing, listMatches}
+
+const listPageSize = 5
+
+// listFlow - постраничный просмотр списка
+type listFlow struct {
+	kind   string
+	offset int
+}
+
+func (uc *UseCase) listFlow(userID int64) *listFlow {
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	return uc.listFlows[userID]
+}
+
+func (uc *UseCase) setListFlow(userID int64, flow *listFlow) {
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	if flow == nil {
+		delete(uc.listFlows, userID)
+		return
+	}
+	uc.listFlows[userID] = flow
+}
+
+func (uc *UseCase) startList(ctx telebot.Context, kind string) error {
+	flow := &listFlow{kind: kind}
+	uc.setListFlow(ctx.Sender().ID, flow)
+	return uc.sendListPage(ctx, flow)
+}
+
+// handleListFlow - кнопки листания. Любой другой текст закрывает список
+// и обрабатывается как обычно (handled = false)
+func (uc *UseCase) handleListFlow(ctx telebot.Context, flow *listFlow) (bool, error) {
+	switch text := ctx.Text(); {
+	case uc.isButton(text, "list.next"):
+		flow.offset += listPageSize
+		return true, uc.sendListPage(ctx, flow)
+	case uc.isButton(text, "button.menu"):
+		uc.setListFlow(ctx.Sender().ID, nil)
+		return true, uc.sendMainMenu(ctx)
+	default:
+		uc.setListFlow(ctx.Sender().ID, nil)
+		return false, nil
+	}
+}
+
+func (uc *UseCase) sendListPage(ctx telebot.Context, flow *listFlow) error {
+	userID := ctx.Sender().ID
+
+	var (
+		title   string
+		lines   []string
+		hasMore bool
+		err     error
+	)
+	switch flow.kind {
+	case listIncoming:
+		title = uc.tr(ctx, "list.incoming")
+		var items []entity.LikeItem
+		items, hasMore, err = uc.matchService.IncomingLikes(userID, flow.offset, listPageSize)
+		for _, item := range items {
+			line := uc.likeLine(ctx, item)
+			if item.Answered {
+				line += uc.tr(ctx, "list.mutual")
+			}
+			lines = append(lines, line)
+		}
+	case listOutgoing:
+		title = uc.tr(ctx, "list.outgoing")
+		var items []entity.LikeItem
+		items, hasMore, err = uc.matchService.OutgoingLikes(userID, flow.offset, listPageSize)
+		for _, item := range items {
+			lines = append(lines, uc.likeLine(ctx, item))
+		}
+	case listMatches:
+		title = uc.tr(ctx, "list.matches")
+		var items []entity.MatchItem
+		items, hasMore, err = uc.matchService.Matches(userID, flow.offset, listPageSize)
+		for _, item := range items {
+			lines = append(lines, uc.trf(ctx, "list.match_line", i18n.Args{
+				"Profile": uc.profileLine(ctx, item.UserID, item.Profile),
+				"UserID":  item.UserID,
+				"Keep":    fmt.Sprintf("%s%d", keepCommand, item.ID),
+				"Unmatch": fmt.Sprintf("%s%d", unmatchCommand, item.ID),
+			}))
+		}
+	}
+	if err != nil {
+		log.Println("Ошибка загрузки списка:", err)
+		uc.setListFlow(userID, nil)
+		ctx.Send(uc.tr(ctx, "error"))
+		return uc.sendMainMenu(ctx)
+	}
+
+	if len(lines) == 0 {
+		uc.setListFlow(userID, nil)
+		if flow.offset == 0 {
+			ctx.Send(uc.tr(ctx, "list.empty"))
+		} else {
+			ctx.Send(uc.tr(ctx, "list.end"))
+		}
+		return uc.sendMainMenu(ctx)
+	}
+
+	row := []telebot.ReplyButton{{Text: uc.tr(ctx, "button.menu")}}
+	if hasMore {
+		row = append(row, telebot.ReplyButton{Text: uc.tr(ctx, "list.next")})
+	}
+	text := title + "\n\n" + strings.Join(lines, "\n")
+	return ctx.Send(text, &telebot.ReplyMarkup{ReplyKeyboard: [][]telebot.ReplyButton{row}, ResizeKeyboard: true}, telebot.ModeHTML)
+}
+
+func (uc *UseCase) likeLine(ctx telebot.Context, item entity.LikeItem) string {
+	line := uc.profileLine(ctx, item.UserID, item.Profile)
+	if item.Comment != "" {
+		line += fmt.Sprintf("\n   ✉️ «%s»", html.EscapeString(item.Comment))
+	}
+	return line
+}
+
+// profileLine - краткая строка анкеты; если serviceUser не ответил, показываем только ID
+func (uc *UseCase) profileLine(ctx telebot.Context, userID int64, profile *entity.User) string {
+	if profile == nil {
+		return uc.trf(ctx, "list.profile_id", i18n.Args{"ID": userID})
+	}
+	return html.EscapeString(fmt.Sprintf("• %s, %d, %s", profile.Name, profile.Age, profile.City))
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	clientsMatch "serviceBot/internal/clients/match_client"
+	"strconv"
+	"strings"
+
+	"gopkg.in/telebot.v4"
+)
+
+// Команды управления парой; ID пары пишется слитно, чтобы Telegram сделал команду кликабельной
+const (
+	unmatchCommand = "/unmatch"
+	keepCommand    = "/keep"
+)
+
+// matchCommand - разбирает команды вида /unmatch12
+func matchCommand(text, command string) (int64, bool) {
+	if !strings.HasPrefix(text, command) {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(text, command), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+func (uc *UseCase) endMatch(ctx telebot.Context, matchID int64) error {
+	err := uc.matchService.EndMatch(matchID, ctx.Sender().ID)
+	if errors.Is(err, clientsMatch.ErrMatchNotFound) {
+		return ctx.Send(uc.tr(ctx, "match.not_found"))
+	}
+	if err != nil {
+		log.Println("Ошибка при разрыве пары:", err)
+		return ctx.Send(uc.tr(ctx, "error"))
+	}
+	ctx.Send(uc.tr(ctx, "match.ended"))
+	return uc.sendMainMenu(ctx)
+}
+
+func (uc *UseCase) keepMatch(ctx telebot.Context, matchID int64) error {
+	err := uc.matchService.FollowUpMatch(matchID, ctx.Sender().ID)
+	if errors.Is(err, clientsMatch.ErrMatchNotFound) {
+		return ctx.Send(uc.tr(ctx, "match.not_found"))
+	}
+	if err != nil {
+		log.Println("Ошибка при сохранении пары:", err)
+		return ctx.Send(uc.tr(ctx, "error"))
+	}
+	return ctx.Send(uc.tr(ctx, "match.kept"))
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+
+	"gopkg.in/telebot.v4"
+)
+
+// menuItemsCount - пункты главного меню menu.1..menu.6 в каталоге; номер пункта
+// совпадает с цифрой старой reply-клавиатуры
+const menuItemsCount = 6
+
+// sendMainMenu показывает основное меню бота. Цифры по-прежнему можно
+// отправить текстом - так работает старая reply-клавиатура
+func (uc *UseCase) sendMainMenu(ctx telebot.Context) error {
+	nonce := uc.newNonce(ctx.Sender().ID)
+	var rows [][]telebot.InlineButton
+	for i := 0; i < menuItemsCount; i++ {
+		data := callbackData{Action: strconv.Itoa(i + 1), Nonce: nonce}
+		button := telebot.InlineButton{Unique: menuButton.Unique, Text: uc.tr(ctx, "menu."+strconv.Itoa(i+1)), Data: data.String()}
+		if i%2 == 0 {
+			rows = append(rows, []telebot.InlineButton{button})
+		} else {
+			rows[len(rows)-1] = append(rows[len(rows)-1], button)
+		}
+	}
+	return ctx.Send(uc.tr(ctx, "menu"), &telebot.ReplyMarkup{InlineKeyboard: rows})
+}
+
+// openMenu - пункт главного меню: цифра со старой reply-клавиатуры или inline-кнопка
+func (uc *UseCase) openMenu(ctx telebot.Context, s *session, choice int) error {
+	user, err := uc.userService.GetUserByID(ctx.Sender().ID)
+	if err != nil || user == nil {
+		return ctx.Send(uc.tr(ctx, "need_profile"))
+	}
+	s.autho = choice + 1
+
+	if s.autho == 2 {
+		// Ищем тех, кто интересен пользователю и кому интересен он сам
+		usersGet, err := uc.userService.SearchUser(user.Age-3, user.Age+3, user.City, user.InterestedIn, user.Gender, ctx.Sender().ID)
+		if err != nil {
+			log.Println("Лоооооохвхыхвхы", err)
+			ctx.Send(uc.tr(ctx, "search.error"))
+			return uc.sendMainMenu(ctx)
+		}
+		if len(usersGet) > 0 {
+			// Анкеты показываются с конца подборки, а выдача отсортирована по убыванию оценки
+			slices.Reverse(usersGet)
+			s.usersLike = append(s.usersLike, usersGet...)
+			s.autho = 0
+			s.likes = 1
+			text := uc.tr(ctx, "search.found")
+			if quota, err := uc.matchService.Quota(ctx.Sender().ID); err != nil {
+				log.Println("Ошибка загрузки квоты лайков:", err)
+			} else if quota != nil {
+				text += "\n" + uc.quotaText(ctx, quota)
+			}
+			return ctx.Send(text, uc.startKeyboard(ctx))
+		} else {
+			return ctx.Send(uc.tr(ctx, "search.empty"))
+		}
+	}
+
+	if s.autho == 3 {
+		fmt.Println(user)
+		if err := uc.sendPhoto(user.Photo, uc.profileCaption(ctx, user), replyPhoto(ctx)); err != nil {
+			log.Println(err)
+			if errors.Is(err, errPhotoUnavailable) {
+				return ctx.Send(uc.tr(ctx, "photo_error"))
+			}
+		}
+		return uc.sendMainMenu(ctx)
+	}
+
+	if s.autho >= 5 && s.autho <= 7 {
+		kind := listKinds[s.autho-5]
+		s.autho = 1
+		return uc.startList(ctx, kind)
+	}
+
+	if s.autho == 4 {
+		err := uc.userService.Delete(ctx.Sender().ID)
+		if err != nil {
+			log.Fatal(err)
+		}
+		s.state = 1
+		s.autho = 0
+		return ctx.Send(uc.tr(ctx, "registration.name"), &telebot.ReplyMarkup{RemoveKeyboard: true})
+	}
+
+	s.autho = 1
+	return ctx.Send(uc.tr(ctx, "no_such_option"))
+}
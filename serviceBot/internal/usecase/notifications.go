@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"serviceBot/internal/entity"
+	"serviceBot/internal/i18n"
+	"slices"
+	"strconv"
+	"time"
+
+	"gopkg.in/telebot.v4"
+)
+
+// notifyButton - кнопки панели настроек уведомлений из /settings
+var notifyButton = telebot.InlineButton{Unique: "notify"}
+
+// Действия панели настроек уведомлений
+const (
+	notifyOpen  = "open"
+	notifyType  = "type"
+	notifyMode  = "mode"
+	notifyQuiet = "quiet"
+	notifyZones = "zones"
+	notifyZone  = "zone"
+	notifyDone  = "done"
+)
+
+// quietPresets - варианты тихих часов, кнопка переключает их по кругу.
+// Первый вариант - тихих часов нет
+var quietPresets = [][2]int{{-1, -1}, {22, 8}, {23, 8}, {0, 9}}
+
+// timeZones - часовые пояса, из которых выбирает пользователь
+var timeZones = []string{
+	"Europe/Kaliningrad", "Europe/Moscow", "Europe/Samara", "Asia/Yekaterinburg",
+	"Asia/Omsk", "Asia/Novosibirsk", "Asia/Irkutsk", "Asia/Yakutsk",
+	"Asia/Vladivostok", "Asia/Magadan", "Asia/Kamchatka",
+	"Europe/London", "Europe/Berlin", "UTC",
+}
+
+// settingsKeyboard - кнопка перехода к настройкам уведомлений под /settings
+func (uc *UseCase) settingsKeyboard(ctx telebot.Context) *telebot.ReplyMarkup {
+	data := callbackData{Action: notifyOpen, Nonce: uc.newNonce(ctx.Sender().ID)}
+	return &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{
+		{{Unique: notifyButton.Unique, Text: uc.tr(ctx, "notify.button"), Data: data.String()}},
+	}}
+}
+
+// handleNotify - нажатие в панели уведомлений: каждое изменение сразу
+// сохраняется в serviceUser, а панель перерисовывается
+func (uc *UseCase) handleNotify(ctx telebot.Context) error {
+	data, ok := uc.callback(ctx)
+	if !ok {
+		return uc.rejectCallback(ctx)
+	}
+	ctx.Respond()
+	userID := ctx.Sender().ID
+
+	if data.Action == notifyDone {
+		return ctx.Edit(uc.tr(ctx, "notify.saved"))
+	}
+	settings, err := uc.userService.NotificationSettings(userID)
+	if err != nil || settings == nil {
+		log.Println("Ошибка загрузки настроек уведомлений:", err)
+		return ctx.Send(uc.tr(ctx, "error"))
+	}
+
+	switch data.Action {
+	case notifyZones:
+		return ctx.Edit(uc.tr(ctx, "notify.zone.choose"), uc.zonesKeyboard(ctx))
+	case notifyType:
+		if i := slices.Index(settings.Disabled, data.Arg); i >= 0 {
+			settings.Disabled = slices.Delete(settings.Disabled, i, i+1)
+		} else if slices.Contains(entity.NotificationTypes, data.Arg) {
+			settings.Disabled = append(settings.Disabled, data.Arg)
+		}
+	case notifyMode:
+		if settings.Mode == entity.NotifyDigest {
+			settings.Mode = entity.NotifyInstant
+		} else {
+			settings.Mode = entity.NotifyDigest
+		}
+	case notifyQuiet:
+		next := quietPresets[(quietPreset(settings)+1)%len(quietPresets)]
+		settings.QuietFrom, settings.QuietTo = nil, nil
+		if next[0] >= 0 {
+			settings.QuietFrom, settings.QuietTo = &next[0], &next[1]
+		}
+	case notifyZone:
+		if slices.Contains(timeZones, data.Arg) {
+			settings.TimeZone = data.Arg
+		}
+	}
+
+	if data.Action != notifyOpen {
+		if err := uc.userService.SaveNotificationSettings(userID, *settings); err != nil {
+			log.Println("Ошибка сохранения настроек уведомлений:", err)
+			return ctx.Send(uc.tr(ctx, "error"))
+		}
+	}
+	return ctx.Edit(uc.notifyText(ctx, settings), uc.notifyKeyboard(ctx, settings))
+}
+
+// quietPreset - индекс текущих тихих часов в quietPresets; часы, заданные
+// через API, считаются последним вариантом, чтобы следующим шел первый
+func quietPreset(s *entity.NotificationSettings) int {
+	if s.QuietFrom == nil || s.QuietTo == nil {
+		return 0
+	}
+	if i := slices.Index(quietPresets, [2]int{*s.QuietFrom, *s.QuietTo}); i >= 0 {
+		return i
+	}
+	return len(quietPresets) - 1
+}
+
+func (uc *UseCase) quietText(ctx telebot.Context, s *entity.NotificationSettings) string {
+	if s.QuietFrom == nil || s.QuietTo == nil {
+		return uc.tr(ctx, "notify.quiet.off")
+	}
+	return uc.trf(ctx, "notify.quiet.hours", i18n.Args{"From": *s.QuietFrom, "To": *s.QuietTo})
+}
+
+func (uc *UseCase) notifyText(ctx telebot.Context, s *entity.NotificationSettings) string {
+	return uc.trf(ctx, "notify.title", i18n.Args{
+		"Mode":  uc.tr(ctx, "notify.mode."+modeOrDefault(s.Mode)),
+		"Quiet": uc.quietText(ctx, s),
+		"Zone":  zoneLabel(s.TimeZone),
+	})
+}
+
+func modeOrDefault(mode string) string {
+	if mode == entity.NotifyDigest {
+		return entity.NotifyDigest
+	}
+	return entity.NotifyInstant
+}
+
+func (uc *UseCase) notifyKeyboard(ctx telebot.Context, s *entity.NotificationSettings) *telebot.ReplyMarkup {
+	nonce := uc.newNonce(ctx.Sender().ID)
+	button := func(text, action, arg string) telebot.InlineButton {
+		data := callbackData{Action: action, Nonce: nonce, Arg: arg}
+		return telebot.InlineButton{Unique: notifyButton.Unique, Text: text, Data: data.String()}
+	}
+
+	var rows [][]telebot.InlineButton
+	var row []telebot.InlineButton
+	for _, t := range entity.NotificationTypes {
+		mark := "✅ "
+		if slices.Contains(s.Disabled, t) {
+			mark = "🔕 "
+		}
+		row = append(row, button(mark+uc.tr(ctx, "notify.type."+t), notifyType, t))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	mode := uc.tr(ctx, "notify.mode."+modeOrDefault(s.Mode))
+	rows = append(rows,
+		[]telebot.InlineButton{button(uc.trf(ctx, "notify.mode.button", i18n.Args{"Mode": mode}), notifyMode, "")},
+		[]telebot.InlineButton{button(uc.trf(ctx, "notify.quiet.button", i18n.Args{"Quiet": uc.quietText(ctx, s)}), notifyQuiet, "")},
+		[]telebot.InlineButton{button("🕒 "+zoneLabel(s.TimeZone), notifyZones, "")},
+		[]telebot.InlineButton{button(uc.tr(ctx, "notify.done"), notifyDone, "")},
+	)
+	return &telebot.ReplyMarkup{InlineKeyboard: rows}
+}
+
+func (uc *UseCase) zonesKeyboard(ctx telebot.Context) *telebot.ReplyMarkup {
+	nonce := uc.newNonce(ctx.Sender().ID)
+	var rows [][]telebot.InlineButton
+	for _, zone := range timeZones {
+		data := callbackData{Action: notifyZone, Nonce: nonce, Arg: zone}
+		rows = append(rows, []telebot.InlineButton{{Unique: notifyButton.Unique, Text: zoneLabel(zone), Data: data.String()}})
+	}
+	return &telebot.ReplyMarkup{InlineKeyboard: rows}
+}
+
+// zoneLabel - часовой пояс со смещением от UTC на сегодня: «UTC+3 Europe/Moscow»
+func zoneLabel(zone string) string {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return zone
+	}
+	_, offset := time.Now().In(loc).Zone()
+	hours, minutes := offset/3600, (offset%3600)/60
+	label := "UTC"
+	if offset != 0 {
+		label += fmt.Sprintf("%+d", hours)
+		if minutes != 0 {
+			label += ":" + strconv.Itoa(max(minutes, -minutes))
+		}
+	}
+	if zone == "UTC" {
+		return label
+	}
+	return label + " " + zone
+}
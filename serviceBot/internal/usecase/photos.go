@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"serviceBot/utilites"
+
+	"gopkg.in/telebot.v4"
+)
+
+// errPhotoUnavailable - фото анкеты не удалось скачать из хранилища
+var errPhotoUnavailable = errors.New("photo unavailable")
+
+// photoCache - file_id фотографий, которые уже загружены в Telegram, по объекту в MinIO.
+// Повторная отправка по file_id не требует ни скачивания, ни загрузки файла
+type photoCache struct {
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+func newPhotoCache() *photoCache {
+	return &photoCache{ids: make(map[string]string)}
+}
+
+// photoKey - ключ объекта: ссылка без query и фрагмента, которые не меняют сам файл
+func photoKey(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		return url[:i]
+	}
+	return url
+}
+
+func (c *photoCache) get(url string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	id, ok := c.ids[photoKey(url)]
+	return id, ok
+}
+
+func (c *photoCache) put(url, fileID string) {
+	if fileID == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[photoKey(url)] = fileID
+}
+
+func (c *photoCache) drop(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.ids, photoKey(url))
+}
+
+// photoSender - отправляет или правит сообщение с фото и возвращает ответ Telegram
+type photoSender func(photo *telebot.Photo) (*telebot.Message, error)
+
+// replyPhoto - новое сообщение с фото в текущий чат
+func replyPhoto(ctx telebot.Context, opts ...interface{}) photoSender {
+	return func(photo *telebot.Photo) (*telebot.Message, error) {
+		return ctx.Bot().Send(ctx.Recipient(), photo, opts...)
+	}
+}
+
+// editPhoto - замена фото в сообщении, на кнопку которого нажали
+func editPhoto(ctx telebot.Context, opts ...interface{}) photoSender {
+	return func(photo *telebot.Photo) (*telebot.Message, error) {
+		return ctx.Bot().Edit(ctx.Callback(), photo, opts...)
+	}
+}
+
+// sendPhoto - отправляет фото анкеты по ссылке. Если file_id объекта уже известен,
+// файл не скачивается; если Telegram его отверг, фото загружается заново.
+// Ошибка скачивания оборачивает errPhotoUnavailable
+func (uc *UseCase) sendPhoto(url, caption string, send photoSender) error {
+	if id, ok := uc.photos.get(url); ok {
+		_, err := send(&telebot.Photo{File: telebot.File{FileID: id}, Caption: caption})
+		if err == nil || !staleFileID(err) {
+			return err
+		}
+		log.Printf("file_id фото %s больше недействителен, загружаем заново: %v", url, err)
+		uc.photos.drop(url)
+	}
+
+	image, err := utilites.DownloadImageAsBytes(url)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPhotoUnavailable, err)
+	}
+	msg, err := send(&telebot.Photo{File: telebot.FromReader(bytes.NewReader(image)), Caption: caption})
+	if err != nil {
+		return err
+	}
+	if msg != nil && msg.Photo != nil {
+		uc.photos.put(url, msg.Photo.FileID)
+	}
+	return nil
+}
+
+// staleFileID - Telegram не принял file_id: неверный идентификатор или файл устарел
+func staleFileID(err error) bool {
+	var tgErr *telebot.Error
+	if !errors.As(err, &tgErr) || tgErr.Code != 400 {
+		return false
+	}
+	return strings.Contains(strings.ToLower(tgErr.Description), "file")
+}
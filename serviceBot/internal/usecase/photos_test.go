@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gopkg.in/telebot.v4"
+)
+
+// fakePhotoSender - запоминает отправленные фото и отвечает заданным file_id
+type fakePhotoSender struct {
+	sent   []*telebot.Photo
+	fileID string
+	err    error
+}
+
+func (f *fakePhotoSender) send(photo *telebot.Photo) (*telebot.Message, error) {
+	f.sent = append(f.sent, photo)
+	if f.err != nil {
+		err := f.err
+		f.err = nil
+		return nil, err
+	}
+	return &telebot.Message{Photo: &telebot.Photo{File: telebot.File{FileID: f.fileID}}}, nil
+}
+
+func newPhotoServer(t *testing.T) (*httptest.Server, *int) {
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		w.Write([]byte("jpeg"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &downloads
+}
+
+func TestSendPhotoReusesFileID(t *testing.T) {
+	server, downloads := newPhotoServer(t)
+	uc := &UseCase{photos: newPhotoCache()}
+	sender := &fakePhotoSender{fileID: "AgAC-1"}
+	url := server.URL + "/photos/1.jpg"
+
+	for i := 0; i < 3; i++ {
+		if err := uc.sendPhoto(url, "caption", sender.send); err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+	}
+
+	if *downloads != 1 {
+		t.Fatalf("downloads = %d, want 1", *downloads)
+	}
+	if len(sender.sent) != 3 {
+		t.Fatalf("sent = %d, want 3", len(sender.sent))
+	}
+	if got := sender.sent[2].FileID; got != "AgAC-1" {
+		t.Errorf("third send file_id = %q, want AgAC-1", got)
+	}
+	if sender.sent[2].Caption != "caption" {
+		t.Errorf("caption = %q", sender.sent[2].Caption)
+	}
+}
+
+func TestSendPhotoReuploadsStaleFileID(t *testing.T) {
+	server, downloads := newPhotoServer(t)
+	uc := &UseCase{photos: newPhotoCache()}
+	url := server.URL + "/photos/1.jpg"
+	uc.photos.put(url, "stale")
+	sender := &fakePhotoSender{fileID: "fresh", err: fmt.Errorf("telebot: %w", telebot.ErrWrongFileID)}
+
+	if err := uc.sendPhoto(url, "caption", sender.send); err != nil {
+		t.Fatal(err)
+	}
+
+	if *downloads != 1 || len(sender.sent) != 2 {
+		t.Fatalf("downloads = %d, sent = %d, want 1 and 2", *downloads, len(sender.sent))
+	}
+	if id, _ := uc.photos.get(url); id != "fresh" {
+		t.Errorf("cached file_id = %q, want fresh", id)
+	}
+}
+
+func TestSendPhotoKeepsFileIDOnOtherErrors(t *testing.T) {
+	server, downloads := newPhotoServer(t)
+	uc := &UseCase{photos: newPhotoCache()}
+	url := server.URL + "/photos/1.jpg"
+	uc.photos.put(url, "cached")
+	sender := &fakePhotoSender{err: telebot.ErrBlockedByUser}
+
+	if err := uc.sendPhoto(url, "caption", sender.send); !errors.Is(err, telebot.ErrBlockedByUser) {
+		t.Fatalf("err = %v, want ErrBlockedByUser", err)
+	}
+	if *downloads != 0 {
+		t.Errorf("downloads = %d, want 0", *downloads)
+	}
+	if id, _ := uc.photos.get(url); id != "cached" {
+		t.Errorf("cached file_id = %q, want cached", id)
+	}
+}
+
+func TestSendPhotoUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	uc := &UseCase{photos: newPhotoCache()}
+	sender := &fakePhotoSender{}
+
+	err := uc.sendPhoto(server.URL+"/photos/missing.jpg", "caption", sender.send)
+	if !errors.Is(err, errPhotoUnavailable) {
+		t.Fatalf("err = %v, want errPhotoUnavailable", err)
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("sent = %d, want 0", len(sender.sent))
+	}
+}
+
+func TestPhotoKeyIgnoresQuery(t *testing.T) {
+	cache := newPhotoCache()
+	cache.put("http://minio:9000/photos/1.jpg?X-Amz-Signature=a", "id")
+	if id, ok := cache.get("http://minio:9000/photos/1.jpg?X-Amz-Signature=b"); !ok || id != "id" {
+		t.Errorf("get = %q, %v", id, ok)
+	}
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"fmt"
+	"serviceBot/internal/entity"
+	"serviceBot/internal/i18n"
+
+	"gopkg.in/telebot.v4"
+)
+
+// profileCaption - подпись под фотографией анкеты на языке отправителя
+func (uc *UseCase) profileCaption(ctx telebot.Context, user *entity.User) string {
+	caption := fmt.Sprintf("%s, %d, %s - %s", user.Name, user.Age, user.City, user.Description)
+	if user.Compatibility != nil {
+		caption += "\n" + uc.trf(ctx, "card.compatibility", i18n.Args{"Percent": *user.Compatibility})
+	}
+	if label := uc.activityLabel(ctx, user.LastActiveAt); label != "" {
+		caption += "\n🕒 " + label
+	}
+	for i, p := range user.Prompts {
+		caption += fmt.Sprintf("\n\n💬 %d. %s\n%s", i+1, p.Question, p.Answer)
+	}
+	return caption
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"fmt"
+	"serviceBot/internal/entity"
+)
+
+// promptButton - кнопка реакции на N-й вопрос-карточку анкеты (в старой reply-клавиатуре - ее текст)
+func promptButton(n int) string {
+	return fmt.Sprintf("💬 %d", n)
+}
+
+// promptReaction - определяет, на какой ответ анкеты отреагировал пользователь
+func promptReaction(text string, prompts []entity.PromptAnswer) (*int, bool) {
+	for i, p := range prompts {
+		if text == promptButton(i+1) {
+			id := p.PromptID
+			return &id, true
+		}
+	}
+	return nil, false
+}
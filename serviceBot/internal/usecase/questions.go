@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	clientsUser "serviceBot/internal/clients/user_client"
+	"serviceBot/internal/entity"
+
+	"gopkg.in/telebot.v4"
+)
+
+const (
+	questionBatch      = 3
+	questionStepOwn    = 0
+	questionStepAccept = 1
+	questionStepWeight = 2
+	questionStepMore   = 3
+)
+
+// importanceLevels - число кнопок важности вопроса; подписи лежат в каталоге под
+// ключами questions.importance.<n>, n совпадает с важностью в serviceUser
+const importanceLevels = 5
+
+// questionFlow - анкета совместимости (/questions): по каждому вопросу свой ответ,
+// подходящие ответы партнера и важность. Вопросы приходят пачками по questionBatch.
+type questionFlow struct {
+	questions []entity.Question
+	index     int
+	step      int
+	answer    entity.QuestionAnswer
+}
+
+func (f *questionFlow) current() entity.Question {
+	return f.questions[f.index]
+}
+
+func (uc *UseCase) questionFlow(userID int64) *questionFlow {
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	return uc.questionFlows[userID]
+}
+
+func (uc *UseCase) setQuestionFlow(userID int64, flow *questionFlow) {
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	if flow == nil {
+		delete(uc.questionFlows, userID)
+		return
+	}
+	uc.questionFlows[userID] = flow
+}
+
+func (uc *UseCase) startQuestionFlow(ctx telebot.Context) error {
+	user, err := uc.userService.GetUserByID(ctx.Sender().ID)
+	if err != nil || user == nil {
+		return ctx.Send(uc.tr(ctx, "need_profile"))
+	}
+	return uc.nextQuestionBatch(ctx)
+}
+
+func (uc *UseCase) nextQuestionBatch(ctx telebot.Context) error {
+	questions, err := uc.userService.NextQuestions(ctx.Sender().ID, questionBatch)
+	if err != nil {
+		log.Println("Ошибка при загрузке вопросов:", err)
+		uc.setQuestionFlow(ctx.Sender().ID, nil)
+		return ctx.Send(uc.tr(ctx, "error"))
+	}
+	if len(questions) == 0 {
+		uc.setQuestionFlow(ctx.Sender().ID, nil)
+		ctx.Send(uc.tr(ctx, "questions.all_answered"), &telebot.ReplyMarkup{RemoveKeyboard: true})
+		return uc.sendMainMenu(ctx)
+	}
+
+	flow := &questionFlow{questions: questions}
+	flow.answer.QuestionID = flow.current().ID
+	uc.setQuestionFlow(ctx.Sender().ID, flow)
+	return uc.askQuestion(ctx, flow)
+}
+
+func (uc *UseCase) handleQuestionFlow(ctx telebot.Context, flow *questionFlow) error {
+	text := ctx.Text()
+
+	switch flow.step {
+	case questionStepMore:
+		if uc.isButton(text, "questions.more") {
+			return uc.nextQuestionBatch(ctx)
+		}
+		uc.setQuestionFlow(ctx.Sender().ID, nil)
+		return uc.sendMainMenu(ctx)
+	case questionStepOwn:
+		option, ok := questionOption(flow.current(), text)
+		if !ok {
+			return ctx.Send(uc.tr(ctx, "no_such_option"))
+		}
+		flow.answer.Answer = option
+		flow.step = questionStepAccept
+	case questionStepAccept:
+		switch {
+		case uc.isButton(text, "questions.any"):
+			flow.answer.Acceptable = nil
+			for i := range flow.current().Options {
+				flow.answer.Acceptable = append(flow.answer.Acceptable, i)
+			}
+			flow.step = questionStepWeight
+		case uc.isButton(text, "button.done"):
+			if len(flow.answer.Acceptable) == 0 {
+				return ctx.Send(uc.tr(ctx, "questions.pick_or_any"))
+			}
+			flow.step = questionStepWeight
+		default:
+			option, ok := questionOption(flow.current(), text)
+			if !ok {
+				return ctx.Send(uc.tr(ctx, "no_such_option"))
+			}
+			flow.answer.Acceptable = toggleOption(flow.answer.Acceptable, option)
+		}
+	case questionStepWeight:
+		importance := -1
+		for i := range importanceLevels {
+			if uc.isButton(text, importanceKey(i)) {
+				importance = i
+			}
+		}
+		if importance < 0 {
+			return ctx.Send(uc.tr(ctx, "questions.pick_importance"))
+		}
+		flow.answer.Importance = importance
+		return uc.saveQuestionAnswer(ctx, flow)
+	}
+	return uc.askQuestion(ctx, flow)
+}
+
+// importanceKey - подпись кнопки важности в каталоге
+func importanceKey(importance int) string {
+	return fmt.Sprintf("questions.importance.%d", importance)
+}
+
+func (uc *UseCase) saveQuestionAnswer(ctx telebot.Context, flow *questionFlow) error {
+	err := uc.userService.AnswerQuestion(ctx.Sender().ID, flow.answer)
+	if errors.Is(err, clientsUser.ErrInvalidValue) {
+		// Вопрос могли отключить, пока пользователь отвечал - просто идем дальше
+		log.Printf("Ответ на вопрос %d отклонен", flow.answer.QuestionID)
+	} else if err != nil {
+		log.Println("Ошибка при сохранении ответа:", err)
+		uc.setQuestionFlow(ctx.Sender().ID, nil)
+		ctx.Send(uc.tr(ctx, "error"))
+		return uc.sendMainMenu(ctx)
+	}
+
+	flow.index++
+	flow.step = questionStepOwn
+	if flow.index >= len(flow.questions) {
+		flow.step = questionStepMore
+		return ctx.Send(uc.tr(ctx, "questions.saved"), &telebot.ReplyMarkup{
+			ReplyKeyboard:  [][]telebot.ReplyButton{{{Text: uc.tr(ctx, "questions.more")}, {Text: uc.tr(ctx, "button.menu")}}},
+			ResizeKeyboard: true,
+		})
+	}
+	flow.answer = entity.QuestionAnswer{QuestionID: flow.current().ID}
+	return uc.askQuestion(ctx, flow)
+}
+
+func (uc *UseCase) askQuestion(ctx telebot.Context, flow *questionFlow) error {
+	question := flow.current()
+
+	switch flow.step {
+	case questionStepAccept:
+		var rows [][]telebot.ReplyButton
+		for i, option := range question.Options {
+			text := option
+			if containsOption(flow.answer.Acceptable, i) {
+				text = selectedMark + text
+			}
+			rows = append(rows, []telebot.ReplyButton{{Text: text}})
+		}
+		rows = append(rows, []telebot.ReplyButton{{Text: uc.tr(ctx, "button.done")}, {Text: uc.tr(ctx, "questions.any")}})
+		return ctx.Send(uc.tr(ctx, "questions.acceptable"), &telebot.ReplyMarkup{ReplyKeyboard: rows, ResizeKeyboard: true})
+	case questionStepWeight:
+		var rows [][]telebot.ReplyButton
+		for i := range importanceLevels {
+			rows = append(rows, []telebot.ReplyButton{{Text: uc.tr(ctx, importanceKey(i))}})
+		}
+		return ctx.Send(uc.tr(ctx, "questions.importance"), &telebot.ReplyMarkup{ReplyKeyboard: rows, ResizeKeyboard: true})
+	default:
+		var rows [][]telebot.ReplyButton
+		for _, option := range question.Options {
+			rows = append(rows, []telebot.ReplyButton{{Text: option}})
+		}
+		return ctx.Send("❓ "+question.Text, &telebot.ReplyMarkup{ReplyKeyboard: rows, ResizeKeyboard: true})
+	}
+}
+
+// questionOption - индекс варианта ответа по тексту кнопки (с отметкой выбора или без)
+func questionOption(question entity.Question, text string) (int, bool) {
+	for i, option := range question.Options {
+		if option == text || selectedMark+option == text {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func toggleOption(selected []int, option int) []int {
+	for i, o := range selected {
+		if o == option {
+			return append(selected[:i], selected[i+1:]...)
+		}
+	}
+	return append(selected, option)
+}
+
+func containsOption(options []int, option int) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
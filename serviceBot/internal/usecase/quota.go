@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"serviceBot/internal/entity"
+	"serviceBot/internal/i18n"
+
+	"gopkg.in/telebot.v4"
+)
+
+// superLikeButton - кнопка суперлайка
+const superLikeButton = "⭐"
+
+// lowLikesThreshold - с какого остатка начинаем предупреждать о лимите лайков
+const lowLikesThreshold = 5
+
+// quotaText - остаток квот для показа пользователю
+func (uc *UseCase) quotaText(ctx telebot.Context, quota *entity.Quota) string {
+	return uc.trf(ctx, "quota.left", i18n.Args{"Likes": quota.Likes, "SuperLikes": quota.SuperLikes})
+}
+
+// quotaExceededText - сообщение об исчерпанном дневном лимите
+func (uc *UseCase) quotaExceededText(ctx telebot.Context, quota *entity.Quota, super bool) string {
+	text := uc.tr(ctx, "quota.likes_out")
+	if super {
+		text = uc.tr(ctx, "quota.supers_out")
+	}
+	if quota == nil {
+		return text + " " + uc.tr(ctx, "quota.tomorrow")
+	}
+	return text + " " + uc.trf(ctx, "quota.resets_at", i18n.Args{"Time": quota.ResetsAt.Format("15:04")}) + "\n" + uc.quotaText(ctx, quota)
+}
+
+// quotaNotice - напоминание об остатке после лайка: после суперлайка и когда лайков осталось мало.
+// Пустая строка - напоминать не нужно
+func (uc *UseCase) quotaNotice(ctx telebot.Context, quota *entity.Quota, super bool) string {
+	if quota == nil {
+		return ""
+	}
+	if super || quota.Likes <= lowLikesThreshold {
+		return uc.quotaText(ctx, quota)
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"serviceBot/internal/entity"
+	"time"
+)
+
+// session - состояние диалога пользователя с ботом
+type session struct {
+	// state - шаг регистрации, 0 - пользователь не регистрируется
+	state int
+	// autho > 0 - ждем выбор пункта главного меню
+	autho int
+	// likes - просмотр подборки: 1 - ждем «Начать», 2 - показана карточка,
+	// 3 - ждем сообщение к лайку
+	likes int
+
+	// usersLike - подборка анкет, показываются с конца
+	usersLike []entity.User
+	// outID, outPrompts и outCard - анкета на текущей карточке
+	outID      int64
+	outPrompts []entity.PromptAnswer
+	outCard    entity.User
+
+	// languageCode - последний language_code, сохраненный в анкете.
+	// Читается и меняется под uc.flowsMu: запись идет из фоновой горутины
+	languageCode string
+	// activityAt - когда активность последний раз ушла в serviceUser, под uc.flowsMu
+	activityAt time.Time
+}
+
+// session - сессия пользователя, создается при первом обращении
+func (uc *UseCase) session(userID int64) *session {
+	uc.flowsMu.Lock()
+	defer uc.flowsMu.Unlock()
+	s, ok := uc.sessions[userID]
+	if !ok {
+		s = &session{}
+		uc.sessions[userID] = s
+	}
+	return s
+}
+
+// stopBrowsing - выход из просмотра подборки в главное меню
+func (s *session) stopBrowsing() {
+	s.likes = 0
+	s.autho = 1
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	clientsMatch "serviceBot/internal/clients/match_client"
+	"serviceBot/internal/i18n"
+
+	"gopkg.in/telebot.v4"
+)
+
+// undoButton - кнопка отмены последнего свайпа
+const undoButton = "↩️"
+
+// undoSwipe - отменяет последний свайп и снова показывает ту анкету.
+// Текущая анкета возвращается в подборку и будет показана следом
+func (uc *UseCase) undoSwipe(ctx telebot.Context, s *session) error {
+	swipe, quota, err := uc.matchService.Undo(ctx.Sender().ID)
+	if errors.Is(err, clientsMatch.ErrNothingToUndo) {
+		return ctx.Send(uc.tr(ctx, "undo.nothing"))
+	}
+	if errors.Is(err, clientsMatch.ErrUndoLimitReached) {
+		text := uc.tr(ctx, "undo.out")
+		if quota != nil {
+			text += " " + uc.trf(ctx, "quota.resets_at", i18n.Args{"Time": quota.ResetsAt.Format("15:04")})
+		}
+		return ctx.Send(text)
+	}
+	if err != nil {
+		log.Println("Ошибка отмены свайпа:", err)
+		return ctx.Send(uc.tr(ctx, "error"))
+	}
+
+	previous, err := uc.userService.GetUserByID(swipe.ToUserID)
+	if err != nil || previous == nil {
+		// Анкету могли удалить, пока шло окно отмены
+		if err != nil {
+			log.Println("Ошибка загрузки анкеты после отмены:", err)
+		}
+		return ctx.Send(uc.tr(ctx, "undo.no_profile"))
+	}
+
+	// Открытая карточка возвращается в подборку, только если просмотр еще идет:
+	// после последней анкеты в outCard осталась уже показанная карточка
+	if s.likes == 2 {
+		s.usersLike = append(s.usersLike, s.outCard)
+	}
+	s.usersLike = append(s.usersLike, *previous)
+	if quota != nil {
+		ctx.Send(uc.trf(ctx, "undo.done", i18n.Args{"Undos": quota.Undos}))
+	}
+	return uc.showNextProfile(ctx, s)
+}
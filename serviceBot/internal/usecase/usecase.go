@@ -2,376 +2,299 @@ package usecase
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	clientsMatch "serviceBot/internal/clients/match_client"
 	"serviceBot/internal/entity"
-	"serviceBot/utilites"
+	"serviceBot/internal/i18n"
 	"strconv"
+	"sync"
 	"time"
 
 	"gopkg.in/telebot.v4"
 )
 
 type UserService interface {
-	CreateUser(name, city, gender, description string, age int, telegramID int64, file []byte, filename string) error
-	SearchUser(MinAge, MaxAge int, City, Gender string) ([]entity.User, error)
+	CreateUser(name, city, gender, description string, interestedIn []string, age int, telegramID int64, file []byte, filename, languageCode string) error
+	SearchUser(MinAge, MaxAge int, City string, Genders []string, InterestedIn string, ExcludeFor int64) ([]entity.User, error)
 	Delete(id int64) error
 	GetUserByID(userID int64) (*entity.User, error)
+	TouchActivity(userID int64) error
+	SetLanguage(userID int64, languageCode string) error
+	Reactivate(userID int64) error
+	ListAttributes() ([]entity.AttributeDefinition, error)
+	SetAttribute(userID int64, key string, values []string) error
+	NextQuestions(userID int64, limit int) ([]entity.Question, error)
+	AnswerQuestion(userID int64, answer entity.QuestionAnswer) error
+	NotificationSettings(userID int64) (*entity.NotificationSettings, error)
+	SaveNotificationSettings(userID int64, settings entity.NotificationSettings) error
 }
 
 type MatchService interface {
-	LikeUser(fromUserID, toUserID int64) error
+	LikeUser(like entity.Like) (*entity.Quota, error)
+	Quota(userID int64) (*entity.Quota, error)
+	HasLiked(fromUserID, toUserID int64) (bool, error)
+	IncomingLikes(userID int64, offset, limit int) ([]entity.LikeItem, bool, error)
+	OutgoingLikes(userID int64, offset, limit int) ([]entity.LikeItem, bool, error)
+	Matches(userID int64, offset, limit int) ([]entity.MatchItem, bool, error)
+	EndMatch(matchID, userID int64) error
+	FollowUpMatch(matchID, userID int64) error
+	Dislike(fromUserID, toUserID int64) error
+	Undo(userID int64) (*entity.Swipe, *entity.Quota, error)
 }
 
 type UseCase struct {
 	userService  UserService
 	matchService MatchService
+
+	flowsMu        sync.Mutex
+	attributeFlows map[int64]*attributeFlow
+	listFlows      map[int64]*listFlow
+	questionFlows  map[int64]*questionFlow
+	nonces         map[int64]string
+	sessions       map[int64]*session
+	catalog        *i18n.Catalog
+	// photos - file_id уже загруженных в Telegram фото анкет
+	photos *photoCache
+	// bot - запущенный бот, через него отправляются уведомления из serviceNotification
+	bot *telebot.Bot
 }
 
 func NewUseCase(userService UserService, matchService MatchService) *UseCase {
-	return &UseCase{userService: userService, matchService: matchService}
+	return &UseCase{
+		userService:    userService,
+		matchService:   matchService,
+		attributeFlows: make(map[int64]*attributeFlow),
+		listFlows:      make(map[int64]*listFlow),
+		questionFlows:  make(map[int64]*questionFlow),
+		nonces:         make(map[int64]string),
+		sessions:       make(map[int64]*session),
+		catalog:        i18n.New(),
+		photos:         newPhotoCache(),
+	}
 }
 
 var users = make(map[int64]entity.User)
-var usersLike = make([]entity.User, 0)
-var outID int64
 
-func (uc *UseCase) StartBot(token string) {
+// StartBot - запускает бота; poller определяет, как приходят обновления:
+// long polling или вебхук
+func (uc *UseCase) StartBot(token string, poller telebot.Poller) {
 	if token == "" {
 		log.Fatal("Token empty")
 	}
-	var state int
-	var autho int
-	var likes int
-
 	b, err := telebot.NewBot(telebot.Settings{
 		Token:  token,
-		Poller: &telebot.LongPoller{Timeout: 10},
+		Poller: poller,
 	})
 
 	if err != nil {
 		log.Fatalf("Ошибка при создании бота: %v", err)
 	}
 
-	b.Handle("/start", func(ctx telebot.Context) error {
-		user, err := uc.userService.GetUserByID(ctx.Sender().ID)
-		if err != nil || user == nil {
-			state = 1
-			ctx.Send("Привет, это бот для знакомств!")
-			time.Sleep(1 * time.Second)
-			ctx.Send("Давай создадим твою анкету!")
-			time.Sleep(1 * time.Second)
-			state = 1
-			return ctx.Send("Как тебя зовут?", &telebot.ReplyMarkup{RemoveKeyboard: true})
-		}
+	b.Use(uc.trackActivity)
+	uc.flowsMu.Lock()
+	uc.bot = b
+	uc.flowsMu.Unlock()
 
-		ctx.Send("Вот так выглядит твоя анкета:", &telebot.ReplyMarkup{RemoveKeyboard: true})
-		fmt.Println(user)
-		imageBytes, err := utilites.DownloadImageAsBytes(user.Photo)
-		if err != nil {
-			log.Println(err)
-			return ctx.Send("Ошибка загрузки фотографии из бд")
+	uc.registerCommands(b)
+
+	// Inline-кнопки меню и карточек; нажатия на устаревшие клавиатуры отклоняются
+	b.Handle(&menuButton, func(ctx telebot.Context) error {
+		s := uc.session(ctx.Sender().ID)
+		data, ok := uc.callback(ctx)
+		if !ok {
+			return uc.rejectCallback(ctx)
 		}
-		Answer := &telebot.Photo{
-			File:    telebot.FromReader(bytes.NewReader(imageBytes)),
-			Caption: fmt.Sprintf("%s, %d, %s - %s", user.Name, user.Age, user.City, user.Description),
+		ctx.Respond()
+		if data.Action == menuStart {
+			return uc.showNextProfile(ctx, s)
 		}
-		autho = 1
-		ctx.Send(Answer)
+		choice, err := strconv.Atoi(data.Action)
+		if err != nil {
+			return nil
+		}
+		s.likes = 0
+		return uc.openMenu(ctx, s, choice)
+	})
+
+	b.Handle(&handoffButton, uc.handleHandoff)
 
-		profileKeys := [][]telebot.ReplyButton{
-			{{Text: "1"}, {Text: "2"}, {Text: "3"}},
+	b.Handle(&cardButton, func(ctx telebot.Context) error {
+		s := uc.session(ctx.Sender().ID)
+		data, ok := uc.callback(ctx)
+		if !ok || s.likes != 2 || data.Target != s.outID {
+			return uc.rejectCallback(ctx)
 		}
-		return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
+		ctx.Respond()
+		action, promptID := data.Action, (*int)(nil)
+		if action == cardPrompt {
+			n, err := strconv.Atoi(data.Arg)
+			if err != nil || n < 1 || n > len(s.outPrompts) {
+				return nil
+			}
+			id := s.outPrompts[n-1].PromptID
+			promptID = &id
+		}
+		return uc.swipe(ctx, action, promptID, s)
 	})
 
 	///////////////////////////////////////////////////////////////////////////////////////////////////////
 	b.Handle(telebot.OnText, func(ctx telebot.Context) error {
+		s := uc.session(ctx.Sender().ID)
+		if flow := uc.attributeFlow(ctx.Sender().ID); flow != nil {
+			return uc.handleAttributeFlow(ctx, flow)
+		}
+		if flow := uc.questionFlow(ctx.Sender().ID); flow != nil {
+			return uc.handleQuestionFlow(ctx, flow)
+		}
+		if matchID, ok := matchCommand(ctx.Text(), unmatchCommand); ok {
+			return uc.endMatch(ctx, matchID)
+		}
+		if matchID, ok := matchCommand(ctx.Text(), keepCommand); ok {
+			return uc.keepMatch(ctx, matchID)
+		}
+		if flow := uc.listFlow(ctx.Sender().ID); flow != nil {
+			if handled, err := uc.handleListFlow(ctx, flow); handled {
+				return err
+			}
+		}
 		if ctx.Text() == "Cмотреть" {
-			autho = 1
-			likes = 0
+			s.autho = 1
+			s.likes = 0
 			return nil
 		}
+		// Старая reply-клавиатура уведомления о лайке: лайкнувший неизвестен, показываем список
 		if ctx.Text() == "Показать анкету" {
-			autho = 0
-			likes = 0
-			return nil
+			s.likes = 0
+			return uc.openMenu(ctx, s, 4)
 		}
-		if ctx.Text() == "❤" || ctx.Text() == "👎" {
-			autho = 1
-			return nil
+		if s.likes != 2 && (ctx.Text() == "❤" || ctx.Text() == "👎") {
+			s.autho = 1
+			return uc.sendMainMenu(ctx)
 		}
-		if likes == 1 {
-			if len(usersLike) <= 0 {
-				profileKeys := [][]telebot.ReplyButton{
-					{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-				}
-				likes = 0
-				autho = 1
-				ctx.Send("Анкеты закончились :(")
-				return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-			}
-			outUser := usersLike[len(usersLike)-1]
-			outID = outUser.TelegramID
-			usersLike = usersLike[:len(usersLike)-1]
-
-			image, err := utilites.DownloadImageAsBytes(outUser.Photo)
-			if err != nil {
-				profileKeys := [][]telebot.ReplyButton{
-					{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-				}
-				likes = 0
-				autho = 1
-				ctx.Send("произошла ошибка в боте:(")
-				return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-			}
-
-			Answer := &telebot.Photo{
-				File:    telebot.FromReader(bytes.NewReader(image)),
-				Caption: fmt.Sprintf("%s, %d, %s - %s", outUser.Name, outUser.Age, outUser.City, outUser.Description),
-			}
-			likes = 2
-			key := [][]telebot.ReplyButton{
-				{{Text: "❤"}, {Text: "👎"}, {Text: "💤"}},
+		if s.likes == 1 {
+			return uc.showNextProfile(ctx, s)
+		}
+		if s.likes == 2 {
+			// Старая reply-клавиатура карточки: те же действия, что у inline-кнопок
+			if action, promptID, ok := swipeAction(ctx.Text(), s.outPrompts); ok {
+				return uc.swipe(ctx, action, promptID, s)
 			}
-			return ctx.Send(Answer, &telebot.ReplyMarkup{ReplyKeyboard: key, ResizeKeyboard: true})
 		}
-		if likes == 2 {
 
-			if ctx.Text() == "❤" {
-				err := uc.matchService.LikeUser(ctx.Sender().ID, outID)
-				if err != nil {
-					log.Println("fdsfsdfsd", err)
-					profileKeys := [][]telebot.ReplyButton{
-						{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-					}
-					likes = 0
-					autho = 1
-					ctx.Send("произошла ошибка в боте:(")
-					return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-				}
-				if len(usersLike) <= 0 {
-					profileKeys := [][]telebot.ReplyButton{
-						{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-					}
-					likes = 0
-					autho = 1
-					ctx.Send("Анкеты закончились :(")
-					return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-				}
-				outUser := usersLike[len(usersLike)-1]
-				outID = outUser.TelegramID
-				usersLike = usersLike[:len(usersLike)-1]
-
-				image, err := utilites.DownloadImageAsBytes(outUser.Photo)
-				if err != nil {
-					profileKeys := [][]telebot.ReplyButton{
-						{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-					}
-					likes = 0
-					autho = 1
-					ctx.Send("произошла ошибка в боте:(")
-					return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-				}
-
-				Answer := &telebot.Photo{
-					File:    telebot.FromReader(bytes.NewReader(image)),
-					Caption: fmt.Sprintf("%s, %d, %s - %s", outUser.Name, outUser.Age, outUser.City, outUser.Description),
-				}
-				likes = 2
-				key := [][]telebot.ReplyButton{
-					{{Text: "❤"}, {Text: "👎"}, {Text: "💤"}},
-				}
-				return ctx.Send(Answer, &telebot.ReplyMarkup{ReplyKeyboard: key, ResizeKeyboard: true})
+		if s.likes == 3 {
+			quota, err := uc.matchService.LikeUser(entity.Like{FromUserID: ctx.Sender().ID, ToUserID: s.outID, Comment: ctx.Text()})
+			if errors.Is(err, clientsMatch.ErrInvalidLike) {
+				return ctx.Send(uc.tr(ctx, "swipe.comment_invalid"))
 			}
-
-			if ctx.Text() == "👎" {
-				if len(usersLike) <= 0 {
-					profileKeys := [][]telebot.ReplyButton{
-						{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-					}
-					likes = 0
-					autho = 1
-					ctx.Send("Анкеты закончились :(")
-					return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-				}
-				outUser := usersLike[len(usersLike)-1]
-				outID = outUser.TelegramID
-				usersLike = usersLike[:len(usersLike)-1]
-
-				image, err := utilites.DownloadImageAsBytes(outUser.Photo)
-				if err != nil {
-					profileKeys := [][]telebot.ReplyButton{
-						{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-					}
-					likes = 0
-					autho = 1
-					ctx.Send("произошла ошибка в боте:(")
-					return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-				}
-
-				Answer := &telebot.Photo{
-					File:    telebot.FromReader(bytes.NewReader(image)),
-					Caption: fmt.Sprintf("%s, %d, %s - %s", outUser.Name, outUser.Age, outUser.City, outUser.Description),
-				}
-				likes = 2
-				key := [][]telebot.ReplyButton{
-					{{Text: "❤"}, {Text: "👎"}, {Text: "💤"}},
-				}
-				return ctx.Send(Answer, &telebot.ReplyMarkup{ReplyKeyboard: key, ResizeKeyboard: true})
+			if errors.Is(err, clientsMatch.ErrTooManySwipes) {
+				return ctx.Send(uc.tr(ctx, "swipe.too_fast") + " " + uc.tr(ctx, "swipe.comment_retry"))
 			}
-
-			if ctx.Text() == "💤" {
-				log.Println("dasdasd")
-				likes = 0
-				autho = 1
-				profileKeys := [][]telebot.ReplyButton{
-					{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-				}
-				return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
+			if errors.Is(err, clientsMatch.ErrQuotaExceeded) {
+				s.likes = 0
+				s.autho = 1
+				ctx.Send(uc.quotaExceededText(ctx, quota, false))
+				return uc.sendMainMenu(ctx)
 			}
-		}
-
-		if autho > 0 {
-			user, err := uc.userService.GetUserByID(ctx.Sender().ID)
-			autho, err = strconv.Atoi(ctx.Text())
 			if err != nil {
-				return ctx.Send("Нет такого варианта ответа")
-			}
-			autho += 1
-
-			if autho == 2 {
-				gender := "Парень"
-				if user.Gender == "Парень" {
-					gender = "Девушка"
-				}
-				usersGet, err := uc.userService.SearchUser(user.Age-3, user.Age+3, user.City, gender)
-				if err != nil {
-					log.Println("Лоооооохвхыхвхы", err)
-					ctx.Send("Произашла ошибка! попробуй еще раз")
-					profileKeys := [][]telebot.ReplyButton{
-						{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-					}
-					return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-				}
-				if len(usersGet) > 0 {
-					usersLike = append(usersLike, usersGet...)
-					Answer := [][]telebot.ReplyButton{
-						{{Text: "Начать"}},
-					}
-					autho = 0
-					likes = 1
-					return ctx.Send("Смогли подобрать идеальную пару для тебя нажми \"Начать\"", &telebot.ReplyMarkup{ReplyKeyboard: Answer, ResizeKeyboard: true})
-				} else {
-					return ctx.Send("Не смогли подобрать тебе пару :(")
-				}
-			}
-
-			if autho == 3 {
-				if err != nil {
-					ctx.Send("Произашла ошибка! попробуй еще раз")
-					profileKeys := [][]telebot.ReplyButton{
-						{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-					}
-					return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-				}
-				fmt.Println(user)
-				imageBytes, err := utilites.DownloadImageAsBytes(user.Photo)
-				if err != nil {
-					log.Println(err)
-					return ctx.Send("Ошибка загрузки фотографии из бд")
-				}
-				Answer := &telebot.Photo{
-					File:    telebot.FromReader(bytes.NewReader(imageBytes)),
-					Caption: fmt.Sprintf("%s, %d, %s - %s", user.Name, user.Age, user.City, user.Description),
-				}
-				ctx.Send(Answer)
-				profileKeys := [][]telebot.ReplyButton{
-					{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-				}
-				return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
+				log.Println("Ошибка отправки лайка с сообщением:", err)
+				ctx.Send(uc.tr(ctx, "error"))
 			}
+			return uc.showNextProfile(ctx, s)
+		}
 
-			if autho == 4 {
-				err := uc.userService.Delete(ctx.Sender().ID)
-				if err != nil {
-					log.Fatal(err)
-				}
-				state = 1
-				autho = 0
-				return ctx.Send("Как тебя зовут?", &telebot.ReplyMarkup{RemoveKeyboard: true})
+		if s.autho > 0 {
+			choice, err := strconv.Atoi(ctx.Text())
+			if err != nil {
+				return ctx.Send(uc.tr(ctx, "no_such_option"))
 			}
+			return uc.openMenu(ctx, s, choice)
 		}
 
-		if state > 0 {
+		if s.state > 0 {
 			user := users[ctx.Sender().ID]
-			if state == 1 {
+			if s.state == 1 {
 				user.Name = ctx.Text()
 				users[ctx.Sender().ID] = user
-				state = 2
-				return ctx.Send("Теперь укажи свой возраст:")
+				s.state = 2
+				return ctx.Send(uc.tr(ctx, "registration.age"))
 			}
 
-			if state == 2 {
+			if s.state == 2 {
 				age, err := strconv.Atoi(ctx.Text())
 				if err != nil {
-					return ctx.Send("Возраст должен быть числом!")
+					return ctx.Send(uc.tr(ctx, "registration.age_invalid"))
 				}
 				user.Age = age
 				users[ctx.Sender().ID] = user
-				state = 3
-				return ctx.Send("В каком городе ты живешь?")
+				s.state = 3
+				return ctx.Send(uc.tr(ctx, "registration.city"))
 			}
 
-			if state == 3 {
+			if s.state == 3 {
 				user.City = ctx.Text()
 				users[ctx.Sender().ID] = user
-				state = 4
-				genderKeys := [][]telebot.ReplyButton{
-					{{Text: "Парень"}, {Text: "Девушка"}},
+				s.state = 4
+				return ctx.Send(uc.tr(ctx, "registration.gender"), &telebot.ReplyMarkup{ReplyKeyboard: uc.genderKeyboard(ctx), ResizeKeyboard: true})
+			}
+
+			if s.state == 4 {
+				code, ok := uc.genderCode(ctx.Text())
+				if !ok {
+					ctx.Send(uc.tr(ctx, "registration.gender_invalid"))
+					return ctx.Send(uc.tr(ctx, "registration.gender"), &telebot.ReplyMarkup{ReplyKeyboard: uc.genderKeyboard(ctx), ResizeKeyboard: true})
 				}
-				return ctx.Send("Выбери свой пол:", &telebot.ReplyMarkup{ReplyKeyboard: genderKeys, ResizeKeyboard: true})
+				user.Gender = code
+				user.InterestedIn = nil
+				users[ctx.Sender().ID] = user
+				s.state = 5
+				return ctx.Send(uc.tr(ctx, "registration.interests"), &telebot.ReplyMarkup{ReplyKeyboard: uc.interestKeyboard(ctx, user.InterestedIn), ResizeKeyboard: true})
 			}
 
-			if state == 4 {
-				if ctx.Text() != "Парень" && ctx.Text() != "Девушка" {
-					ctx.Send("Такого пола нет!")
-					genderKeys := [][]telebot.ReplyButton{
-						{{Text: "Парень"}, {Text: "Девушка"}},
+			if s.state == 5 {
+				if uc.isButton(ctx.Text(), "button.done") {
+					if len(user.InterestedIn) == 0 {
+						return ctx.Send(uc.tr(ctx, "registration.interests_empty"), &telebot.ReplyMarkup{ReplyKeyboard: uc.interestKeyboard(ctx, user.InterestedIn), ResizeKeyboard: true})
 					}
-					return ctx.Send("Выбери свой пол:", &telebot.ReplyMarkup{ReplyKeyboard: genderKeys, ResizeKeyboard: true})
+					s.state = 6
+					return ctx.Send(uc.tr(ctx, "registration.description"), &telebot.ReplyMarkup{RemoveKeyboard: true})
 				}
-				user.Gender = ctx.Text()
+				code, ok := uc.genderCode(ctx.Text())
+				if !ok {
+					return ctx.Send(uc.tr(ctx, "no_such_option"), &telebot.ReplyMarkup{ReplyKeyboard: uc.interestKeyboard(ctx, user.InterestedIn), ResizeKeyboard: true})
+				}
+				user.InterestedIn = toggleCode(user.InterestedIn, code)
 				users[ctx.Sender().ID] = user
-				state = 5
-				return ctx.Send("Напиши описание к своей анкете:", &telebot.ReplyMarkup{RemoveKeyboard: true})
+				return ctx.Send(uc.tr(ctx, "registration.interest_toggled"), &telebot.ReplyMarkup{ReplyKeyboard: uc.interestKeyboard(ctx, user.InterestedIn), ResizeKeyboard: true})
 			}
 
-			if state == 5 {
+			if s.state == 6 {
 				user.Description = ctx.Text()
-				state = 6
+				s.state = 7
 				users[ctx.Sender().ID] = user
-				return ctx.Send("Пришли фото для анкеты:")
+				return ctx.Send(uc.tr(ctx, "registration.photo"))
 			}
 		}
 		return nil
 	})
 
 	b.Handle(telebot.OnPhoto, func(ctx telebot.Context) error {
-		if state == 6 {
+		s := uc.session(ctx.Sender().ID)
+		if s.state == 7 {
 			photo := ctx.Message().Photo
 			if photo == nil {
-				return ctx.Send("Ошибка при получении фотографии. Отправь фото еще раз")
+				return ctx.Send(uc.tr(ctx, "registration.photo_error"))
 			}
 
 			file, err := b.FileByID(photo.FileID)
 			if err != nil {
-				return ctx.Send("Ошибка при получении файла. Попробуйте еще раз.")
+				return ctx.Send(uc.tr(ctx, "registration.file_error"))
 			}
 
 			if file.FilePath == "" {
-				return ctx.Send("Ошибка: путь к файлу отсутствует.")
+				return ctx.Send(uc.tr(ctx, "registration.file_path_error"))
 			}
 
 			log.Println("Фото получено: ", file.FilePath)
@@ -380,39 +303,38 @@ func (uc *UseCase) StartBot(token string) {
 
 			fileReader, err := b.File(&file)
 			if err != nil {
-				return ctx.Send("Ошибка при чтении файла.")
+				return ctx.Send(uc.tr(ctx, "registration.file_read_error"))
 			}
 
 			fileData, err := io.ReadAll(fileReader)
 			if err != nil {
-				return ctx.Send("Ошибка при чтении файла. Попробуйте еще раз.")
+				return ctx.Send(uc.tr(ctx, "registration.file_read_error"))
 			}
 
 			user := users[ctx.Sender().ID]
 			users[ctx.Sender().ID] = user
-			state = 0
+			s.state = 0
 
-			err = uc.userService.CreateUser(user.Name, user.City, user.Gender, user.Description, user.Age, ctx.Sender().ID, fileData, filePatch)
+			err = uc.userService.CreateUser(user.Name, user.City, user.Gender, user.Description, user.InterestedIn, user.Age, ctx.Sender().ID, fileData, filePatch, ctx.Sender().LanguageCode)
 			if err != nil {
 				log.Println(err)
-				return ctx.Send("Ошибка при отправке в базу. Попробуйте еще раз.")
+				return ctx.Send(uc.tr(ctx, "registration.save_error"))
 			}
 
-			ctx.Send("Анкета Успешно создана! 🎉")
+			// Язык записан вместе с анкетой
+			uc.setSavedLanguage(ctx.Sender().ID, ctx.Sender().LanguageCode)
+			ctx.Send(uc.tr(ctx, "registration.done"))
 			time.Sleep(50 * time.Millisecond)
 
-			ctx.Send("Твоя анкета:")
+			ctx.Send(uc.tr(ctx, "registration.your_profile"))
 			Answer := &telebot.Photo{
 				File:    telebot.FromReader(bytes.NewReader(fileData)),
-				Caption: fmt.Sprintf("%s, %d, %s - %s", user.Name, user.Age, user.City, user.Description),
+				Caption: uc.profileCaption(ctx, &user),
 			}
 			delete(users, ctx.Sender().ID)
-			autho = 1
+			s.autho = 1
 			ctx.Send(Answer)
-			profileKeys := [][]telebot.ReplyButton{
-				{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-			}
-			return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
+			return uc.sendMainMenu(ctx)
 		}
 		return nil
 	})
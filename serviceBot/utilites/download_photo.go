@@ -1,23 +1,61 @@
 package utilites
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
+const (
+	// MaxImageSize - предельный размер скачиваемой фотографии (лимит Telegram для фото - 10 МБ)
+	MaxImageSize = 10 << 20
+	// DownloadTimeout - общий тайм-аут на скачивание одной фотографии
+	DownloadTimeout = 15 * time.Second
+)
+
+// ErrImageTooLarge - фотография больше MaxImageSize
+var ErrImageTooLarge = errors.New("image is too large")
+
+// downloadClient - общий клиент для скачивания фото: без тайм-аута зависший MinIO
+// держал бы обработчик апдейта бесконечно
+var downloadClient = &http.Client{Timeout: DownloadTimeout}
+
+// DownloadImageAsBytes - скачивает фотографию по ссылке с тайм-аутом DownloadTimeout
 func DownloadImageAsBytes(url string) ([]byte, error) {
-	// Отправляем GET-запрос
-	resp, err := http.Get(url)
+	return DownloadImage(context.Background(), url)
+}
+
+// DownloadImage - скачивает фотографию по ссылке. Ответ не 200 или тело больше
+// MaxImageSize считаются ошибкой, чтобы не отправлять в Telegram обрезанный файл
+func DownloadImage(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := downloadClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// Читаем все байты из ответа
-	imageBytes, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: unexpected status %d", url, resp.StatusCode)
+	}
+	if resp.ContentLength > MaxImageSize {
+		return nil, fmt.Errorf("download %s: %w", url, ErrImageTooLarge)
+	}
+
+	// Читаем на байт больше лимита, чтобы отличить файл ровно в лимит от превышения
+	imageBytes, err := io.ReadAll(io.LimitReader(resp.Body, MaxImageSize+1))
 	if err != nil {
 		return nil, err
 	}
+	if len(imageBytes) > MaxImageSize {
+		return nil, fmt.Errorf("download %s: %w", url, ErrImageTooLarge)
+	}
 
 	return imageBytes, nil
 }
@@ -0,0 +1,61 @@
+package utilites
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDownloadImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("jpeg"))
+	}))
+	defer server.Close()
+
+	got, err := DownloadImageAsBytes(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "jpeg" {
+		t.Errorf("got %q, want jpeg", got)
+	}
+}
+
+func TestDownloadImageTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Без Content-Length, чтобы сработало ограничение чтения, а не заголовок
+		w.(http.Flusher).Flush()
+		w.Write(bytes.Repeat([]byte{0}, MaxImageSize+1))
+	}))
+	defer server.Close()
+
+	if _, err := DownloadImageAsBytes(server.URL); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("err = %v, want ErrImageTooLarge", err)
+	}
+}
+
+func TestDownloadImageStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := DownloadImageAsBytes(server.URL); err == nil {
+		t.Fatal("expected error for 404")
+	}
+}
+
+func TestDownloadImageContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := DownloadImage(ctx, server.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+}
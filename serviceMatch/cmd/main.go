@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
+	clientsUser "service3/internal/client"
 	"service3/internal/config"
 	"service3/internal/handler"
 	"service3/internal/repository"
 	"service3/internal/storage"
 	"service3/internal/usecase"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -30,18 +33,67 @@ func InitMatchUse(cfg *config.Config) (*gin.Engine, error) {
 		return nil, fmt.Errorf("unable to initialize Kafka producer: %w", err)
 	}
 
+	// Клиент serviceUser
+	userClient := clientsUser.NewHTTPUserServiseClient(cfg.USER_SERVICE)
+
+	// Дневные квоты лайков
+	quota, err := initQuota(cfg, pool)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize quotas: %w", err)
+	}
+	quota.SetTimeZones(userClient)
+
 	// Логика UseCase
-	uc := usecase.NewUseCase(repo, kfk)
+	uc := usecase.NewUseCase(repo, kfk, userClient, quota)
+	uc.SetUndoWindow(cfg.UNDO_WINDOW)
+
+	// Антиспам: существование анкет, частота свайпов и поведение
+	limits := usecase.DefaultSpamLimits
+	limits.PerMinute = cfg.SWIPE_LIMIT_PER_MINUTE
+	limits.PerHour = cfg.SWIPE_LIMIT_PER_HOUR
+	limits.Window = cfg.SPAM_WINDOW
+	limits.MinSwipes = cfg.SPAM_MIN_SWIPES
+	antiSpam := usecase.NewAntiSpam(repository.NewSpamRepository(pool), userClient, limits)
+	uc.SetAntiSpam(antiSpam)
+
+	// Автоистечение пар без продолжения
+	if cfg.MATCH_EXPIRY > 0 {
+		go uc.RunMatchExpirer(context.Background(), cfg.MATCH_EXPIRY_INTERVAL, cfg.MATCH_EXPIRY, cfg.MATCH_EXPIRY_REMINDER)
+	}
 
 	// Gin router
 	router := gin.Default()
 
 	// Обработчики
 	handler.NewMatchHandler(uc, router)
+	handler.NewSpamHandler(antiSpam, router, cfg.ADMIN_TOKEN)
 
 	return router, nil
 }
 
+func initQuota(cfg *config.Config, pool *pgxpool.Pool) (*usecase.Quota, error) {
+	location, err := time.LoadLocation(cfg.QUOTA_TIMEZONE)
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUOTA_TIMEZONE: %w", err)
+	}
+
+	// Без Redis счетчики ведутся только в Postgres
+	var primary usecase.QuotaCounter
+	redisQuota, err := storage.NewRedisQuota(cfg.REDIS_ADDR)
+	if err != nil {
+		log.Printf("Redis is not available, quotas are stored in Postgres: %v", err)
+	} else {
+		primary = redisQuota
+	}
+
+	return usecase.NewQuota(primary, repository.NewQuotaRepository(pool), usecase.QuotaLimits{
+		Likes:      cfg.DAILY_LIKE_LIMIT,
+		SuperLikes: cfg.DAILY_SUPER_LIKE_LIMIT,
+		Undos:      cfg.DAILY_UNDO_LIMIT,
+		Location:   location,
+	}), nil
+}
+
 func initPostgresPool(cfg *config.Config) (*pgxpool.Pool, error) {
 	pool, err := pgxpool.New(context.Background(), cfg.DATABASE_URL)
 	if err != nil {
@@ -0,0 +1,160 @@
+package clientsUser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"service3/internal/entity"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type HTTPUserServiseClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPUserServiseClient(baseURL string) *HTTPUserServiseClient {
+	return &HTTPUserServiseClient{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// TouchActivity сообщает serviceUser, что пользователь только что был активен
+func (c *HTTPUserServiseClient) TouchActivity(ctx context.Context, userID int64) error {
+	url := fmt.Sprintf("%s/users/%d/activity", c.baseURL, userID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// HideUser навсегда убирает hiddenID из выдачи пользователя userID
+func (c *HTTPUserServiseClient) HideUser(ctx context.Context, userID, hiddenID int64) error {
+	url := fmt.Sprintf("%s/users/%d/hidden/%d", c.baseURL, userID, hiddenID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// RateUser - исход свайпа для Elo-рейтинга анкеты targetID
+func (c *HTTPUserServiseClient) RateUser(ctx context.Context, targetID, raterID int64, liked bool) error {
+	return c.sendRating(ctx, targetID, map[string]interface{}{"rater_id": raterID, "liked": liked})
+}
+
+// UnrateUser - откат Elo-обновления после отмены свайпа
+func (c *HTTPUserServiseClient) UnrateUser(ctx context.Context, targetID, raterID int64, liked bool) error {
+	return c.sendRating(ctx, targetID, map[string]interface{}{"rater_id": raterID, "liked": liked, "undo": true})
+}
+
+func (c *HTTPUserServiseClient) sendRating(ctx context.Context, targetID int64, payload map[string]interface{}) error {
+	url := fmt.Sprintf("%s/users/%d/rating", c.baseURL, targetID)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// TimeZone - часовой пояс пользователя из его настроек уведомлений
+func (c *HTTPUserServiseClient) TimeZone(ctx context.Context, userID int64) (string, error) {
+	url := fmt.Sprintf("%s/users/%d/notification-settings", c.baseURL, userID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var settings struct {
+		TimeZone string `json:"time_zone"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return "", fmt.Errorf("failed to decode notification settings: %w", err)
+	}
+	return settings.TimeZone, nil
+}
+
+// GetProfiles - пакетное получение анкет по Telegram ID
+func (c *HTTPUserServiseClient) GetProfiles(ctx context.Context, ids []int64) ([]entity.Profile, error) {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+	url := fmt.Sprintf("%s/users/batch?ids=%s", c.baseURL, strings.Join(parts, ","))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var profiles []entity.Profile
+	if err := json.NewDecoder(resp.Body).Decode(&profiles); err != nil {
+		return nil, fmt.Errorf("failed to decode profiles: %w", err)
+	}
+	return profiles, nil
+}
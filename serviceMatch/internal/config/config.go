@@ -3,7 +3,9 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -12,6 +14,32 @@ type Config struct {
 	SERVICE_MATCH    string
 	KAFKA_LIKE_TOPIC string
 	KAFKA_GROUP_ID   string
+	USER_SERVICE     string
+
+	// Автоистечение пар без продолжения; 0 - выключено
+	MATCH_EXPIRY          time.Duration
+	MATCH_EXPIRY_REMINDER time.Duration
+	MATCH_EXPIRY_INTERVAL time.Duration
+
+	// Дневные квоты: счетчики в Redis, сброс в полночь по часовому поясу
+	// пользователя, QUOTA_TIMEZONE - если его пояс неизвестен
+	REDIS_ADDR             string
+	DAILY_LIKE_LIMIT       int
+	DAILY_SUPER_LIKE_LIMIT int
+	QUOTA_TIMEZONE         string
+
+	// Отмена последнего свайпа: окно и дневной лимит
+	UNDO_WINDOW      time.Duration
+	DAILY_UNDO_LIMIT int
+
+	// Антиспам: лимиты частоты свайпов и окно анализа поведения
+	SWIPE_LIMIT_PER_MINUTE int
+	SWIPE_LIMIT_PER_HOUR   int
+	SPAM_WINDOW            time.Duration
+	SPAM_MIN_SWIPES        int
+
+	// Токен админских эндпоинтов (отчет антиспама); пустой - они закрыты
+	ADMIN_TOKEN string
 }
 
 func NewConfig() *Config {
@@ -24,6 +52,26 @@ func NewConfig() *Config {
 		KAFKA_URL:        brokers,
 		KAFKA_LIKE_TOPIC: getEnv("KAFKA_LIKE_TOPIC", ""),
 		KAFKA_GROUP_ID:   getEnv("KAFKA_GROUP_ID", ""),
+		USER_SERVICE:     getEnv("USER_SERVICE", "http://serviceUser:8080"),
+
+		MATCH_EXPIRY:          getEnvDuration("MATCH_EXPIRY", 0),
+		MATCH_EXPIRY_REMINDER: getEnvDuration("MATCH_EXPIRY_REMINDER", 24*time.Hour),
+		MATCH_EXPIRY_INTERVAL: getEnvDuration("MATCH_EXPIRY_INTERVAL", 10*time.Minute),
+
+		REDIS_ADDR:             getEnv("REDIS_ADDR", "redis:6379"),
+		DAILY_LIKE_LIMIT:       getEnvInt("DAILY_LIKE_LIMIT", 50),
+		DAILY_SUPER_LIKE_LIMIT: getEnvInt("DAILY_SUPER_LIKE_LIMIT", 1),
+		QUOTA_TIMEZONE:         getEnv("QUOTA_TIMEZONE", "Europe/Moscow"),
+
+		UNDO_WINDOW:      getEnvDuration("UNDO_WINDOW", 5*time.Minute),
+		DAILY_UNDO_LIMIT: getEnvInt("DAILY_UNDO_LIMIT", 3),
+
+		SWIPE_LIMIT_PER_MINUTE: getEnvInt("SWIPE_LIMIT_PER_MINUTE", 30),
+		SWIPE_LIMIT_PER_HOUR:   getEnvInt("SWIPE_LIMIT_PER_HOUR", 300),
+		SPAM_WINDOW:            getEnvDuration("SPAM_WINDOW", 24*time.Hour),
+		SPAM_MIN_SWIPES:        getEnvInt("SPAM_MIN_SWIPES", 30),
+
+		ADMIN_TOKEN: getEnv("ADMIN_TOKEN", ""),
 	}
 }
 
@@ -33,3 +81,21 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,59 @@
+package entity
+
+import "time"
+
+// Like - лайк от одного пользователя другому
+type Like struct {
+	FromUserID int64 `json:"from_user_id"`
+	ToUserID   int64 `json:"to_user_id"`
+	// Target - на что именно поставлен лайк: фото или вопрос-карточка
+	Target string `json:"target,omitempty"`
+	// PromptID - вопрос-карточка из анкеты получателя, на который отреагировали
+	PromptID *int `json:"prompt_id,omitempty"`
+	// Comment - короткое сообщение к лайку
+	Comment string `json:"comment,omitempty"`
+	// Super - суперлайк: отдельная дневная квота и приоритетное уведомление
+	Super bool `json:"super,omitempty"`
+	// Limited - лайк аккаунта под теневым ограничением, рейтинг получателя он не меняет
+	Limited bool `json:"-"`
+}
+
+// Цели лайка
+const (
+	TargetPhoto  = "photo"
+	TargetPrompt = "prompt"
+)
+
+// Типы событий в Kafka
+const (
+	EventLike    = "like"
+	EventMatch   = "match"
+	EventUnmatch = "unmatch"
+	// EventMatchEnded - пара разорвана одним из участников или истекла
+	EventMatchEnded = "match.ended"
+	// EventMatchExpiring - напоминание, что пара скоро истечет
+	EventMatchExpiring = "match.expiring"
+	// EventLikeUndone - лайк отменен кнопкой «↩️», уведомление о нем нужно отозвать
+	EventLikeUndone = "like.undone"
+)
+
+// PriorityHigh - приоритет доставки суперлайков
+const PriorityHigh = "high"
+
+// Event - событие, которое serviceMatch отправляет в Kafka
+type Event struct {
+	Type       string `json:"type"`
+	FromUserID int64  `json:"from_user_id"`
+	ToUserID   int64  `json:"to_user_id"`
+	Target     string `json:"target,omitempty"`
+	PromptID   *int   `json:"prompt_id,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+	Super      bool   `json:"super,omitempty"`
+	// Priority - high для событий, которые нужно доставить в первую очередь
+	Priority string `json:"priority,omitempty"`
+	MatchID  int64  `json:"match_id,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	// ExpiresAt - когда истечет пара (для match.expiring)
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
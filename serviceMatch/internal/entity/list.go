@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// Profile - краткая анкета из serviceUser для списков лайков и пар
+type Profile struct {
+	TelegramID  int64     `json:"telegram_id"`
+	Name        string    `json:"name"`
+	Age         int       `json:"age"`
+	City        string    `json:"city,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Photo       string    `json:"photo,omitempty"`
+	LastActive  time.Time `json:"last_active_at"`
+}
+
+// LikeItem - элемент списка входящих или исходящих лайков.
+// UserID - второй участник: кто лайкнул или кого лайкнули
+type LikeItem struct {
+	UserID    int64     `json:"user_id"`
+	Target    string    `json:"target,omitempty"`
+	PromptID  *int      `json:"prompt_id,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Answered - для входящих: получатель уже ответил лайком
+	Answered bool     `json:"answered"`
+	Profile  *Profile `json:"profile,omitempty"`
+}
+
+// MatchItem - элемент списка пар
+type MatchItem struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Profile   *Profile  `json:"profile,omitempty"`
+}
+
+// PendingLikes - неотвеченные входящие лайки пользователя для напоминания
+type PendingLikes struct {
+	UserID int64     `json:"user_id"`
+	Count  int       `json:"count"`
+	Oldest time.Time `json:"oldest"`
+}
+
+// Page - параметры постраничной выдачи
+type Page struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// Причины завершения пары
+const (
+	EndReasonUnmatch = "unmatch"
+	EndReasonExpired = "expired"
+)
+
+// Match - пара пользователей, лайкнувших друг друга. UserA < UserB
+type Match struct {
+	ID           int64      `json:"id"`
+	UserA        int64      `json:"user_a"`
+	UserB        int64      `json:"user_b"`
+	CreatedAt    time.Time  `json:"created_at"`
+	EndedAt      *time.Time `json:"ended_at,omitempty"`
+	EndedBy      *int64     `json:"ended_by,omitempty"`
+	EndReason    string     `json:"end_reason,omitempty"`
+	FollowedUpAt *time.Time `json:"followed_up_at,omitempty"`
+}
+
+// Other - второй участник пары
+func (m Match) Other(userID int64) int64 {
+	if m.UserA == userID {
+		return m.UserB
+	}
+	return m.UserA
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// Виды дневных квот
+const (
+	QuotaLike      = "like"
+	QuotaSuperLike = "superlike"
+	QuotaUndo      = "undo"
+)
+
+// QuotaKey - счетчик квоты пользователя за конкретный день (YYYY-MM-DD)
+type QuotaKey struct {
+	UserID int64
+	Kind   string
+	Day    string
+}
+
+// Quota - сколько лайков и суперлайков осталось на сегодня
+type Quota struct {
+	Likes      int       `json:"likes"`
+	SuperLikes int       `json:"super_likes"`
+	Undos      int       `json:"undos"`
+	ResetsAt   time.Time `json:"resets_at"`
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// Причины пометки аккаунта антиспамом
+const (
+	// SpamReasonLikeRatio - лайкает почти все анкеты подряд
+	SpamReasonLikeRatio = "like_ratio"
+	// SpamReasonFastSwipes - большинство свайпов быстрее секунды
+	SpamReasonFastSwipes = "fast_swipes"
+)
+
+// SwipeStats - статистика свайпов пользователя. LastMinute и LastHour считаются
+// от текущего момента, остальные поля - за окно анализа поведения
+type SwipeStats struct {
+	LastMinute int `json:"last_minute"`
+	LastHour   int `json:"last_hour"`
+	Total      int `json:"total"`
+	Likes      int `json:"likes"`
+	// Fast - свайпы, сделанные быстрее порога после предыдущего
+	Fast int `json:"fast"`
+}
+
+// SpamFlag - аккаунт, помеченный антиспамом. Пока пометка не снята, его лайки
+// сохраняются, но получатели о них не узнают (теневое ограничение)
+type SpamFlag struct {
+	UserID    int64      `json:"user_id"`
+	Reason    string     `json:"reason"`
+	Swipes    int        `json:"swipes"`
+	Likes     int        `json:"likes"`
+	Fast      int        `json:"fast_swipes"`
+	FlaggedAt time.Time  `json:"flagged_at"`
+	ClearedAt *time.Time `json:"cleared_at,omitempty"`
+	Profile   *Profile   `json:"profile,omitempty"`
+}
+
+// Active - пометка есть и админ ее не снял
+func (f *SpamFlag) Active() bool {
+	return f != nil && f.ClearedAt == nil
+}
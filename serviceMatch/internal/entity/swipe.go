@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// Виды свайпов
+const (
+	SwipeLike      = "like"
+	SwipeSuperLike = "superlike"
+	SwipeDislike   = "dislike"
+)
+
+// Swipe - решение пользователя по анкете; последний свайп можно отменить
+type Swipe struct {
+	ID         int64     `json:"id"`
+	FromUserID int64     `json:"from_user_id"`
+	ToUserID   int64     `json:"to_user_id"`
+	Kind       string    `json:"kind"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Limited - свайп аккаунта под теневым ограничением, рейтинг по нему не менялся
+	Limited bool `json:"-"`
+}
+
+// IsLike - свайп оставил лайк, который нужно снять при отмене
+func (s Swipe) IsLike() bool {
+	return s.Kind == SwipeLike || s.Kind == SwipeSuperLike
+}
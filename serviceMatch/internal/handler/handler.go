@@ -2,7 +2,10 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
+	"service3/internal/entity"
 	"service3/internal/usecase"
 	"strconv"
 
@@ -17,6 +20,17 @@ type MatchHandler struct {
 func NewMatchHandler(uc *usecase.Usecase, router *gin.Engine) *MatchHandler {
 	handler := &MatchHandler{uc: uc, router: router}
 	router.POST("/like/:id1/:id2", handler.Like)
+	router.DELETE("/like/:id1/:id2", handler.Unlike)
+	router.GET("/like/:id1/:id2", handler.HasLiked)
+	router.GET("/users/:id/likes/incoming", handler.IncomingLikes)
+	router.GET("/users/:id/likes/outgoing", handler.OutgoingLikes)
+	router.GET("/users/:id/matches", handler.Matches)
+	router.GET("/likes/unanswered", handler.UnansweredLikes)
+	router.GET("/users/:id/quota", handler.Quota)
+	router.DELETE("/matches/:id", handler.EndMatch)
+	router.POST("/matches/:id/followup", handler.FollowUpMatch)
+	router.POST("/dislike/:id1/:id2", handler.Dislike)
+	router.POST("/swipes/:telegram_id/undo", handler.Undo)
 	return handler
 }
 
@@ -29,11 +43,109 @@ func (h *MatchHandler) Like(c *gin.Context) {
 		return
 	}
 
-	err := h.uc.Like(context.Background(), fromUserID, toUserID)
+	// Тело необязательное: в нем может быть комментарий и цель лайка (фото или вопрос-карточка)
+	var req struct {
+		Target   string `json:"target"`
+		PromptID *int   `json:"prompt_id"`
+		Comment  string `json:"comment"`
+		Super    bool   `json:"super"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	created, err := h.uc.Like(context.Background(), entity.Like{
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		Target:     req.Target,
+		PromptID:   req.PromptID,
+		Comment:    req.Comment,
+		Super:      req.Super,
+	})
+	if errors.Is(err, usecase.ErrQuotaExceeded) {
+		remaining, _ := h.uc.Remaining(context.Background(), fromUserID)
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "remaining": remaining})
+		return
+	}
+	if respondScreenError(c, err) {
+		return
+	}
+	if errors.Is(err, usecase.ErrInvalidLike) || errors.Is(err, usecase.ErrSelfLike) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	remaining, err := h.uc.Remaining(context.Background(), fromUserID)
+	if err != nil {
+		log.Printf("Error loading quota of user %d: %v", fromUserID, err)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Like saved", "created": created, "remaining": remaining})
+}
+
+// Quota - GET /users/:id/quota остаток дневных лайков и суперлайков
+func (h *MatchHandler) Quota(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	remaining, err := h.uc.Remaining(context.Background(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"remaining": remaining})
+}
+
+// HasLiked - GET /like/:id1/:id2: 204, если id1 лайкнул id2 и лайк виден
+// получателю, иначе 404
+func (h *MatchHandler) HasLiked(c *gin.Context) {
+	fromUserID, err1 := strconv.ParseInt(c.Param("id1"), 10, 64)
+	toUserID, err2 := strconv.ParseInt(c.Param("id2"), 10, 64)
+
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	liked, err := h.uc.HasLiked(context.Background(), fromUserID, toUserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !liked {
+		c.JSON(http.StatusNotFound, gin.H{"error": usecase.ErrLikeNotFound.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *MatchHandler) Unlike(c *gin.Context) {
+	fromUserID, err1 := strconv.ParseInt(c.Param("id1"), 10, 64)
+	toUserID, err2 := strconv.ParseInt(c.Param("id2"), 10, 64)
+
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err := h.uc.Unlike(context.Background(), fromUserID, toUserID)
+	if errors.Is(err, usecase.ErrLikeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Like saved"})
+	c.Status(http.StatusNoContent)
 }
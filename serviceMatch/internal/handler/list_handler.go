@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"service3/internal/entity"
+	"service3/internal/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IncomingLikes - GET /users/:id/likes/incoming?answered=true|false&limit=&offset=
+func (h *MatchHandler) IncomingLikes(c *gin.Context) {
+	userID, page, ok := parseListRequest(c)
+	if !ok {
+		return
+	}
+
+	var answered *bool
+	if raw := c.Query("answered"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid answered filter"})
+			return
+		}
+		answered = &value
+	}
+
+	items, hasMore, err := h.uc.IncomingLikes(context.Background(), userID, answered, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "has_more": hasMore})
+}
+
+// OutgoingLikes - GET /users/:id/likes/outgoing?limit=&offset=
+func (h *MatchHandler) OutgoingLikes(c *gin.Context) {
+	userID, page, ok := parseListRequest(c)
+	if !ok {
+		return
+	}
+
+	items, hasMore, err := h.uc.OutgoingLikes(context.Background(), userID, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "has_more": hasMore})
+}
+
+// Matches - GET /users/:id/matches?limit=&offset=
+func (h *MatchHandler) Matches(c *gin.Context) {
+	userID, page, ok := parseListRequest(c)
+	if !ok {
+		return
+	}
+
+	items, hasMore, err := h.uc.Matches(context.Background(), userID, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "has_more": hasMore})
+}
+
+// UnansweredLikes - GET /likes/unanswered?older_than=24h&limit=&offset=
+func (h *MatchHandler) UnansweredLikes(c *gin.Context) {
+	olderThan, err := time.ParseDuration(c.DefaultQuery("older_than", "24h"))
+	if err != nil || olderThan < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid older_than"})
+		return
+	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	items, hasMore, err := h.uc.UnansweredLikes(context.Background(), olderThan, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "has_more": hasMore})
+}
+
+// EndMatch - DELETE /matches/:id?user_id= разрыв пары одним из участников
+func (h *MatchHandler) EndMatch(c *gin.Context) {
+	matchID, userID, ok := parseMatchRequest(c)
+	if !ok {
+		return
+	}
+
+	err := h.uc.EndMatch(context.Background(), matchID, userID)
+	if errors.Is(err, usecase.ErrMatchNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// FollowUpMatch - POST /matches/:id/followup?user_id= пара продолжила общение
+func (h *MatchHandler) FollowUpMatch(c *gin.Context) {
+	matchID, userID, ok := parseMatchRequest(c)
+	if !ok {
+		return
+	}
+
+	err := h.uc.FollowUpMatch(context.Background(), matchID, userID)
+	if errors.Is(err, usecase.ErrMatchNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func parseMatchRequest(c *gin.Context) (matchID, userID int64, ok bool) {
+	matchID, err1 := strconv.ParseInt(c.Param("id"), 10, 64)
+	userID, err2 := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err1 != nil || err2 != nil || matchID <= 0 || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match or user ID"})
+		return 0, 0, false
+	}
+	return matchID, userID, true
+}
+
+// parseListRequest - ID пользователя и параметры страницы; при ошибке ответ уже отправлен
+func parseListRequest(c *gin.Context) (int64, entity.Page, bool) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, entity.Page{}, false
+	}
+
+	page, ok := parsePage(c)
+	if !ok {
+		return 0, entity.Page{}, false
+	}
+	return userID, page, true
+}
+
+// parsePage - limit и offset из query; при ошибке ответ уже отправлен
+func parsePage(c *gin.Context) (entity.Page, bool) {
+	var (
+		page entity.Page
+		err  error
+	)
+	if raw := c.Query("limit"); raw != "" {
+		if page.Limit, err = strconv.Atoi(raw); err != nil || page.Limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return entity.Page{}, false
+		}
+	}
+	if raw := c.Query("offset"); raw != "" {
+		if page.Offset, err = strconv.Atoi(raw); err != nil || page.Offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return entity.Page{}, false
+		}
+	}
+	return page, true
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"service3/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitRetryAfter - через сколько секунд боту стоит повторить свайп после 429
+const rateLimitRetryAfter = 60
+
+type SpamHandler struct {
+	antiSpam *usecase.AntiSpam
+}
+
+func NewSpamHandler(antiSpam *usecase.AntiSpam, router *gin.Engine, adminToken string) *SpamHandler {
+	h := &SpamHandler{antiSpam: antiSpam}
+	admin := router.Group("/admin", AdminAuth(adminToken))
+	admin.GET("/spam", h.Report)
+	admin.DELETE("/spam/:id", h.Clear)
+	return h
+}
+
+// AdminAuth пропускает только запросы с верным X-Admin-Token.
+// Если токен не настроен, админские эндпоинты закрыты полностью.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got := c.GetHeader("X-Admin-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Report - GET /admin/spam?limit=&offset= аккаунты под теневым ограничением
+func (h *SpamHandler) Report(c *gin.Context) {
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	flags, hasMore, err := h.antiSpam.Report(context.Background(), page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": flags, "has_more": hasMore})
+}
+
+// Clear - DELETE /admin/spam/:id снимает пометку при ложном срабатывании
+func (h *SpamHandler) Clear(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err = h.antiSpam.Clear(context.Background(), userID)
+	if errors.Is(err, usecase.ErrSpamFlagNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// respondScreenError - ответ на отказ антиспама; false, если ошибка не от него
+func respondScreenError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrRateLimited):
+		c.Header("Retry-After", strconv.Itoa(rateLimitRetryAfter))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "retry_after": rateLimitRetryAfter})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"service3/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Dislike - POST /dislike/:id1/:id2 записывает дизлайк, чтобы его можно было отменить
+func (h *MatchHandler) Dislike(c *gin.Context) {
+	fromUserID, err1 := strconv.ParseInt(c.Param("id1"), 10, 64)
+	toUserID, err2 := strconv.ParseInt(c.Param("id2"), 10, 64)
+
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err := h.uc.Dislike(context.Background(), fromUserID, toUserID)
+	if respondScreenError(c, err) {
+		return
+	}
+	if errors.Is(err, usecase.ErrSelfLike) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Undo - POST /swipes/:telegram_id/undo отменяет последний свайп и возвращает его,
+// чтобы бот мог снова показать анкету
+func (h *MatchHandler) Undo(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("telegram_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	swipe, err := h.uc.Undo(context.Background(), userID)
+	switch {
+	case errors.Is(err, usecase.ErrNothingToUndo):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, usecase.ErrUndoLimitReached):
+		remaining, _ := h.uc.Remaining(context.Background(), userID)
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "remaining": remaining})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	remaining, _ := h.uc.Remaining(context.Background(), userID)
+	c.JSON(http.StatusOK, gin.H{"swipe": swipe, "remaining": remaining})
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service3/internal/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QuotaRepository - резервные счетчики квот в Postgres
+type QuotaRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewQuotaRepository(pool *pgxpool.Pool) *QuotaRepository {
+	return &QuotaRepository{pool: pool}
+}
+
+func (r *QuotaRepository) Consume(ctx context.Context, key entity.QuotaKey, limit int) (bool, error) {
+	query := `
+		INSERT INTO like_quotas (telegram_id, day, kind, used) VALUES ($1, $2, $3, 1)
+		ON CONFLICT (telegram_id, day, kind) DO UPDATE SET used = like_quotas.used + 1
+		WHERE like_quotas.used < $4
+		RETURNING used
+	`
+	if limit <= 0 {
+		return false, nil
+	}
+	var used int
+	err := r.pool.QueryRow(ctx, query, key.UserID, key.Day, key.Kind, limit).Scan(&used)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *QuotaRepository) Refund(ctx context.Context, key entity.QuotaKey) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE like_quotas SET used = used - 1
+		WHERE telegram_id = $1 AND day = $2 AND kind = $3 AND used > 0
+	`, key.UserID, key.Day, key.Kind)
+	return err
+}
+
+func (r *QuotaRepository) Used(ctx context.Context, key entity.QuotaKey) (int, error) {
+	var used int
+	err := r.pool.QueryRow(ctx, `SELECT used FROM like_quotas WHERE telegram_id = $1 AND day = $2 AND kind = $3`,
+		key.UserID, key.Day, key.Kind).Scan(&used)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return used, err
+}
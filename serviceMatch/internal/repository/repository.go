@@ -2,7 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"service3/internal/entity"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -14,12 +19,90 @@ func NewRepository(pool *pgxpool.Pool) *Repository {
 	return &Repository{pool: pool}
 }
 
-func (r *Repository) SaveLike(fromUserID, toUserID int64) error {
-	query := `INSERT INTO likes(from_user_id, to_user_id) VALUES($1, $2)`
-	_, err := r.pool.Exec(context.Background(), query, fromUserID, toUserID)
+// SaveLike - сохраняет лайк. Повторный лайк обновляет комментарий и цель,
+// created сообщает, был ли лайк поставлен впервые. Новый лайк в том же запросе
+// записывается в историю свайпов, чтобы его можно было отменить
+func (r *Repository) SaveLike(like entity.Like) (bool, error) {
+	query := `
+		WITH saved AS (
+			INSERT INTO likes(from_user_id, to_user_id, target, prompt_id, comment, super)
+			VALUES($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), $6)
+			ON CONFLICT (from_user_id, to_user_id) DO UPDATE SET
+				target = COALESCE(EXCLUDED.target, likes.target),
+				prompt_id = COALESCE(EXCLUDED.prompt_id, likes.prompt_id),
+				comment = COALESCE(EXCLUDED.comment, likes.comment)
+			RETURNING (xmax = 0) AS created
+		), swipe AS (
+			INSERT INTO swipes(from_user_id, to_user_id, kind, limited)
+			SELECT $1, $2, $7, $8 FROM saved WHERE created
+		)
+		SELECT created FROM saved
+	`
+	kind := entity.SwipeLike
+	if like.Super {
+		kind = entity.SwipeSuperLike
+	}
+	var created bool
+	err := r.pool.QueryRow(context.Background(), query, like.FromUserID, like.ToUserID, like.Target, like.PromptID, like.Comment, like.Super, kind, like.Limited).Scan(&created)
+	return created, err
+}
+
+// SaveSwipe - записывает свайп без лайка (дизлайк)
+func (r *Repository) SaveSwipe(swipe entity.Swipe) error {
+	_, err := r.pool.Exec(context.Background(),
+		`INSERT INTO swipes(from_user_id, to_user_id, kind, limited) VALUES($1, $2, $3, $4)`,
+		swipe.FromUserID, swipe.ToUserID, swipe.Kind, swipe.Limited)
 	return err
 }
 
+// UndoLastSwipe - отменяет последний свайп пользователя, если он сделан не раньше since
+// и еще не отменен. Для лайка в той же транзакции удаляются лайк и созданная им пара.
+// Возвращает nil, если отменять нечего
+func (r *Repository) UndoLastSwipe(userID int64, since time.Time) (swipe *entity.Swipe, matchDeleted bool, err error) {
+	err = pgx.BeginFunc(context.Background(), r.pool, func(tx pgx.Tx) error {
+		var (
+			s        entity.Swipe
+			undoneAt *time.Time
+		)
+		err := tx.QueryRow(context.Background(), `
+			SELECT id, from_user_id, to_user_id, kind, created_at, undone_at, limited
+			FROM swipes WHERE from_user_id = $1
+			ORDER BY id DESC LIMIT 1
+			FOR UPDATE
+		`, userID).Scan(&s.ID, &s.FromUserID, &s.ToUserID, &s.Kind, &s.CreatedAt, &undoneAt, &s.Limited)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Отменить можно только самый последний свайп и только один раз
+		if undoneAt != nil || s.CreatedAt.Before(since) {
+			return nil
+		}
+
+		if _, err := tx.Exec(context.Background(), `UPDATE swipes SET undone_at = now() WHERE id = $1`, s.ID); err != nil {
+			return err
+		}
+		if s.IsLike() {
+			if _, err := tx.Exec(context.Background(),
+				`DELETE FROM likes WHERE from_user_id = $1 AND to_user_id = $2`, s.FromUserID, s.ToUserID); err != nil {
+				return err
+			}
+			userA, userB := orderPair(s.FromUserID, s.ToUserID)
+			tag, err := tx.Exec(context.Background(),
+				`DELETE FROM matches WHERE user_a = $1 AND user_b = $2 AND ended_at IS NULL`, userA, userB)
+			if err != nil {
+				return err
+			}
+			matchDeleted = tag.RowsAffected() > 0
+		}
+		swipe = &s
+		return nil
+	})
+	return swipe, matchDeleted, err
+}
+
 func (r *Repository) CheckMatch(fromUserID, toUserID int64) (bool, error) {
 	var count int
 	query := `
@@ -30,3 +113,260 @@ func (r *Repository) CheckMatch(fromUserID, toUserID int64) (bool, error) {
 	err := r.pool.QueryRow(context.Background(), query, fromUserID, toUserID).Scan(&count)
 	return count > 0, err
 }
+
+// SaveMatch - сохраняет взаимную пару, created = false, если пара уже была
+func (r *Repository) SaveMatch(userA, userB int64) (id int64, created bool, err error) {
+	userA, userB = orderPair(userA, userB)
+	err = r.pool.QueryRow(context.Background(),
+		`INSERT INTO matches(user_a, user_b) VALUES($1, $2) ON CONFLICT DO NOTHING RETURNING id`, userA, userB).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
+// DeleteLike - снимает лайк и в той же транзакции удаляет созданную им пару
+func (r *Repository) DeleteLike(fromUserID, toUserID int64) (likeDeleted, matchDeleted bool, err error) {
+	err = pgx.BeginFunc(context.Background(), r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(context.Background(),
+			`DELETE FROM likes WHERE from_user_id = $1 AND to_user_id = $2`, fromUserID, toUserID)
+		if err != nil {
+			return err
+		}
+		likeDeleted = tag.RowsAffected() > 0
+
+		userA, userB := orderPair(fromUserID, toUserID)
+		tag, err = tx.Exec(context.Background(),
+			`DELETE FROM matches WHERE user_a = $1 AND user_b = $2 AND ended_at IS NULL`, userA, userB)
+		if err != nil {
+			return err
+		}
+		matchDeleted = tag.RowsAffected() > 0
+		return nil
+	})
+	return likeDeleted, matchDeleted, err
+}
+
+func orderPair(a, b int64) (int64, int64) {
+	if a > b {
+		return b, a
+	}
+	return a, b
+}
+
+// IncomingLikes - кто лайкнул пользователя, новые сверху. Лайки аккаунтов
+// под теневым ограничением антиспама не показываются.
+// answered = nil - все лайки, иначе только отвеченные или только неотвеченные
+func (r *Repository) IncomingLikes(userID int64, answered *bool, page entity.Page) ([]entity.LikeItem, error) {
+	query := `
+		SELECT l.from_user_id, COALESCE(l.target, ''), l.prompt_id, COALESCE(l.comment, ''), l.created_at,
+			EXISTS (SELECT 1 FROM likes b WHERE b.from_user_id = l.to_user_id AND b.to_user_id = l.from_user_id) AS answered
+		FROM likes l
+		WHERE l.to_user_id = $1
+			AND NOT EXISTS (SELECT 1 FROM spam_flags f WHERE f.user_id = l.from_user_id AND f.cleared_at IS NULL)
+	`
+	args := []interface{}{userID}
+	if answered != nil {
+		query += ` AND EXISTS (SELECT 1 FROM likes b WHERE b.from_user_id = l.to_user_id AND b.to_user_id = l.from_user_id) = $2`
+		args = append(args, *answered)
+	}
+	query += fmt.Sprintf(` ORDER BY l.created_at DESC, l.id DESC LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	args = append(args, page.Limit, page.Offset)
+
+	rows, err := r.pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []entity.LikeItem{}
+	for rows.Next() {
+		var item entity.LikeItem
+		if err := rows.Scan(&item.UserID, &item.Target, &item.PromptID, &item.Comment, &item.CreatedAt, &item.Answered); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// LikeVisible - есть ли лайк from -> to, который получатель видит во входящих.
+// Как и в IncomingLikes, лайки аккаунтов под ограничением антиспама скрыты
+func (r *Repository) LikeVisible(fromUserID, toUserID int64) (bool, error) {
+	var visible bool
+	err := r.pool.QueryRow(context.Background(), `
+		SELECT EXISTS (
+			SELECT 1 FROM likes l
+			WHERE l.from_user_id = $1 AND l.to_user_id = $2
+				AND NOT EXISTS (SELECT 1 FROM spam_flags f WHERE f.user_id = l.from_user_id AND f.cleared_at IS NULL)
+		)
+	`, fromUserID, toUserID).Scan(&visible)
+	return visible, err
+}
+
+// UnansweredLikes - сколько лайков без ответа старше createdBefore у каждого
+// получателя. Ответ - лайк в ответ или дизлайк; лайки от аккаунтов под
+// ограничением антиспама не считаются
+func (r *Repository) UnansweredLikes(createdBefore time.Time, page entity.Page) ([]entity.PendingLikes, error) {
+	query := `
+		SELECT l.to_user_id, count(*), min(l.created_at)
+		FROM likes l
+		WHERE l.created_at < $1
+			AND NOT EXISTS (SELECT 1 FROM likes b WHERE b.from_user_id = l.to_user_id AND b.to_user_id = l.from_user_id)
+			AND NOT EXISTS (
+				SELECT 1 FROM swipes s
+				WHERE s.from_user_id = l.to_user_id AND s.to_user_id = l.from_user_id
+					AND s.kind = 'dislike' AND s.undone_at IS NULL
+			)
+			AND NOT EXISTS (SELECT 1 FROM spam_flags f WHERE f.user_id = l.from_user_id AND f.cleared_at IS NULL)
+		GROUP BY l.to_user_id
+		ORDER BY l.to_user_id
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.pool.Query(context.Background(), query, createdBefore, page.Limit, page.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []entity.PendingLikes{}
+	for rows.Next() {
+		var item entity.PendingLikes
+		if err := rows.Scan(&item.UserID, &item.Count, &item.Oldest); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// OutgoingLikes - кого лайкнул пользователь, новые сверху
+func (r *Repository) OutgoingLikes(userID int64, page entity.Page) ([]entity.LikeItem, error) {
+	query := `
+		SELECT l.to_user_id, COALESCE(l.target, ''), l.prompt_id, COALESCE(l.comment, ''), l.created_at,
+			EXISTS (SELECT 1 FROM likes b WHERE b.from_user_id = l.to_user_id AND b.to_user_id = l.from_user_id) AS answered
+		FROM likes l
+		WHERE l.from_user_id = $1
+		ORDER BY l.created_at DESC, l.id DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.pool.Query(context.Background(), query, userID, page.Limit, page.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []entity.LikeItem{}
+	for rows.Next() {
+		var item entity.LikeItem
+		if err := rows.Scan(&item.UserID, &item.Target, &item.PromptID, &item.Comment, &item.CreatedAt, &item.Answered); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// Matches - пары пользователя, новые сверху
+func (r *Repository) Matches(userID int64, page entity.Page) ([]entity.MatchItem, error) {
+	query := `
+		SELECT id, CASE WHEN user_a = $1 THEN user_b ELSE user_a END, created_at
+		FROM matches
+		WHERE (user_a = $1 OR user_b = $1) AND ended_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.pool.Query(context.Background(), query, userID, page.Limit, page.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []entity.MatchItem{}
+	for rows.Next() {
+		var item entity.MatchItem
+		if err := rows.Scan(&item.ID, &item.UserID, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+const matchColumns = `id, user_a, user_b, created_at, ended_at, ended_by, COALESCE(end_reason, ''), followed_up_at`
+
+func scanMatch(row pgx.Row) (entity.Match, error) {
+	var m entity.Match
+	err := row.Scan(&m.ID, &m.UserA, &m.UserB, &m.CreatedAt, &m.EndedAt, &m.EndedBy, &m.EndReason, &m.FollowedUpAt)
+	return m, err
+}
+
+func collectMatches(rows pgx.Rows) ([]entity.Match, error) {
+	defer rows.Close()
+	matches := []entity.Match{}
+	for rows.Next() {
+		m, err := scanMatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// EndMatch - завершает активную пару по просьбе одного из участников.
+// Возвращает nil, если пары нет, она уже завершена или userID в ней не участвует
+func (r *Repository) EndMatch(matchID, userID int64) (*entity.Match, error) {
+	query := `
+		UPDATE matches SET ended_at = now(), ended_by = $2, end_reason = $3
+		WHERE id = $1 AND ended_at IS NULL AND (user_a = $2 OR user_b = $2)
+		RETURNING ` + matchColumns
+	m, err := scanMatch(r.pool.QueryRow(context.Background(), query, matchID, userID, entity.EndReasonUnmatch))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// FollowUpMatch - отмечает, что пара продолжила общение, и снимает ее с автоистечения
+func (r *Repository) FollowUpMatch(matchID, userID int64) (bool, error) {
+	tag, err := r.pool.Exec(context.Background(), `
+		UPDATE matches SET followed_up_at = COALESCE(followed_up_at, now())
+		WHERE id = $1 AND ended_at IS NULL AND (user_a = $2 OR user_b = $2)
+	`, matchID, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ClaimMatchesToRemind - помечает и возвращает пары без продолжения, созданные до createdBefore,
+// по которым еще не отправлялось напоминание. Пометка в том же запросе не дает напомнить дважды
+func (r *Repository) ClaimMatchesToRemind(createdBefore time.Time) ([]entity.Match, error) {
+	rows, err := r.pool.Query(context.Background(), `
+		UPDATE matches SET reminded_at = now()
+		WHERE ended_at IS NULL AND followed_up_at IS NULL AND reminded_at IS NULL AND created_at <= $1
+		RETURNING `+matchColumns, createdBefore)
+	if err != nil {
+		return nil, err
+	}
+	return collectMatches(rows)
+}
+
+// ExpireMatches - завершает пары без продолжения, созданные до createdBefore
+func (r *Repository) ExpireMatches(createdBefore time.Time) ([]entity.Match, error) {
+	rows, err := r.pool.Query(context.Background(), `
+		UPDATE matches SET ended_at = now(), end_reason = $2
+		WHERE ended_at IS NULL AND followed_up_at IS NULL AND created_at <= $1
+		RETURNING `+matchColumns, createdBefore, entity.EndReasonExpired)
+	if err != nil {
+		return nil, err
+	}
+	return collectMatches(rows)
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service3/internal/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SpamRepository - статистика свайпов и пометки антиспама
+type SpamRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSpamRepository(pool *pgxpool.Pool) *SpamRepository {
+	return &SpamRepository{pool: pool}
+}
+
+// SwipeStats - частота свайпов за последние минуту и час и поведение с момента since.
+// Интервал между свайпами считается по всем свайпам выборки, включая отмененные
+func (r *SpamRepository) SwipeStats(ctx context.Context, userID int64, now, since time.Time, fast time.Duration) (entity.SwipeStats, error) {
+	query := `
+		SELECT
+			count(*) FILTER (WHERE created_at > $2::timestamptz - interval '1 minute'),
+			count(*) FILTER (WHERE created_at > $2::timestamptz - interval '1 hour'),
+			count(*) FILTER (WHERE created_at > $3),
+			count(*) FILTER (WHERE created_at > $3 AND kind <> 'dislike'),
+			count(*) FILTER (WHERE created_at > $3 AND EXTRACT(EPOCH FROM gap) < $4)
+		FROM (
+			SELECT created_at, kind, created_at - lag(created_at) OVER (ORDER BY created_at, id) AS gap
+			FROM swipes
+			WHERE from_user_id = $1 AND created_at > LEAST($3, $2::timestamptz - interval '1 hour')
+		) s
+	`
+	var stats entity.SwipeStats
+	err := r.pool.QueryRow(ctx, query, userID, now, since, fast.Seconds()).
+		Scan(&stats.LastMinute, &stats.LastHour, &stats.Total, &stats.Likes, &stats.Fast)
+	return stats, err
+}
+
+// Flag - пометка пользователя, в том числе снятая; nil, если ее не было
+func (r *SpamRepository) Flag(ctx context.Context, userID int64) (*entity.SpamFlag, error) {
+	row := r.pool.QueryRow(ctx, `
+		SELECT user_id, reason, swipes, likes, fast_swipes, flagged_at, cleared_at
+		FROM spam_flags WHERE user_id = $1
+	`, userID)
+	flag, err := scanSpamFlag(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// SaveFlag - помечает пользователя; снятая ранее пометка ставится заново
+func (r *SpamRepository) SaveFlag(ctx context.Context, flag entity.SpamFlag) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO spam_flags (user_id, reason, swipes, likes, fast_swipes)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			reason = EXCLUDED.reason,
+			swipes = EXCLUDED.swipes,
+			likes = EXCLUDED.likes,
+			fast_swipes = EXCLUDED.fast_swipes,
+			flagged_at = now(),
+			cleared_at = NULL
+	`, flag.UserID, flag.Reason, flag.Swipes, flag.Likes, flag.Fast)
+	return err
+}
+
+// ClearFlag - снимает активную пометку; false, если ее не было
+func (r *SpamRepository) ClearFlag(ctx context.Context, userID int64) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `UPDATE spam_flags SET cleared_at = now() WHERE user_id = $1 AND cleared_at IS NULL`, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Flags - активные пометки, новые сверху
+func (r *SpamRepository) Flags(ctx context.Context, page entity.Page) ([]entity.SpamFlag, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT user_id, reason, swipes, likes, fast_swipes, flagged_at, cleared_at
+		FROM spam_flags
+		WHERE cleared_at IS NULL
+		ORDER BY flagged_at DESC, user_id
+		LIMIT $1 OFFSET $2
+	`, page.Limit, page.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flags := []entity.SpamFlag{}
+	for rows.Next() {
+		flag, err := scanSpamFlag(rows)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, flag)
+	}
+	return flags, rows.Err()
+}
+
+func scanSpamFlag(row pgx.Row) (entity.SpamFlag, error) {
+	var f entity.SpamFlag
+	err := row.Scan(&f.UserID, &f.Reason, &f.Swipes, &f.Likes, &f.Fast, &f.FlaggedAt, &f.ClearedAt)
+	return f, err
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"service3/internal/entity"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// consumeScript - атомарно увеличивает счетчик, если он еще не достиг лимита.
+// Возвращает -1, если квота исчерпана
+var consumeScript = redis.NewScript(`
+local used = tonumber(redis.call('GET', KEYS[1]) or '0')
+if used >= tonumber(ARGV[1]) then
+	return -1
+end
+used = redis.call('INCR', KEYS[1])
+if used == 1 then
+	redis.call('EXPIRE', KEYS[1], ARGV[2])
+end
+return used
+`)
+
+// refundScript - уменьшает счетчик, только если он есть и больше нуля. Иначе
+// DECR создал бы ключ без TTL или увел счетчик в минус, и пользователь получил
+// бы лишние лайки. DECR сохраняет срок жизни ключа
+var refundScript = redis.NewScript(`
+local used = tonumber(redis.call('GET', KEYS[1]) or '0')
+if used <= 0 then
+	return 0
+end
+return redis.call('DECR', KEYS[1])
+`)
+
+// quotaTTL - счетчик живет чуть больше суток, чтобы пережить любой часовой пояс
+const quotaTTL = 48 * time.Hour
+
+// RedisQuota - счетчики дневных квот в Redis
+type RedisQuota struct {
+	client *redis.Client
+}
+
+func NewRedisQuota(addr string) (*RedisQuota, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("ошибка подключения к Redis: %w", err)
+	}
+	return &RedisQuota{client: client}, nil
+}
+
+func quotaKey(key entity.QuotaKey) string {
+	return fmt.Sprintf("quota:%s:%d:%s", key.Kind, key.UserID, key.Day)
+}
+
+func (q *RedisQuota) Consume(ctx context.Context, key entity.QuotaKey, limit int) (bool, error) {
+	used, err := consumeScript.Run(ctx, q.client, []string{quotaKey(key)}, limit, int(quotaTTL.Seconds())).Int()
+	if err != nil {
+		return false, err
+	}
+	return used > 0, nil
+}
+
+func (q *RedisQuota) Refund(ctx context.Context, key entity.QuotaKey) error {
+	return refundScript.Run(ctx, q.client, []string{quotaKey(key)}).Err()
+}
+
+func (q *RedisQuota) Used(ctx context.Context, key entity.QuotaKey) (int, error) {
+	used, err := q.client.Get(ctx, quotaKey(key)).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return used, err
+}
@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"service3/internal/entity"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUserNotFound - одной из анкет нет в serviceUser
+	ErrUserNotFound = errors.New("user not found")
+	// ErrRateLimited - пользователь свайпает чаще, чем разрешено
+	ErrRateLimited = errors.New("too many swipes")
+	// ErrSpamFlagNotFound - активной пометки антиспама нет
+	ErrSpamFlagNotFound = errors.New("spam flag not found")
+)
+
+// knownUserTTL - сколько доверять тому, что анкета существует, без повторного запроса в serviceUser
+const knownUserTTL = 10 * time.Minute
+
+// maxKnownUsers - после такого числа запомненных анкет устаревшие записи удаляются
+const maxKnownUsers = 10000
+
+// SpamStore - статистика свайпов и пометки антиспама
+type SpamStore interface {
+	SwipeStats(ctx context.Context, userID int64, now, since time.Time, fast time.Duration) (entity.SwipeStats, error)
+	Flag(ctx context.Context, userID int64) (*entity.SpamFlag, error)
+	SaveFlag(ctx context.Context, flag entity.SpamFlag) error
+	ClearFlag(ctx context.Context, userID int64) (bool, error)
+	Flags(ctx context.Context, page entity.Page) ([]entity.SpamFlag, error)
+}
+
+// SpamLimits - лимиты частоты свайпов и пороги подозрительного поведения; 0 - проверка выключена
+type SpamLimits struct {
+	PerMinute int
+	PerHour   int
+	// Window - за какой период анализируется поведение
+	Window time.Duration
+	// MinSwipes - при меньшем числе свайпов за окно выводы не делаются
+	MinSwipes int
+	// LikeRatio - доля лайков среди свайпов, начиная с которой аккаунт помечается
+	LikeRatio float64
+	// FastSwipe и FastRatio - свайп быстрее FastSwipe считается быстрым,
+	// аккаунт помечается, если таких свайпов не меньше FastRatio
+	FastSwipe time.Duration
+	FastRatio float64
+}
+
+// DefaultSpamLimits - живой человек не лайкает все анкеты подряд и не свайпает быстрее секунды
+var DefaultSpamLimits = SpamLimits{
+	PerMinute: 30,
+	PerHour:   300,
+	Window:    24 * time.Hour,
+	MinSwipes: 30,
+	LikeRatio: 1,
+	FastSwipe: time.Second,
+	FastRatio: 0.5,
+}
+
+// AntiSpam - анализ поведения при свайпах: проверяет, что анкеты существуют,
+// ограничивает частоту и помечает аккаунты, похожие на ботов
+type AntiSpam struct {
+	store  SpamStore
+	users  UserClient
+	limits SpamLimits
+	now    func() time.Time
+
+	mu    sync.Mutex
+	known map[int64]time.Time
+}
+
+func NewAntiSpam(store SpamStore, users UserClient, limits SpamLimits) *AntiSpam {
+	return &AntiSpam{store: store, users: users, limits: limits, now: time.Now, known: make(map[int64]time.Time)}
+}
+
+// Check - проверка перед свайпом. limited - аккаунт под теневым ограничением:
+// свайп сохраняется, но получатель о нем не узнает
+func (a *AntiSpam) Check(ctx context.Context, fromUserID, toUserID int64) (limited bool, err error) {
+	if err := a.checkUsers(ctx, fromUserID, toUserID); err != nil {
+		return false, err
+	}
+
+	flag, err := a.store.Flag(ctx, fromUserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to load spam flag: %w", err)
+	}
+
+	// После снятия пометки поведение оценивается заново, старые свайпы не учитываются
+	now := a.now()
+	since := now.Add(-a.limits.Window)
+	if flag != nil && flag.ClearedAt != nil && flag.ClearedAt.After(since) {
+		since = *flag.ClearedAt
+	}
+	stats, err := a.store.SwipeStats(ctx, fromUserID, now, since, a.limits.FastSwipe)
+	if err != nil {
+		return false, fmt.Errorf("failed to load swipe stats: %w", err)
+	}
+
+	if (a.limits.PerMinute > 0 && stats.LastMinute >= a.limits.PerMinute) ||
+		(a.limits.PerHour > 0 && stats.LastHour >= a.limits.PerHour) {
+		return flag.Active(), ErrRateLimited
+	}
+	if flag.Active() {
+		return true, nil
+	}
+
+	reason := a.analyze(stats)
+	if reason == "" {
+		return false, nil
+	}
+	err = a.store.SaveFlag(ctx, entity.SpamFlag{UserID: fromUserID, Reason: reason, Swipes: stats.Total, Likes: stats.Likes, Fast: stats.Fast})
+	if err != nil {
+		return false, fmt.Errorf("failed to save spam flag: %w", err)
+	}
+	log.Printf("User %d flagged as spam (%s): %d swipes, %d likes, %d fast", fromUserID, reason, stats.Total, stats.Likes, stats.Fast)
+	return true, nil
+}
+
+// analyze - причина пометки или пустая строка, если поведение похоже на человека
+func (a *AntiSpam) analyze(stats entity.SwipeStats) string {
+	if stats.Total == 0 || stats.Total < a.limits.MinSwipes {
+		return ""
+	}
+	total := float64(stats.Total)
+	if a.limits.LikeRatio > 0 && float64(stats.Likes)/total >= a.limits.LikeRatio {
+		return entity.SpamReasonLikeRatio
+	}
+	if a.limits.FastRatio > 0 && float64(stats.Fast)/total >= a.limits.FastRatio {
+		return entity.SpamReasonFastSwipes
+	}
+	return ""
+}
+
+// checkUsers - обе анкеты должны существовать; подтвержденные анкеты запоминаются на knownUserTTL
+func (a *AntiSpam) checkUsers(ctx context.Context, ids ...int64) error {
+	now := a.now()
+	var unknown []int64
+	a.mu.Lock()
+	for _, id := range ids {
+		if checked, ok := a.known[id]; !ok || now.Sub(checked) > knownUserTTL {
+			unknown = append(unknown, id)
+		}
+	}
+	a.mu.Unlock()
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	profiles, err := a.users.GetProfiles(ctx, unknown)
+	if err != nil {
+		return fmt.Errorf("failed to check users: %w", err)
+	}
+	found := make(map[int64]bool, len(profiles))
+	for _, p := range profiles {
+		found[p.TelegramID] = true
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, id := range unknown {
+		if !found[id] {
+			return fmt.Errorf("%w: %d", ErrUserNotFound, id)
+		}
+		a.known[id] = now
+	}
+	a.pruneKnown(now)
+	return nil
+}
+
+// pruneKnown - удаляет анкеты, проверенные дольше knownUserTTL назад, чтобы кэш
+// не рос бесконечно. Вызывается под a.mu
+func (a *AntiSpam) pruneKnown(now time.Time) {
+	if len(a.known) <= maxKnownUsers {
+		return
+	}
+	for id, checked := range a.known {
+		if now.Sub(checked) > knownUserTTL {
+			delete(a.known, id)
+		}
+	}
+}
+
+// Report - активные пометки для админа вместе с анкетами
+func (a *AntiSpam) Report(ctx context.Context, page entity.Page) (flags []entity.SpamFlag, hasMore bool, err error) {
+	page = NormalizePage(page)
+	flags, err = a.store.Flags(ctx, entity.Page{Limit: page.Limit + 1, Offset: page.Offset})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load spam flags: %w", err)
+	}
+	flags, hasMore = trimPage(flags, page.Limit)
+	if len(flags) == 0 {
+		return flags, hasMore, nil
+	}
+
+	ids := make([]int64, 0, len(flags))
+	for _, f := range flags {
+		ids = append(ids, f.UserID)
+	}
+	profiles, err := a.users.GetProfiles(ctx, ids)
+	if err != nil {
+		// Отчет полезен и без анкет
+		log.Printf("Error loading profiles for spam report: %v", err)
+		return flags, hasMore, nil
+	}
+	byID := make(map[int64]*entity.Profile, len(profiles))
+	for i := range profiles {
+		byID[profiles[i].TelegramID] = &profiles[i]
+	}
+	for i := range flags {
+		flags[i].Profile = byID[flags[i].UserID]
+	}
+	return flags, hasMore, nil
+}
+
+// Clear - снимает пометку (ложное срабатывание); лайки аккаунта снова видны получателям
+func (a *AntiSpam) Clear(ctx context.Context, userID int64) error {
+	cleared, err := a.store.ClearFlag(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to clear spam flag: %w", err)
+	}
+	if !cleared {
+		return ErrSpamFlagNotFound
+	}
+	log.Printf("Spam flag of user %d cleared", userID)
+	return nil
+}
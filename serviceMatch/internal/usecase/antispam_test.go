@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"service3/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memorySpamStore - хранилище антиспама с заранее заданной статистикой свайпов
+type memorySpamStore struct {
+	stats entity.SwipeStats
+	flags map[int64]*entity.SpamFlag
+	since time.Time
+}
+
+func newMemorySpamStore(stats entity.SwipeStats) *memorySpamStore {
+	return &memorySpamStore{stats: stats, flags: make(map[int64]*entity.SpamFlag)}
+}
+
+func (s *memorySpamStore) SwipeStats(ctx context.Context, userID int64, now, since time.Time, fast time.Duration) (entity.SwipeStats, error) {
+	s.since = since
+	return s.stats, nil
+}
+
+func (s *memorySpamStore) Flag(ctx context.Context, userID int64) (*entity.SpamFlag, error) {
+	return s.flags[userID], nil
+}
+
+func (s *memorySpamStore) SaveFlag(ctx context.Context, flag entity.SpamFlag) error {
+	s.flags[flag.UserID] = &flag
+	return nil
+}
+
+func (s *memorySpamStore) ClearFlag(ctx context.Context, userID int64) (bool, error) {
+	flag := s.flags[userID]
+	if !flag.Active() {
+		return false, nil
+	}
+	now := time.Now()
+	flag.ClearedAt = &now
+	return true, nil
+}
+
+func (s *memorySpamStore) Flags(ctx context.Context, page entity.Page) ([]entity.SpamFlag, error) {
+	var flags []entity.SpamFlag
+	for _, f := range s.flags {
+		if f.Active() {
+			flags = append(flags, *f)
+		}
+	}
+	return flags, nil
+}
+
+func existingUsers(ids ...int64) *profileClient {
+	client := &profileClient{}
+	for _, id := range ids {
+		client.profiles = append(client.profiles, entity.Profile{TelegramID: id})
+	}
+	return client
+}
+
+func TestAntiSpam_Check(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown user is rejected", func(t *testing.T) {
+		antiSpam := NewAntiSpam(newMemorySpamStore(entity.SwipeStats{}), existingUsers(1), DefaultSpamLimits)
+
+		_, err := antiSpam.Check(ctx, 1, 2)
+		assert.True(t, errors.Is(err, ErrUserNotFound))
+	})
+
+	t.Run("existing users are cached", func(t *testing.T) {
+		users := existingUsers(1, 2)
+		antiSpam := NewAntiSpam(newMemorySpamStore(entity.SwipeStats{}), users, DefaultSpamLimits)
+
+		for i := 0; i < 3; i++ {
+			limited, err := antiSpam.Check(ctx, 1, 2)
+			assert.NoError(t, err)
+			assert.False(t, limited)
+		}
+		assert.Len(t, users.calls, 1)
+	})
+
+	t.Run("expired users are pruned", func(t *testing.T) {
+		antiSpam := NewAntiSpam(newMemorySpamStore(entity.SwipeStats{}), existingUsers(1, 2), DefaultSpamLimits)
+		stale := time.Now().Add(-2 * knownUserTTL)
+		for id := int64(100); id < 100+maxKnownUsers; id++ {
+			antiSpam.known[id] = stale
+		}
+
+		_, err := antiSpam.Check(ctx, 1, 2)
+		assert.NoError(t, err)
+		assert.Len(t, antiSpam.known, 2)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		store := newMemorySpamStore(entity.SwipeStats{LastMinute: 30, LastHour: 30, Total: 30, Likes: 15})
+		antiSpam := NewAntiSpam(store, existingUsers(1, 2), DefaultSpamLimits)
+
+		_, err := antiSpam.Check(ctx, 1, 2)
+		assert.True(t, errors.Is(err, ErrRateLimited))
+		assert.Empty(t, store.flags)
+	})
+
+	t.Run("liking every profile flags the account", func(t *testing.T) {
+		store := newMemorySpamStore(entity.SwipeStats{LastMinute: 5, LastHour: 40, Total: 40, Likes: 40})
+		antiSpam := NewAntiSpam(store, existingUsers(1, 2), DefaultSpamLimits)
+
+		limited, err := antiSpam.Check(ctx, 1, 2)
+		assert.NoError(t, err)
+		assert.True(t, limited)
+		assert.Equal(t, entity.SpamReasonLikeRatio, store.flags[1].Reason)
+	})
+
+	t.Run("sub-second swipes flag the account", func(t *testing.T) {
+		store := newMemorySpamStore(entity.SwipeStats{LastMinute: 20, LastHour: 40, Total: 40, Likes: 10, Fast: 25})
+		antiSpam := NewAntiSpam(store, existingUsers(1, 2), DefaultSpamLimits)
+
+		limited, err := antiSpam.Check(ctx, 1, 2)
+		assert.NoError(t, err)
+		assert.True(t, limited)
+		assert.Equal(t, entity.SpamReasonFastSwipes, store.flags[1].Reason)
+	})
+
+	t.Run("too few swipes are not judged", func(t *testing.T) {
+		store := newMemorySpamStore(entity.SwipeStats{LastMinute: 5, LastHour: 5, Total: 5, Likes: 5, Fast: 5})
+		antiSpam := NewAntiSpam(store, existingUsers(1, 2), DefaultSpamLimits)
+
+		limited, err := antiSpam.Check(ctx, 1, 2)
+		assert.NoError(t, err)
+		assert.False(t, limited)
+	})
+
+	t.Run("cleared flag restarts the analysis window", func(t *testing.T) {
+		store := newMemorySpamStore(entity.SwipeStats{Total: 40, Likes: 20})
+		clearedAt := time.Now().Add(-time.Hour)
+		store.flags[1] = &entity.SpamFlag{UserID: 1, Reason: entity.SpamReasonLikeRatio, ClearedAt: &clearedAt}
+		antiSpam := NewAntiSpam(store, existingUsers(1, 2), DefaultSpamLimits)
+
+		limited, err := antiSpam.Check(ctx, 1, 2)
+		assert.NoError(t, err)
+		assert.False(t, limited)
+		assert.Equal(t, clearedAt, store.since)
+	})
+}
+
+func TestUsecase_LikeShadowLimited(t *testing.T) {
+	repo := new(MockMatchRepository)
+	events := newEventRecorder()
+	store := newMemorySpamStore(entity.SwipeStats{})
+	store.flags[1] = &entity.SpamFlag{UserID: 1, Reason: entity.SpamReasonLikeRatio}
+	uc := NewUseCase(repo, events, noopUserClient{}, nil)
+	uc.SetAntiSpam(NewAntiSpam(store, existingUsers(1, 2), DefaultSpamLimits))
+
+	repo.On("SaveLike", entity.Like{FromUserID: 1, ToUserID: 2, Limited: true}).Return(true, nil)
+
+	created, err := uc.Like(context.Background(), entity.Like{FromUserID: 1, ToUserID: 2})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	events.assertNoEvents(t)
+	repo.AssertNotCalled(t, "CheckMatch", int64(2), int64(1))
+
+	// После снятия пометки лайки снова доходят до получателей
+	assert.NoError(t, uc.antiSpam.Clear(context.Background(), 1))
+	assert.True(t, errors.Is(uc.antiSpam.Clear(context.Background(), 1), ErrSpamFlagNotFound))
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"service3/internal/entity"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxCommentLength - максимальная длина комментария к лайку в символах
+const MaxCommentLength = 140
+
+var ErrInvalidLike = errors.New("invalid like")
+
+// linkPattern - ссылки, адреса сайтов и telegram-ссылки в комментариях запрещены
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.|t\.me/|telegram\.me/|tg://|\b[a-z0-9-]+\.(com|ru|net|org|io|me|info|xyz)\b)`)
+
+// normalizeLike - проверяет цель и комментарий лайка.
+// Лайк с prompt_id без явной цели считается реакцией на вопрос-карточку
+func normalizeLike(like entity.Like) (entity.Like, error) {
+	if like.Target == "" && like.PromptID != nil {
+		like.Target = entity.TargetPrompt
+	}
+	switch like.Target {
+	case "":
+	case entity.TargetPhoto:
+		if like.PromptID != nil {
+			return like, fmt.Errorf("%w: prompt_id is not allowed for photo target", ErrInvalidLike)
+		}
+	case entity.TargetPrompt:
+		if like.PromptID == nil {
+			return like, fmt.Errorf("%w: prompt_id is required for prompt target", ErrInvalidLike)
+		}
+	default:
+		return like, fmt.Errorf("%w: unknown target %q", ErrInvalidLike, like.Target)
+	}
+
+	like.Comment = strings.TrimSpace(like.Comment)
+	if utf8.RuneCountInString(like.Comment) > MaxCommentLength {
+		return like, fmt.Errorf("%w: comment is longer than %d characters", ErrInvalidLike, MaxCommentLength)
+	}
+	if linkPattern.MatchString(like.Comment) {
+		return like, fmt.Errorf("%w: links are not allowed in comments", ErrInvalidLike)
+	}
+	return like, nil
+}
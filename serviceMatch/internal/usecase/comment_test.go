@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"errors"
+	"service3/internal/entity"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeLike(t *testing.T) {
+	promptID := 3
+	tests := []struct {
+		name    string
+		like    entity.Like
+		wantErr bool
+		target  string
+		comment string
+	}{
+		{name: "plain like", like: entity.Like{FromUserID: 1, ToUserID: 2}},
+		{name: "comment is trimmed", like: entity.Like{Comment: "  привет!  "}, comment: "привет!"},
+		{name: "prompt id implies prompt target", like: entity.Like{PromptID: &promptID}, target: entity.TargetPrompt},
+		{name: "photo target", like: entity.Like{Target: entity.TargetPhoto}, target: entity.TargetPhoto},
+		{name: "photo target with prompt", like: entity.Like{Target: entity.TargetPhoto, PromptID: &promptID}, wantErr: true},
+		{name: "prompt target without prompt", like: entity.Like{Target: entity.TargetPrompt}, wantErr: true},
+		{name: "unknown target", like: entity.Like{Target: "video"}, wantErr: true},
+		{name: "too long", like: entity.Like{Comment: strings.Repeat("я", MaxCommentLength+1)}, wantErr: true},
+		{name: "max length", like: entity.Like{Comment: strings.Repeat("я", MaxCommentLength)}, comment: strings.Repeat("я", MaxCommentLength)},
+		{name: "http link", like: entity.Like{Comment: "пиши сюда https://example.com"}, wantErr: true},
+		{name: "telegram link", like: entity.Like{Comment: "t.me/someone"}, wantErr: true},
+		{name: "bare domain", like: entity.Like{Comment: "мой сайт mysite.ru"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			like, err := normalizeLike(tt.like)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidLike) {
+					t.Fatalf("expected ErrInvalidLike, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if like.Target != tt.target {
+				t.Errorf("expected target %q, got %q", tt.target, like.Target)
+			}
+			if like.Comment != tt.comment {
+				t.Errorf("expected comment %q, got %q", tt.comment, like.Comment)
+			}
+		})
+	}
+}
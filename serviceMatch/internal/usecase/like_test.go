@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"service3/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// eventRecorder - продюсер Kafka, который складывает события в канал
+type eventRecorder struct {
+	events chan entity.Event
+}
+
+func newEventRecorder() *eventRecorder {
+	return &eventRecorder{events: make(chan entity.Event, 10)}
+}
+
+func (r *eventRecorder) SendMessage(ctx context.Context, message string) error {
+	var event entity.Event
+	if err := json.Unmarshal([]byte(message), &event); err != nil {
+		return err
+	}
+	r.events <- event
+	return nil
+}
+
+// next - ждет следующее событие; события отправляются асинхронно
+func (r *eventRecorder) next(t *testing.T) entity.Event {
+	t.Helper()
+	select {
+	case event := <-r.events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("expected event, got none")
+		return entity.Event{}
+	}
+}
+
+func (r *eventRecorder) assertNoEvents(t *testing.T) {
+	t.Helper()
+	select {
+	case event := <-r.events:
+		t.Fatalf("unexpected event %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+type noopUserClient struct{}
+
+func (noopUserClient) TouchActivity(ctx context.Context, userID int64) error { return nil }
+
+func (noopUserClient) HideUser(ctx context.Context, userID, hiddenID int64) error { return nil }
+
+func (noopUserClient) RateUser(ctx context.Context, targetID, raterID int64, liked bool) error {
+	return nil
+}
+
+func (noopUserClient) UnrateUser(ctx context.Context, targetID, raterID int64, liked bool) error {
+	return nil
+}
+
+func (noopUserClient) GetProfiles(ctx context.Context, ids []int64) ([]entity.Profile, error) {
+	return nil, nil
+}
+
+func TestUsecase_Like(t *testing.T) {
+	t.Run("new like without mutual", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		events := newEventRecorder()
+		uc := NewUseCase(repo, events, noopUserClient{}, nil)
+
+		repo.On("SaveLike", entity.Like{FromUserID: 1, ToUserID: 2}).Return(true, nil)
+		repo.On("CheckMatch", int64(2), int64(1)).Return(false, nil)
+
+		created, err := uc.Like(context.Background(), entity.Like{FromUserID: 1, ToUserID: 2})
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, entity.EventLike, events.next(t).Type)
+		events.assertNoEvents(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("mutual like creates match", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		events := newEventRecorder()
+		uc := NewUseCase(repo, events, noopUserClient{}, nil)
+
+		repo.On("SaveLike", entity.Like{FromUserID: 1, ToUserID: 2}).Return(true, nil)
+		repo.On("CheckMatch", int64(2), int64(1)).Return(true, nil)
+		repo.On("SaveMatch", int64(1), int64(2)).Return(int64(7), true, nil)
+
+		created, err := uc.Like(context.Background(), entity.Like{FromUserID: 1, ToUserID: 2})
+		assert.NoError(t, err)
+		assert.True(t, created)
+		types := []string{events.next(t).Type, events.next(t).Type}
+		assert.ElementsMatch(t, []string{entity.EventLike, entity.EventMatch}, types)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("repeated like emits nothing", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		events := newEventRecorder()
+		uc := NewUseCase(repo, events, noopUserClient{}, nil)
+
+		repo.On("SaveLike", entity.Like{FromUserID: 1, ToUserID: 2}).Return(false, nil)
+
+		created, err := uc.Like(context.Background(), entity.Like{FromUserID: 1, ToUserID: 2})
+		assert.NoError(t, err)
+		assert.False(t, created)
+		events.assertNoEvents(t)
+		repo.AssertNotCalled(t, "CheckMatch", int64(2), int64(1))
+	})
+
+	t.Run("self like is rejected", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		uc := NewUseCase(repo, newEventRecorder(), noopUserClient{}, nil)
+
+		_, err := uc.Like(context.Background(), entity.Like{FromUserID: 1, ToUserID: 1})
+		assert.True(t, errors.Is(err, ErrSelfLike))
+		repo.AssertNotCalled(t, "SaveLike", entity.Like{FromUserID: 1, ToUserID: 1})
+	})
+}
+
+func TestUsecase_Unlike(t *testing.T) {
+	t.Run("unlike removes match", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		events := newEventRecorder()
+		uc := NewUseCase(repo, events, noopUserClient{}, nil)
+
+		repo.On("DeleteLike", int64(1), int64(2)).Return(true, true, nil)
+
+		assert.NoError(t, uc.Unlike(context.Background(), 1, 2))
+		event := events.next(t)
+		assert.Equal(t, entity.EventUnmatch, event.Type)
+		assert.Equal(t, int64(1), event.FromUserID)
+		assert.Equal(t, int64(2), event.ToUserID)
+	})
+
+	t.Run("unlike without match", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		events := newEventRecorder()
+		uc := NewUseCase(repo, events, noopUserClient{}, nil)
+
+		repo.On("DeleteLike", int64(1), int64(2)).Return(true, false, nil)
+
+		assert.NoError(t, uc.Unlike(context.Background(), 1, 2))
+		events.assertNoEvents(t)
+	})
+
+	t.Run("missing like", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		uc := NewUseCase(repo, newEventRecorder(), noopUserClient{}, nil)
+
+		repo.On("DeleteLike", int64(1), int64(2)).Return(false, false, nil)
+
+		assert.True(t, errors.Is(uc.Unlike(context.Background(), 1, 2), ErrLikeNotFound))
+	})
+}
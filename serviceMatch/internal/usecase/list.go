@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"service3/internal/entity"
+	"time"
+)
+
+// Ограничения постраничной выдачи
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// NormalizePage - подставляет лимит по умолчанию и обрезает слишком большие значения
+func NormalizePage(page entity.Page) entity.Page {
+	if page.Limit <= 0 {
+		page.Limit = DefaultPageLimit
+	}
+	if page.Limit > MaxPageLimit {
+		page.Limit = MaxPageLimit
+	}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+	return page
+}
+
+// IncomingLikes - кто лайкнул пользователя. hasMore сообщает, есть ли следующая страница
+func (uc *Usecase) IncomingLikes(ctx context.Context, userID int64, answered *bool, page entity.Page) (items []entity.LikeItem, hasMore bool, err error) {
+	page = NormalizePage(page)
+	items, err = uc.repo.IncomingLikes(userID, answered, entity.Page{Limit: page.Limit + 1, Offset: page.Offset})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load incoming likes: %w", err)
+	}
+	items, hasMore = trimPage(items, page.Limit)
+	uc.attachLikeProfiles(ctx, items)
+	return items, hasMore, nil
+}
+
+// HasLiked - есть ли лайк fromUserID во входящих toUserID. Нужен, чтобы проверить
+// одну пару, не перебирая все входящие
+func (uc *Usecase) HasLiked(ctx context.Context, fromUserID, toUserID int64) (bool, error) {
+	visible, err := uc.repo.LikeVisible(fromUserID, toUserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check like: %w", err)
+	}
+	return visible, nil
+}
+
+// OutgoingLikes - кого лайкнул пользователь
+func (uc *Usecase) OutgoingLikes(ctx context.Context, userID int64, page entity.Page) (items []entity.LikeItem, hasMore bool, err error) {
+	page = NormalizePage(page)
+	items, err = uc.repo.OutgoingLikes(userID, entity.Page{Limit: page.Limit + 1, Offset: page.Offset})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load outgoing likes: %w", err)
+	}
+	items, hasMore = trimPage(items, page.Limit)
+	uc.attachLikeProfiles(ctx, items)
+	return items, hasMore, nil
+}
+
+// Matches - пары пользователя
+func (uc *Usecase) Matches(ctx context.Context, userID int64, page entity.Page) (items []entity.MatchItem, hasMore bool, err error) {
+	page = NormalizePage(page)
+	items, err = uc.repo.Matches(userID, entity.Page{Limit: page.Limit + 1, Offset: page.Offset})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load matches: %w", err)
+	}
+	items, hasMore = trimPage(items, page.Limit)
+
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.UserID)
+	}
+	profiles := uc.loadProfiles(ctx, ids)
+	for i := range items {
+		items[i].Profile = profiles[items[i].UserID]
+	}
+	return items, hasMore, nil
+}
+
+func (uc *Usecase) attachLikeProfiles(ctx context.Context, items []entity.LikeItem) {
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.UserID)
+	}
+	profiles := uc.loadProfiles(ctx, ids)
+	for i := range items {
+		items[i].Profile = profiles[items[i].UserID]
+	}
+}
+
+// loadProfiles - одним запросом получает анкеты из serviceUser.
+// Если serviceUser недоступен, список отдается без анкет
+func (uc *Usecase) loadProfiles(ctx context.Context, ids []int64) map[int64]*entity.Profile {
+	result := make(map[int64]*entity.Profile, len(ids))
+	if len(ids) == 0 {
+		return result
+	}
+	profiles, err := uc.userClient.GetProfiles(ctx, ids)
+	if err != nil {
+		log.Printf("Error loading profiles from serviceUser: %v", err)
+		return result
+	}
+	for i := range profiles {
+		result[profiles[i].TelegramID] = &profiles[i]
+	}
+	return result
+}
+
+// UnansweredLikes - получатели лайков, оставленных без ответа дольше olderThan,
+// с числом таких лайков. Нужно serviceNotification для напоминаний
+func (uc *Usecase) UnansweredLikes(ctx context.Context, olderThan time.Duration, page entity.Page) (items []entity.PendingLikes, hasMore bool, err error) {
+	page = NormalizePage(page)
+	items, err = uc.repo.UnansweredLikes(time.Now().Add(-olderThan), entity.Page{Limit: page.Limit + 1, Offset: page.Offset})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load unanswered likes: %w", err)
+	}
+	items, hasMore = trimPage(items, page.Limit)
+	return items, hasMore, nil
+}
+
+func trimPage[T any](items []T, limit int) ([]T, bool) {
+	if len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"service3/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// profileClient - serviceUser, который отдает заранее заданные анкеты
+type profileClient struct {
+	noopUserClient
+	profiles []entity.Profile
+	err      error
+	calls    [][]int64
+}
+
+func (c *profileClient) GetProfiles(ctx context.Context, ids []int64) ([]entity.Profile, error) {
+	c.calls = append(c.calls, ids)
+	return c.profiles, c.err
+}
+
+func TestUsecase_IncomingLikes(t *testing.T) {
+	answered := false
+
+	t.Run("pages and enriches in one batch", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		users := &profileClient{profiles: []entity.Profile{{TelegramID: 10, Name: "Аня"}, {TelegramID: 11, Name: "Оля"}}}
+		uc := NewUseCase(repo, newEventRecorder(), users, nil)
+
+		repo.On("IncomingLikes", int64(1), &answered, entity.Page{Limit: 3, Offset: 2}).
+			Return([]entity.LikeItem{{UserID: 10}, {UserID: 11}, {UserID: 12}}, nil)
+
+		items, hasMore, err := uc.IncomingLikes(context.Background(), 1, &answered, entity.Page{Limit: 2, Offset: 2})
+		assert.NoError(t, err)
+		assert.True(t, hasMore)
+		assert.Len(t, items, 2)
+		assert.Equal(t, [][]int64{{10, 11}}, users.calls)
+		assert.Equal(t, "Аня", items[0].Profile.Name)
+		assert.Equal(t, "Оля", items[1].Profile.Name)
+	})
+
+	t.Run("serviceUser is down", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		uc := NewUseCase(repo, newEventRecorder(), &profileClient{err: errors.New("timeout")}, nil)
+
+		repo.On("IncomingLikes", int64(1), (*bool)(nil), entity.Page{Limit: DefaultPageLimit + 1}).
+			Return([]entity.LikeItem{{UserID: 10}}, nil)
+
+		items, hasMore, err := uc.IncomingLikes(context.Background(), 1, nil, entity.Page{})
+		assert.NoError(t, err)
+		assert.False(t, hasMore)
+		assert.Len(t, items, 1)
+		assert.Nil(t, items[0].Profile)
+	})
+}
+
+func TestUsecase_HasLiked(t *testing.T) {
+	repo := new(MockMatchRepository)
+	uc := NewUseCase(repo, newEventRecorder(), noopUserClient{}, nil)
+	repo.On("LikeVisible", int64(1), int64(2)).Return(true, nil)
+	repo.On("LikeVisible", int64(3), int64(2)).Return(false, nil)
+
+	liked, err := uc.HasLiked(context.Background(), 1, 2)
+	assert.NoError(t, err)
+	assert.True(t, liked)
+	liked, err = uc.HasLiked(context.Background(), 3, 2)
+	assert.NoError(t, err)
+	assert.False(t, liked)
+}
+
+func TestUsecase_UnansweredLikes(t *testing.T) {
+	repo := new(MockMatchRepository)
+	uc := NewUseCase(repo, newEventRecorder(), noopUserClient{}, nil)
+
+	before := time.Now().Add(-24 * time.Hour)
+	cutoff := mock.MatchedBy(func(at time.Time) bool {
+		return !at.Before(before) && at.Before(before.Add(time.Minute))
+	})
+	repo.On("UnansweredLikes", cutoff, entity.Page{Limit: 3}).
+		Return([]entity.PendingLikes{{UserID: 1, Count: 2}, {UserID: 2, Count: 1}, {UserID: 3, Count: 5}}, nil)
+
+	items, hasMore, err := uc.UnansweredLikes(context.Background(), 24*time.Hour, entity.Page{Limit: 2})
+	assert.NoError(t, err)
+	assert.True(t, hasMore)
+	assert.Equal(t, []entity.PendingLikes{{UserID: 1, Count: 2}, {UserID: 2, Count: 1}}, items)
+}
+
+func TestNormalizePage(t *testing.T) {
+	assert.Equal(t, entity.Page{Limit: DefaultPageLimit}, NormalizePage(entity.Page{}))
+	assert.Equal(t, entity.Page{Limit: MaxPageLimit, Offset: 5}, NormalizePage(entity.Page{Limit: 1000, Offset: 5}))
+	assert.Equal(t, entity.Page{Limit: 10}, NormalizePage(entity.Page{Limit: 10, Offset: -1}))
+}
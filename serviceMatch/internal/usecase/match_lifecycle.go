@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"service3/internal/entity"
+	"time"
+)
+
+var ErrMatchNotFound = errors.New("match not found")
+
+// EndMatch - разрыв пары одним из участников. Пара остается в истории с отметкой,
+// кто и когда ее завершил, а участники навсегда пропадают из выдачи друг друга
+func (uc *Usecase) EndMatch(ctx context.Context, matchID, userID int64) error {
+	match, err := uc.repo.EndMatch(matchID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to end match: %w", err)
+	}
+	if match == nil {
+		return ErrMatchNotFound
+	}
+	log.Printf("User %d ended match %d", userID, matchID)
+
+	uc.hidePair(ctx, *match)
+	uc.sendEvent(ctx, entity.Event{
+		Type:       entity.EventMatchEnded,
+		FromUserID: userID,
+		ToUserID:   match.Other(userID),
+		MatchID:    match.ID,
+		Reason:     entity.EndReasonUnmatch,
+		CreatedAt:  time.Now(),
+	})
+	return nil
+}
+
+// FollowUpMatch - участник подтвердил, что общение продолжается; пара больше не истекает
+func (uc *Usecase) FollowUpMatch(ctx context.Context, matchID, userID int64) error {
+	ok, err := uc.repo.FollowUpMatch(matchID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to follow up match: %w", err)
+	}
+	if !ok {
+		return ErrMatchNotFound
+	}
+	return nil
+}
+
+// ExpireMatches - один проход автоистечения: напоминает о парах, которые скоро истекут,
+// и завершает пары без продолжения старше expiry
+func (uc *Usecase) ExpireMatches(ctx context.Context, expiry, remindBefore time.Duration) error {
+	now := time.Now()
+
+	if remindBefore > 0 && remindBefore < expiry {
+		toRemind, err := uc.repo.ClaimMatchesToRemind(now.Add(-(expiry - remindBefore)))
+		if err != nil {
+			return fmt.Errorf("failed to claim matches to remind: %w", err)
+		}
+		for _, m := range toRemind {
+			expiresAt := m.CreatedAt.Add(expiry)
+			uc.sendEvent(ctx, entity.Event{
+				Type:       entity.EventMatchExpiring,
+				FromUserID: m.UserA,
+				ToUserID:   m.UserB,
+				MatchID:    m.ID,
+				ExpiresAt:  &expiresAt,
+				CreatedAt:  now,
+			})
+		}
+	}
+
+	expired, err := uc.repo.ExpireMatches(now.Add(-expiry))
+	if err != nil {
+		return fmt.Errorf("failed to expire matches: %w", err)
+	}
+	for _, m := range expired {
+		log.Printf("Match %d expired", m.ID)
+		uc.hidePair(ctx, m)
+		uc.sendEvent(ctx, entity.Event{
+			Type:       entity.EventMatchEnded,
+			FromUserID: m.UserA,
+			ToUserID:   m.UserB,
+			MatchID:    m.ID,
+			Reason:     entity.EndReasonExpired,
+			CreatedAt:  now,
+		})
+	}
+	return nil
+}
+
+// RunMatchExpirer - периодически запускает ExpireMatches, пока не отменен ctx
+func (uc *Usecase) RunMatchExpirer(ctx context.Context, interval, expiry, remindBefore time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := uc.ExpireMatches(ctx, expiry, remindBefore); err != nil {
+				log.Printf("Error expiring matches: %v", err)
+			}
+		}
+	}
+}
+
+// hidePair - убирает участников пары из выдачи друг друга
+func (uc *Usecase) hidePair(ctx context.Context, m entity.Match) {
+	for _, pair := range [][2]int64{{m.UserA, m.UserB}, {m.UserB, m.UserA}} {
+		if err := uc.userClient.HideUser(ctx, pair[0], pair[1]); err != nil {
+			log.Printf("Error hiding user %d for %d: %v", pair[1], pair[0], err)
+		}
+	}
+}
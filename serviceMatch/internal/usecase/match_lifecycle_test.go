@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"service3/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// hideRecorder - serviceUser, который запоминает скрытые пары
+type hideRecorder struct {
+	noopUserClient
+	hidden [][2]int64
+}
+
+func (c *hideRecorder) HideUser(ctx context.Context, userID, hiddenID int64) error {
+	c.hidden = append(c.hidden, [2]int64{userID, hiddenID})
+	return nil
+}
+
+func TestUsecase_EndMatch(t *testing.T) {
+	t.Run("participant ends match", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		events := newEventRecorder()
+		users := &hideRecorder{}
+		uc := NewUseCase(repo, events, users, nil)
+
+		repo.On("EndMatch", int64(7), int64(2)).Return(&entity.Match{ID: 7, UserA: 1, UserB: 2}, nil)
+
+		assert.NoError(t, uc.EndMatch(context.Background(), 7, 2))
+		assert.Equal(t, [][2]int64{{1, 2}, {2, 1}}, users.hidden)
+
+		event := events.next(t)
+		assert.Equal(t, entity.EventMatchEnded, event.Type)
+		assert.Equal(t, int64(7), event.MatchID)
+		assert.Equal(t, int64(2), event.FromUserID)
+		assert.Equal(t, int64(1), event.ToUserID)
+		assert.Equal(t, entity.EndReasonUnmatch, event.Reason)
+	})
+
+	t.Run("unknown or foreign match", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		events := newEventRecorder()
+		users := &hideRecorder{}
+		uc := NewUseCase(repo, events, users, nil)
+
+		repo.On("EndMatch", int64(7), int64(3)).Return(nil, nil)
+
+		err := uc.EndMatch(context.Background(), 7, 3)
+		assert.True(t, errors.Is(err, ErrMatchNotFound))
+		assert.Empty(t, users.hidden)
+		events.assertNoEvents(t)
+	})
+}
+
+func TestUsecase_ExpireMatches(t *testing.T) {
+	repo := new(MockMatchRepository)
+	events := newEventRecorder()
+	users := &hideRecorder{}
+	uc := NewUseCase(repo, events, users, nil)
+
+	created := time.Now().Add(-60 * time.Hour)
+	repo.On("ClaimMatchesToRemind", mock.AnythingOfType("time.Time")).Return([]entity.Match{{ID: 1, UserA: 10, UserB: 11, CreatedAt: created}}, nil)
+	repo.On("ExpireMatches", mock.AnythingOfType("time.Time")).Return([]entity.Match{{ID: 2, UserA: 20, UserB: 21}}, nil)
+
+	assert.NoError(t, uc.ExpireMatches(context.Background(), 72*time.Hour, 24*time.Hour))
+
+	byType := map[string]entity.Event{}
+	for i := 0; i < 2; i++ {
+		event := events.next(t)
+		byType[event.Type] = event
+	}
+	reminder := byType[entity.EventMatchExpiring]
+	assert.Equal(t, int64(1), reminder.MatchID)
+	if assert.NotNil(t, reminder.ExpiresAt) {
+		assert.WithinDuration(t, created.Add(72*time.Hour), *reminder.ExpiresAt, time.Second)
+	}
+	ended := byType[entity.EventMatchEnded]
+	assert.Equal(t, int64(2), ended.MatchID)
+	assert.Equal(t, entity.EndReasonExpired, ended.Reason)
+	assert.Equal(t, [][2]int64{{20, 21}, {21, 20}}, users.hidden)
+
+	// Напоминание отправляется, когда до истечения остается remindBefore
+	remindCutoff := repo.Calls[0].Arguments.Get(0).(time.Time)
+	assert.WithinDuration(t, time.Now().Add(-48*time.Hour), remindCutoff, time.Second)
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"service3/internal/entity"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQuotaExceeded - дневной лимит лайков или суперлайков исчерпан
+	ErrQuotaExceeded = errors.New("daily like limit reached")
+	// ErrUndoLimitReached - дневной лимит отмен свайпов исчерпан
+	ErrUndoLimitReached = errors.New("daily undo limit reached")
+)
+
+// QuotaCounter - хранилище дневных счетчиков
+type QuotaCounter interface {
+	Consume(ctx context.Context, key entity.QuotaKey, limit int) (bool, error)
+	Refund(ctx context.Context, key entity.QuotaKey) error
+	Used(ctx context.Context, key entity.QuotaKey) (int, error)
+}
+
+// TimeZones - часовой пояс пользователя (IANA) из serviceUser; "" - неизвестен
+type TimeZones interface {
+	TimeZone(ctx context.Context, userID int64) (string, error)
+}
+
+const (
+	// zoneTTL - сколько помнить пояс пользователя: смена пояса вступает в силу
+	// не сразу, зато на каждый лайк не нужен запрос в serviceUser
+	zoneTTL = 10 * time.Minute
+	// maxZones - после такого числа запомненных поясов устаревшие удаляются
+	maxZones = 10000
+)
+
+type cachedZone struct {
+	location  *time.Location
+	checkedAt time.Time
+}
+
+// QuotaLimits - дневные лимиты и часовой пояс, в полночь которого они
+// сбрасываются, если пояс пользователя неизвестен
+type QuotaLimits struct {
+	Likes      int
+	SuperLikes int
+	Undos      int
+	Location   *time.Location
+}
+
+// Quota - дневные квоты. Счетчики живут в Redis (primary), при его
+// недоступности используется Postgres (fallback). Сутки считаются в часовом
+// поясе пользователя
+type Quota struct {
+	primary  QuotaCounter
+	fallback QuotaCounter
+	limits   QuotaLimits
+	now      func() time.Time
+
+	zones   TimeZones
+	zonesMu sync.Mutex
+	zoneOf  map[int64]cachedZone
+}
+
+// NewQuota - primary может быть nil, тогда счетчики сразу пишутся в fallback
+func NewQuota(primary, fallback QuotaCounter, limits QuotaLimits) *Quota {
+	if limits.Location == nil {
+		limits.Location = time.UTC
+	}
+	return &Quota{primary: primary, fallback: fallback, limits: limits, now: time.Now, zoneOf: make(map[int64]cachedZone)}
+}
+
+// SetTimeZones - откуда брать часовые пояса пользователей; без них квоты
+// всех пользователей сбрасываются в полночь limits.Location
+func (q *Quota) SetTimeZones(zones TimeZones) {
+	q.zones = zones
+}
+
+// location - часовой пояс пользователя. Если он неизвестен или serviceUser
+// недоступен, используется limits.Location
+func (q *Quota) location(ctx context.Context, userID int64) *time.Location {
+	if q.zones == nil {
+		return q.limits.Location
+	}
+	now := q.now()
+	q.zonesMu.Lock()
+	cached, ok := q.zoneOf[userID]
+	q.zonesMu.Unlock()
+	if ok && now.Sub(cached.checkedAt) <= zoneTTL {
+		return cached.location
+	}
+
+	name, err := q.zones.TimeZone(ctx, userID)
+	if err != nil {
+		// Ошибку не запоминаем, чтобы при следующем лайке спросить снова
+		log.Printf("Error loading time zone of user %d: %v", userID, err)
+		return q.limits.Location
+	}
+	location := q.limits.Location
+	if name != "" {
+		if loaded, err := time.LoadLocation(name); err == nil {
+			location = loaded
+		} else {
+			log.Printf("Unknown time zone %q of user %d: %v", name, userID, err)
+		}
+	}
+
+	q.zonesMu.Lock()
+	defer q.zonesMu.Unlock()
+	q.zoneOf[userID] = cachedZone{location: location, checkedAt: now}
+	if len(q.zoneOf) > maxZones {
+		for id, zone := range q.zoneOf {
+			if now.Sub(zone.checkedAt) > zoneTTL {
+				delete(q.zoneOf, id)
+			}
+		}
+	}
+	return location
+}
+
+func (q *Quota) limit(kind string) int {
+	switch kind {
+	case entity.QuotaSuperLike:
+		return q.limits.SuperLikes
+	case entity.QuotaUndo:
+		return q.limits.Undos
+	}
+	return q.limits.Likes
+}
+
+func (q *Quota) key(userID int64, kind string, location *time.Location) entity.QuotaKey {
+	return entity.QuotaKey{UserID: userID, Kind: kind, Day: q.now().In(location).Format("2006-01-02")}
+}
+
+// resetsAt - ближайшая полночь в часовом поясе пользователя
+func (q *Quota) resetsAt(location *time.Location) time.Time {
+	now := q.now().In(location)
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, location)
+}
+
+// Consume - списывает одно использование; false - лимит исчерпан
+func (q *Quota) Consume(ctx context.Context, userID int64, kind string) (bool, error) {
+	key := q.key(userID, kind, q.location(ctx, userID))
+	if q.primary != nil {
+		ok, err := q.primary.Consume(ctx, key, q.limit(kind))
+		if err == nil {
+			return ok, nil
+		}
+		log.Printf("Quota storage unavailable, falling back to Postgres: %v", err)
+	}
+	return q.fallback.Consume(ctx, key, q.limit(kind))
+}
+
+// Refund - возвращает использование, если лайк не был сохранен или уже существовал
+func (q *Quota) Refund(ctx context.Context, userID int64, kind string) {
+	key := q.key(userID, kind, q.location(ctx, userID))
+	if q.primary != nil {
+		if err := q.primary.Refund(ctx, key); err == nil {
+			return
+		}
+	}
+	if err := q.fallback.Refund(ctx, key); err != nil {
+		log.Printf("Error refunding %s quota of user %d: %v", kind, userID, err)
+	}
+}
+
+// Remaining - сколько лайков, суперлайков и отмен осталось на сегодня
+func (q *Quota) Remaining(ctx context.Context, userID int64) (entity.Quota, error) {
+	location := q.location(ctx, userID)
+	likes, err := q.remaining(ctx, userID, entity.QuotaLike, location)
+	if err != nil {
+		return entity.Quota{}, err
+	}
+	superLikes, err := q.remaining(ctx, userID, entity.QuotaSuperLike, location)
+	if err != nil {
+		return entity.Quota{}, err
+	}
+	undos, err := q.remaining(ctx, userID, entity.QuotaUndo, location)
+	if err != nil {
+		return entity.Quota{}, err
+	}
+	return entity.Quota{Likes: likes, SuperLikes: superLikes, Undos: undos, ResetsAt: q.resetsAt(location)}, nil
+}
+
+func (q *Quota) remaining(ctx context.Context, userID int64, kind string, location *time.Location) (int, error) {
+	key := q.key(userID, kind, location)
+	var (
+		used int
+		err  error
+	)
+	if q.primary != nil {
+		used, err = q.primary.Used(ctx, key)
+	}
+	if q.primary == nil || err != nil {
+		used, err = q.fallback.Used(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return max(q.limit(kind)-used, 0), nil
+}
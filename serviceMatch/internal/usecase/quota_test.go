@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"service3/internal/entity"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryCounter - счетчики квот в памяти; err имитирует недоступное хранилище
+type memoryCounter struct {
+	used map[entity.QuotaKey]int
+	err  error
+}
+
+func newMemoryCounter() *memoryCounter {
+	return &memoryCounter{used: make(map[entity.QuotaKey]int)}
+}
+
+func (c *memoryCounter) Consume(ctx context.Context, key entity.QuotaKey, limit int) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+	if c.used[key] >= limit {
+		return false, nil
+	}
+	c.used[key]++
+	return true, nil
+}
+
+func (c *memoryCounter) Refund(ctx context.Context, key entity.QuotaKey) error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.used[key] > 0 {
+		c.used[key]--
+	}
+	return nil
+}
+
+func (c *memoryCounter) Used(ctx context.Context, key entity.QuotaKey) (int, error) {
+	return c.used[key], c.err
+}
+
+// fakeZones - часовые пояса пользователей; calls считает запросы в serviceUser
+type fakeZones struct {
+	zones map[int64]string
+	err   error
+	calls int
+}
+
+func (z *fakeZones) TimeZone(ctx context.Context, userID int64) (string, error) {
+	z.calls++
+	return z.zones[userID], z.err
+}
+
+func TestQuota(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	limits := QuotaLimits{Likes: 2, SuperLikes: 1, Location: moscow}
+	ctx := context.Background()
+
+	t.Run("limit and reset at local midnight", func(t *testing.T) {
+		quota := NewQuota(newMemoryCounter(), newMemoryCounter(), limits)
+		// 23:30 по Москве
+		quota.now = func() time.Time { return time.Date(2024, 5, 1, 20, 30, 0, 0, time.UTC) }
+
+		for i := 0; i < 2; i++ {
+			ok, err := quota.Consume(ctx, 1, entity.QuotaLike)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		}
+		ok, _ := quota.Consume(ctx, 1, entity.QuotaLike)
+		assert.False(t, ok)
+
+		remaining, err := quota.Remaining(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, remaining.Likes)
+		assert.Equal(t, 1, remaining.SuperLikes)
+		assert.Equal(t, time.Date(2024, 5, 2, 0, 0, 0, 0, moscow), remaining.ResetsAt)
+
+		// 00:10 по Москве - новый день
+		quota.now = func() time.Time { return time.Date(2024, 5, 1, 21, 10, 0, 0, time.UTC) }
+		ok, _ = quota.Consume(ctx, 1, entity.QuotaLike)
+		assert.True(t, ok)
+	})
+
+	t.Run("reset at midnight in the user's time zone", func(t *testing.T) {
+		tokyo, _ := time.LoadLocation("Asia/Tokyo")
+		zones := &fakeZones{zones: map[int64]string{1: "Asia/Tokyo"}}
+		quota := NewQuota(newMemoryCounter(), newMemoryCounter(), limits)
+		quota.SetTimeZones(zones)
+		// 23:30 по Москве, в Токио уже 05:30 следующего дня
+		quota.now = func() time.Time { return time.Date(2024, 5, 1, 20, 30, 0, 0, time.UTC) }
+
+		remaining, err := quota.Remaining(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 5, 3, 0, 0, 0, 0, tokyo), remaining.ResetsAt)
+
+		for i := 0; i < 2; i++ {
+			ok, _ := quota.Consume(ctx, 1, entity.QuotaLike)
+			assert.True(t, ok)
+		}
+		assert.Equal(t, 1, zones.calls, "пояс запоминается на zoneTTL")
+		// 00:10 по Москве: у московского пользователя был бы новый день, в Токио - нет
+		quota.now = func() time.Time { return time.Date(2024, 5, 1, 21, 10, 0, 0, time.UTC) }
+		ok, _ := quota.Consume(ctx, 1, entity.QuotaLike)
+		assert.False(t, ok)
+	})
+
+	t.Run("unknown time zone falls back to the service zone", func(t *testing.T) {
+		for name, zones := range map[string]*fakeZones{
+			"not set":      {zones: map[int64]string{}},
+			"invalid":      {zones: map[int64]string{1: "Mars/Olympus"}},
+			"user is down": {err: errors.New("connection refused")},
+		} {
+			t.Run(name, func(t *testing.T) {
+				quota := NewQuota(newMemoryCounter(), newMemoryCounter(), limits)
+				quota.SetTimeZones(zones)
+				quota.now = func() time.Time { return time.Date(2024, 5, 1, 20, 30, 0, 0, time.UTC) }
+
+				remaining, err := quota.Remaining(ctx, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, time.Date(2024, 5, 2, 0, 0, 0, 0, moscow), remaining.ResetsAt)
+			})
+		}
+	})
+
+	t.Run("falls back to postgres when redis is down", func(t *testing.T) {
+		primary := newMemoryCounter()
+		primary.err = errors.New("connection refused")
+		fallback := newMemoryCounter()
+		quota := NewQuota(primary, fallback, limits)
+
+		ok, err := quota.Consume(ctx, 1, entity.QuotaSuperLike)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		ok, _ = quota.Consume(ctx, 1, entity.QuotaSuperLike)
+		assert.False(t, ok)
+
+		remaining, err := quota.Remaining(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, remaining.SuperLikes)
+	})
+}
+
+func TestUsecase_LikeQuota(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("exhausted quota", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		events := newEventRecorder()
+		quota := NewQuota(nil, newMemoryCounter(), QuotaLimits{Likes: 0, SuperLikes: 1})
+		uc := NewUseCase(repo, events, noopUserClient{}, quota)
+
+		_, err := uc.Like(ctx, entity.Like{FromUserID: 1, ToUserID: 2})
+		assert.True(t, errors.Is(err, ErrQuotaExceeded))
+		repo.AssertNotCalled(t, "SaveLike", entity.Like{FromUserID: 1, ToUserID: 2})
+		events.assertNoEvents(t)
+	})
+
+	t.Run("repeated like is refunded", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		quota := NewQuota(nil, newMemoryCounter(), QuotaLimits{Likes: 1})
+		uc := NewUseCase(repo, newEventRecorder(), noopUserClient{}, quota)
+
+		repo.On("SaveLike", entity.Like{FromUserID: 1, ToUserID: 2}).Return(false, nil)
+
+		_, err := uc.Like(ctx, entity.Like{FromUserID: 1, ToUserID: 2})
+		assert.NoError(t, err)
+		remaining, err := uc.Remaining(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, remaining.Likes)
+	})
+
+	t.Run("super like is prioritized", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		events := newEventRecorder()
+		quota := NewQuota(nil, newMemoryCounter(), QuotaLimits{Likes: 5, SuperLikes: 1})
+		uc := NewUseCase(repo, events, noopUserClient{}, quota)
+
+		like := entity.Like{FromUserID: 1, ToUserID: 2, Super: true}
+		repo.On("SaveLike", like).Return(true, nil)
+		repo.On("CheckMatch", int64(2), int64(1)).Return(false, nil)
+
+		_, err := uc.Like(ctx, like)
+		assert.NoError(t, err)
+		event := events.next(t)
+		assert.True(t, event.Super)
+		assert.Equal(t, entity.PriorityHigh, event.Priority)
+
+		remaining, _ := uc.Remaining(ctx, 1)
+		assert.Equal(t, 5, remaining.Likes)
+		assert.Equal(t, 0, remaining.SuperLikes)
+	})
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"service3/internal/entity"
+	"time"
+)
+
+// DefaultUndoWindow - сколько времени после свайпа его можно отменить
+const DefaultUndoWindow = 5 * time.Minute
+
+// ErrNothingToUndo - свайпов нет, последний уже отменен или окно отмены прошло
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// SetUndoWindow - задает окно, в течение которого свайп можно отменить
+func (uc *Usecase) SetUndoWindow(window time.Duration) {
+	uc.undoWindow = window
+}
+
+// Dislike - записывает дизлайк, чтобы его можно было отменить
+func (uc *Usecase) Dislike(ctx context.Context, fromUserID, toUserID int64) error {
+	if fromUserID == toUserID {
+		return ErrSelfLike
+	}
+	limited, err := uc.screen(ctx, fromUserID, toUserID)
+	if err != nil {
+		return err
+	}
+	err = uc.repo.SaveSwipe(entity.Swipe{FromUserID: fromUserID, ToUserID: toUserID, Kind: entity.SwipeDislike, Limited: limited})
+	if err != nil {
+		return fmt.Errorf("failed to save dislike: %w", err)
+	}
+	if !limited {
+		uc.rate(toUserID, fromUserID, false)
+	}
+
+	go func() {
+		if err := uc.userClient.TouchActivity(context.Background(), fromUserID); err != nil {
+			log.Printf("Error touching activity of user %d: %v", fromUserID, err)
+		}
+	}()
+	return nil
+}
+
+// Undo - отменяет последний свайп пользователя в пределах окна отмены.
+// Отмененный лайк снимается вместе с парой, которую он создал, а получатели
+// узнают об этом компенсирующими событиями. Изменение рейтинга от свайпа
+// откатывается. Число отмен в день ограничено
+func (uc *Usecase) Undo(ctx context.Context, userID int64) (*entity.Swipe, error) {
+	if uc.quota != nil {
+		ok, err := uc.quota.Consume(ctx, userID, entity.QuotaUndo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check quota: %w", err)
+		}
+		if !ok {
+			return nil, ErrUndoLimitReached
+		}
+	}
+
+	swipe, matchDeleted, err := uc.repo.UndoLastSwipe(userID, time.Now().Add(-uc.undoWindow))
+	if err != nil {
+		uc.refund(ctx, userID, entity.QuotaUndo)
+		return nil, fmt.Errorf("failed to undo swipe: %w", err)
+	}
+	if swipe == nil {
+		uc.refund(ctx, userID, entity.QuotaUndo)
+		return nil, ErrNothingToUndo
+	}
+	log.Printf("User %d undid %s of user %d", userID, swipe.Kind, swipe.ToUserID)
+
+	if !swipe.Limited {
+		uc.unrate(swipe.ToUserID, swipe.FromUserID, swipe.IsLike())
+	}
+
+	if !swipe.IsLike() {
+		return swipe, nil
+	}
+
+	// Отмененный лайк не расходует дневную квоту
+	kind := entity.QuotaLike
+	if swipe.Kind == entity.SwipeSuperLike {
+		kind = entity.QuotaSuperLike
+	}
+	uc.refund(ctx, userID, kind)
+
+	// О лайке под ограничением получатель не узнал, отзывать нечего
+	if swipe.Limited {
+		return swipe, nil
+	}
+	uc.sendEvent(ctx, entity.Event{
+		Type:       entity.EventLikeUndone,
+		FromUserID: swipe.FromUserID,
+		ToUserID:   swipe.ToUserID,
+		Super:      swipe.Kind == entity.SwipeSuperLike,
+		CreatedAt:  time.Now(),
+	})
+	if matchDeleted {
+		uc.sendEvent(ctx, entity.Event{
+			Type:       entity.EventUnmatch,
+			FromUserID: swipe.FromUserID,
+			ToUserID:   swipe.ToUserID,
+			CreatedAt:  time.Now(),
+		})
+	}
+	return swipe, nil
+}
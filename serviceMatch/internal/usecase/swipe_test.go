@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"service3/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// unrateRecorder - клиент serviceUser, который запоминает откаты рейтинга
+type unrateRecorder struct {
+	noopUserClient
+	calls chan [2]int64
+}
+
+func (r *unrateRecorder) UnrateUser(ctx context.Context, targetID, raterID int64, liked bool) error {
+	r.calls <- [2]int64{targetID, raterID}
+	return nil
+}
+
+func TestUsecase_Undo(t *testing.T) {
+	ctx := context.Background()
+	since := mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) >= DefaultUndoWindow && time.Since(since) < DefaultUndoWindow+time.Minute
+	})
+
+	t.Run("dislike is undone without events", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		events := newEventRecorder()
+		uc := NewUseCase(repo, events, noopUserClient{}, nil)
+
+		swipe := &entity.Swipe{ID: 7, FromUserID: 1, ToUserID: 2, Kind: entity.SwipeDislike}
+		repo.On("UndoLastSwipe", int64(1), since).Return(swipe, false, nil)
+
+		got, err := uc.Undo(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, swipe, got)
+		events.assertNoEvents(t)
+	})
+
+	t.Run("like with match emits compensating events and refunds quota", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		events := newEventRecorder()
+		counter := newMemoryCounter()
+		quota := NewQuota(nil, counter, QuotaLimits{Likes: 5, SuperLikes: 1, Undos: 3})
+		uc := NewUseCase(repo, events, noopUserClient{}, quota)
+
+		ok, _ := quota.Consume(ctx, 1, entity.QuotaSuperLike)
+		assert.True(t, ok)
+		swipe := &entity.Swipe{ID: 8, FromUserID: 1, ToUserID: 2, Kind: entity.SwipeSuperLike}
+		repo.On("UndoLastSwipe", int64(1), since).Return(swipe, true, nil)
+
+		_, err := uc.Undo(ctx, 1)
+		assert.NoError(t, err)
+
+		received := map[string]entity.Event{}
+		for i := 0; i < 2; i++ {
+			event := events.next(t)
+			received[event.Type] = event
+		}
+		assert.Contains(t, received, entity.EventLikeUndone)
+		assert.Contains(t, received, entity.EventUnmatch)
+		assert.True(t, received[entity.EventLikeUndone].Super)
+
+		remaining, _ := uc.Remaining(ctx, 1)
+		assert.Equal(t, 1, remaining.SuperLikes)
+		assert.Equal(t, 2, remaining.Undos)
+	})
+
+	t.Run("rating is reverted", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		users := &unrateRecorder{calls: make(chan [2]int64, 1)}
+		uc := NewUseCase(repo, newEventRecorder(), users, nil)
+
+		swipe := &entity.Swipe{ID: 10, FromUserID: 1, ToUserID: 2, Kind: entity.SwipeDislike}
+		repo.On("UndoLastSwipe", int64(1), since).Return(swipe, false, nil)
+
+		_, err := uc.Undo(ctx, 1)
+		assert.NoError(t, err)
+		select {
+		case call := <-users.calls:
+			assert.Equal(t, [2]int64{2, 1}, call)
+		case <-time.After(time.Second):
+			t.Fatal("rating was not reverted")
+		}
+	})
+
+	t.Run("shadow-limited swipe did not change rating", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		users := &unrateRecorder{calls: make(chan [2]int64, 1)}
+		events := newEventRecorder()
+		uc := NewUseCase(repo, events, users, nil)
+
+		swipe := &entity.Swipe{ID: 11, FromUserID: 1, ToUserID: 2, Kind: entity.SwipeLike, Limited: true}
+		repo.On("UndoLastSwipe", int64(1), since).Return(swipe, false, nil)
+
+		_, err := uc.Undo(ctx, 1)
+		assert.NoError(t, err)
+		select {
+		case call := <-users.calls:
+			t.Fatalf("unexpected rating revert %v", call)
+		case <-time.After(50 * time.Millisecond):
+		}
+		events.assertNoEvents(t)
+	})
+
+	t.Run("nothing to undo does not spend the limit", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		quota := NewQuota(nil, newMemoryCounter(), QuotaLimits{Undos: 1})
+		uc := NewUseCase(repo, newEventRecorder(), noopUserClient{}, quota)
+
+		repo.On("UndoLastSwipe", int64(1), since).Return(nil, false, nil)
+
+		_, err := uc.Undo(ctx, 1)
+		assert.True(t, errors.Is(err, ErrNothingToUndo))
+		remaining, _ := uc.Remaining(ctx, 1)
+		assert.Equal(t, 1, remaining.Undos)
+	})
+
+	t.Run("daily limit", func(t *testing.T) {
+		repo := new(MockMatchRepository)
+		quota := NewQuota(nil, newMemoryCounter(), QuotaLimits{Undos: 1})
+		uc := NewUseCase(repo, newEventRecorder(), noopUserClient{}, quota)
+
+		swipe := &entity.Swipe{ID: 9, FromUserID: 1, ToUserID: 2, Kind: entity.SwipeDislike}
+		repo.On("UndoLastSwipe", int64(1), since).Return(swipe, false, nil).Once()
+
+		_, err := uc.Undo(ctx, 1)
+		assert.NoError(t, err)
+		_, err = uc.Undo(ctx, 1)
+		assert.True(t, errors.Is(err, ErrUndoLimitReached))
+		repo.AssertNumberOfCalls(t, "UndoLastSwipe", 1)
+	})
+}
@@ -2,43 +2,244 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"service3/internal/entity"
+	"time"
 )
 
 type MatchRepository interface {
-	SaveLike(fromUserID, toUserID int64) error
+	SaveLike(like entity.Like) (bool, error)
 	CheckMatch(fromUserID, toUserID int64) (bool, error)
+	SaveMatch(userA, userB int64) (id int64, created bool, err error)
+	DeleteLike(fromUserID, toUserID int64) (likeDeleted, matchDeleted bool, err error)
+	IncomingLikes(userID int64, answered *bool, page entity.Page) ([]entity.LikeItem, error)
+	LikeVisible(fromUserID, toUserID int64) (bool, error)
+	OutgoingLikes(userID int64, page entity.Page) ([]entity.LikeItem, error)
+	Matches(userID int64, page entity.Page) ([]entity.MatchItem, error)
+	UnansweredLikes(createdBefore time.Time, page entity.Page) ([]entity.PendingLikes, error)
+	EndMatch(matchID, userID int64) (*entity.Match, error)
+	FollowUpMatch(matchID, userID int64) (bool, error)
+	ClaimMatchesToRemind(createdBefore time.Time) ([]entity.Match, error)
+	ExpireMatches(createdBefore time.Time) ([]entity.Match, error)
+	SaveSwipe(swipe entity.Swipe) error
+	UndoLastSwipe(userID int64, since time.Time) (*entity.Swipe, bool, error)
 }
 
 type MatchKafka interface {
 	SendMessage(ctx context.Context, message string) error
 }
 
+type UserClient interface {
+	TouchActivity(ctx context.Context, userID int64) error
+	GetProfiles(ctx context.Context, ids []int64) ([]entity.Profile, error)
+	HideUser(ctx context.Context, userID, hiddenID int64) error
+	RateUser(ctx context.Context, targetID, raterID int64, liked bool) error
+	UnrateUser(ctx context.Context, targetID, raterID int64, liked bool) error
+}
+
+var (
+	ErrSelfLike     = errors.New("user cannot like themselves")
+	ErrLikeNotFound = errors.New("like not found")
+)
+
 type Usecase struct {
 	repo          MatchRepository
 	kafkaProducer MatchKafka
+	userClient    UserClient
+	quota         *Quota
+	antiSpam      *AntiSpam
+	undoWindow    time.Duration
 }
 
-func NewUseCase(repo MatchRepository, kafkaProducer MatchKafka) *Usecase {
-	return &Usecase{repo: repo, kafkaProducer: kafkaProducer}
+// NewUseCase - quota может быть nil, тогда лайки не ограничиваются
+func NewUseCase(repo MatchRepository, kafkaProducer MatchKafka, userClient UserClient, quota *Quota) *Usecase {
+	return &Usecase{repo: repo, kafkaProducer: kafkaProducer, userClient: userClient, quota: quota, undoWindow: DefaultUndoWindow}
 }
 
-// Like - процесс лайкания и проверки совпадений
-func (uc *Usecase) Like(ctx context.Context, fromUserID, toUserID int64) error {
-	log.Printf("User %d liked user %d", fromUserID, toUserID)
+// SetAntiSpam - включает проверки антиспама перед каждым свайпом
+func (uc *Usecase) SetAntiSpam(antiSpam *AntiSpam) {
+	uc.antiSpam = antiSpam
+}
+
+// screen - проверки антиспама; limited - аккаунт под теневым ограничением
+func (uc *Usecase) screen(ctx context.Context, fromUserID, toUserID int64) (bool, error) {
+	if uc.antiSpam == nil {
+		return false, nil
+	}
+	return uc.antiSpam.Check(ctx, fromUserID, toUserID)
+}
+
+// Like - процесс лайкания и проверки совпадений.
+// Возвращает true, если лайк поставлен впервые; события отправляются только для новых лайков.
+// Лайк аккаунта под теневым ограничением сохраняется, но без событий, рейтинга и пары
+func (uc *Usecase) Like(ctx context.Context, like entity.Like) (bool, error) {
+	fromUserID := like.FromUserID
+	log.Printf("User %d liked user %d", fromUserID, like.ToUserID)
+
+	if like.FromUserID == like.ToUserID {
+		return false, ErrSelfLike
+	}
+	like, err := normalizeLike(like)
+	if err != nil {
+		return false, err
+	}
+	limited, err := uc.screen(ctx, fromUserID, like.ToUserID)
+	if err != nil {
+		return false, err
+	}
+
+	// Списание дневной квоты; если лайк не сохранится или уже был, она возвращается
+	kind := entity.QuotaLike
+	if like.Super {
+		kind = entity.QuotaSuperLike
+	}
+	if uc.quota != nil {
+		ok, err := uc.quota.Consume(ctx, fromUserID, kind)
+		if err != nil {
+			return false, fmt.Errorf("failed to check quota: %w", err)
+		}
+		if !ok {
+			return false, ErrQuotaExceeded
+		}
+	}
 
 	// Сохранение лайка в репозитории
-	if err := uc.repo.SaveLike(fromUserID, toUserID); err != nil {
-		return fmt.Errorf("failed to save like: %w", err)
+	like.Limited = limited
+	created, err := uc.repo.SaveLike(like)
+	if err != nil {
+		uc.refund(ctx, fromUserID, kind)
+		return false, fmt.Errorf("failed to save like: %w", err)
 	}
 
-	// Создание события лайка и асинхронная отправка в Kafka
-	likeEvent := fmt.Sprintf("like_%d_%d", fromUserID, toUserID)
+	// Свайп считается активностью пользователя
 	go func() {
-		if err := uc.kafkaProducer.SendMessage(ctx, likeEvent); err != nil {
-			log.Printf("Error sending like event to Kafka: %v", err)
+		if err := uc.userClient.TouchActivity(context.Background(), fromUserID); err != nil {
+			log.Printf("Error touching activity of user %d: %v", fromUserID, err)
 		}
 	}()
+
+	if !created {
+		log.Printf("Like from %d to %d already exists", fromUserID, like.ToUserID)
+		uc.refund(ctx, fromUserID, kind)
+		return false, nil
+	}
+	if limited {
+		// Пара раскрыла бы лайк получателю, поэтому не создается
+		log.Printf("Like from %d to %d is shadow-limited", fromUserID, like.ToUserID)
+		return true, nil
+	}
+	uc.rate(like.ToUserID, fromUserID, true)
+
+	// Создание события лайка и асинхронная отправка в Kafka
+	likeEvent := entity.Event{
+		Type:       entity.EventLike,
+		FromUserID: like.FromUserID,
+		ToUserID:   like.ToUserID,
+		Target:     like.Target,
+		PromptID:   like.PromptID,
+		Comment:    like.Comment,
+		Super:      like.Super,
+		CreatedAt:  time.Now(),
+	}
+	if like.Super {
+		likeEvent.Priority = entity.PriorityHigh
+	}
+	uc.sendEvent(ctx, likeEvent)
+
+	// Проверка взаимности
+	mutual, err := uc.repo.CheckMatch(like.ToUserID, like.FromUserID)
+	if err != nil {
+		return true, fmt.Errorf("failed to check match: %w", err)
+	}
+	if mutual {
+		matchID, matched, err := uc.repo.SaveMatch(like.FromUserID, like.ToUserID)
+		if err != nil {
+			return true, fmt.Errorf("failed to save match: %w", err)
+		}
+		if matched {
+			uc.sendEvent(ctx, entity.Event{
+				Type:       entity.EventMatch,
+				FromUserID: like.FromUserID,
+				ToUserID:   like.ToUserID,
+				MatchID:    matchID,
+				CreatedAt:  time.Now(),
+			})
+		}
+	}
+	return true, nil
+}
+
+// Remaining - остаток дневных квот пользователя
+func (uc *Usecase) Remaining(ctx context.Context, userID int64) (*entity.Quota, error) {
+	if uc.quota == nil {
+		return nil, nil
+	}
+	quota, err := uc.quota.Remaining(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quota: %w", err)
+	}
+	return &quota, nil
+}
+
+func (uc *Usecase) refund(ctx context.Context, userID int64, kind string) {
+	if uc.quota != nil {
+		uc.quota.Refund(ctx, userID, kind)
+	}
+}
+
+// Unlike - снимает лайк; если он образовывал пару, пара удаляется и отправляется событие unmatch
+func (uc *Usecase) Unlike(ctx context.Context, fromUserID, toUserID int64) error {
+	likeDeleted, matchDeleted, err := uc.repo.DeleteLike(fromUserID, toUserID)
+	if err != nil {
+		return fmt.Errorf("failed to delete like: %w", err)
+	}
+	if !likeDeleted {
+		return ErrLikeNotFound
+	}
+	log.Printf("User %d unliked user %d", fromUserID, toUserID)
+
+	if matchDeleted {
+		uc.sendEvent(ctx, entity.Event{
+			Type:       entity.EventUnmatch,
+			FromUserID: fromUserID,
+			ToUserID:   toUserID,
+			CreatedAt:  time.Now(),
+		})
+	}
 	return nil
 }
+
+// rate - асинхронно сообщает serviceUser исход свайпа для Elo-рейтинга анкеты
+func (uc *Usecase) rate(targetID, raterID int64, liked bool) {
+	go func() {
+		if err := uc.userClient.RateUser(context.Background(), targetID, raterID, liked); err != nil {
+			log.Printf("Error rating user %d by %d: %v", targetID, raterID, err)
+		}
+	}()
+}
+
+// unrate - асинхронно откатывает Elo-рейтинг после отмены свайпа
+func (uc *Usecase) unrate(targetID, raterID int64, liked bool) {
+	go func() {
+		if err := uc.userClient.UnrateUser(context.Background(), targetID, raterID, liked); err != nil {
+			log.Printf("Error reverting rating of user %d by %d: %v", targetID, raterID, err)
+		}
+	}()
+}
+
+// sendEvent - асинхронная отправка события в Kafka
+func (uc *Usecase) sendEvent(ctx context.Context, event entity.Event) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error encoding %s event: %v", event.Type, err)
+		return
+	}
+	go func() {
+		if err := uc.kafkaProducer.SendMessage(ctx, string(message)); err != nil {
+			log.Printf("Error sending %s event to Kafka: %v", event.Type, err)
+		}
+	}()
+}
@@ -1,7 +1,9 @@
 package usecase
 
 import (
+	"service3/internal/entity"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -11,9 +13,14 @@ type MockMatchRepository struct {
 	mock.Mock
 }
 
-func (m *MockMatchRepository) SaveLike(fromUserID, toUserID int64) error {
+func (m *MockMatchRepository) SaveLike(like entity.Like) (bool, error) {
+	args := m.Called(like)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMatchRepository) LikeVisible(fromUserID, toUserID int64) (bool, error) {
 	args := m.Called(fromUserID, toUserID)
-	return args.Error(0)
+	return args.Bool(0), args.Error(1)
 }
 
 func (m *MockMatchRepository) CheckMatch(fromUserID, toUserID int64) (bool, error) {
@@ -21,6 +28,68 @@ func (m *MockMatchRepository) CheckMatch(fromUserID, toUserID int64) (bool, erro
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockMatchRepository) SaveMatch(userA, userB int64) (int64, bool, error) {
+	args := m.Called(userA, userB)
+	return args.Get(0).(int64), args.Bool(1), args.Error(2)
+}
+
+func (m *MockMatchRepository) EndMatch(matchID, userID int64) (*entity.Match, error) {
+	args := m.Called(matchID, userID)
+	match, _ := args.Get(0).(*entity.Match)
+	return match, args.Error(1)
+}
+
+func (m *MockMatchRepository) FollowUpMatch(matchID, userID int64) (bool, error) {
+	args := m.Called(matchID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMatchRepository) ClaimMatchesToRemind(createdBefore time.Time) ([]entity.Match, error) {
+	args := m.Called(createdBefore)
+	return args.Get(0).([]entity.Match), args.Error(1)
+}
+
+func (m *MockMatchRepository) ExpireMatches(createdBefore time.Time) ([]entity.Match, error) {
+	args := m.Called(createdBefore)
+	return args.Get(0).([]entity.Match), args.Error(1)
+}
+
+func (m *MockMatchRepository) DeleteLike(fromUserID, toUserID int64) (bool, bool, error) {
+	args := m.Called(fromUserID, toUserID)
+	return args.Bool(0), args.Bool(1), args.Error(2)
+}
+
+func (m *MockMatchRepository) IncomingLikes(userID int64, answered *bool, page entity.Page) ([]entity.LikeItem, error) {
+	args := m.Called(userID, answered, page)
+	return args.Get(0).([]entity.LikeItem), args.Error(1)
+}
+
+func (m *MockMatchRepository) OutgoingLikes(userID int64, page entity.Page) ([]entity.LikeItem, error) {
+	args := m.Called(userID, page)
+	return args.Get(0).([]entity.LikeItem), args.Error(1)
+}
+
+func (m *MockMatchRepository) Matches(userID int64, page entity.Page) ([]entity.MatchItem, error) {
+	args := m.Called(userID, page)
+	return args.Get(0).([]entity.MatchItem), args.Error(1)
+}
+
+func (m *MockMatchRepository) UnansweredLikes(createdBefore time.Time, page entity.Page) ([]entity.PendingLikes, error) {
+	args := m.Called(createdBefore, page)
+	return args.Get(0).([]entity.PendingLikes), args.Error(1)
+}
+
+func (m *MockMatchRepository) SaveSwipe(swipe entity.Swipe) error {
+	args := m.Called(swipe)
+	return args.Error(0)
+}
+
+func (m *MockMatchRepository) UndoLastSwipe(userID int64, since time.Time) (*entity.Swipe, bool, error) {
+	args := m.Called(userID, since)
+	swipe, _ := args.Get(0).(*entity.Swipe)
+	return swipe, args.Bool(1), args.Error(2)
+}
+
 // MockMatchKafka is a mock implementation of the MatchKafka interface
 type MockMatchKafka struct {
 	mock.Mock
@@ -42,13 +111,13 @@ func TestExample(t *testing.T) {
 	kafka := new(MockMatchKafka)
 
 	// Set up expectations
-	repo.On("SaveLike", int64(1), int64(2)).Return(nil)
+	repo.On("SaveLike", entity.Like{FromUserID: 1, ToUserID: 2}).Return(true, nil)
 	repo.On("CheckMatch", int64(1), int64(2)).Return(true, nil)
 	kafka.On("SendMessage", "topic", "message").Return(nil)
 	kafka.On("Close").Return(nil)
 
 	// Call the methods
-	err := repo.SaveLike(1, 2)
+	_, err := repo.SaveLike(entity.Like{FromUserID: 1, ToUserID: 2})
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
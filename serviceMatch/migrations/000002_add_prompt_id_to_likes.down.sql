@@ -0,0 +1 @@
+ALTER TABLE likes DROP COLUMN IF EXISTS prompt_id;
@@ -0,0 +1 @@
+ALTER TABLE likes ADD COLUMN prompt_id INT;
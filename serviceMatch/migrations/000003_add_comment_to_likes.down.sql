@@ -0,0 +1,2 @@
+ALTER TABLE likes DROP COLUMN IF EXISTS comment;
+ALTER TABLE likes DROP COLUMN IF EXISTS target;
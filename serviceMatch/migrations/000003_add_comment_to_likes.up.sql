@@ -0,0 +1,2 @@
+ALTER TABLE likes ADD COLUMN target TEXT;
+ALTER TABLE likes ADD COLUMN comment TEXT;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS matches;
+ALTER TABLE likes DROP CONSTRAINT IF EXISTS likes_not_self;
+ALTER TABLE likes DROP CONSTRAINT IF EXISTS likes_from_to_unique;
+ALTER TABLE likes DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,30 @@
+-- Убираем дубли, которые накопились из-за повторных нажатий ❤, и самолайки
+DELETE FROM likes a
+USING likes b
+WHERE a.from_user_id = b.from_user_id
+  AND a.to_user_id = b.to_user_id
+  AND a.id > b.id;
+
+DELETE FROM likes WHERE from_user_id = to_user_id;
+
+ALTER TABLE likes ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE likes ADD CONSTRAINT likes_from_to_unique UNIQUE (from_user_id, to_user_id);
+ALTER TABLE likes ADD CONSTRAINT likes_not_self CHECK (from_user_id <> to_user_id);
+
+-- Взаимные лайки. Пара хранится упорядоченной: user_a < user_b
+CREATE TABLE matches (
+    user_a BIGINT NOT NULL,
+    user_b BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_a, user_b),
+    CHECK (user_a < user_b)
+);
+
+CREATE INDEX idx_matches_user_b ON matches (user_b);
+
+-- Пары, которые уже лайкнули друг друга до появления таблицы
+INSERT INTO matches (user_a, user_b)
+SELECT l1.from_user_id, l1.to_user_id
+FROM likes l1
+JOIN likes l2 ON l2.from_user_id = l1.to_user_id AND l2.to_user_id = l1.from_user_id
+WHERE l1.from_user_id < l1.to_user_id;
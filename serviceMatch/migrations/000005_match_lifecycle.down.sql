@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_matches_active_created_at;
+ALTER TABLE matches DROP COLUMN IF EXISTS reminded_at;
+ALTER TABLE matches DROP COLUMN IF EXISTS followed_up_at;
+ALTER TABLE matches DROP COLUMN IF EXISTS end_reason;
+ALTER TABLE matches DROP COLUMN IF EXISTS ended_by;
+ALTER TABLE matches DROP COLUMN IF EXISTS ended_at;
+ALTER TABLE matches DROP COLUMN IF EXISTS id;
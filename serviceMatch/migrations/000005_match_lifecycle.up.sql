@@ -0,0 +1,8 @@
+ALTER TABLE matches ADD COLUMN id BIGSERIAL UNIQUE;
+ALTER TABLE matches ADD COLUMN ended_at TIMESTAMPTZ;
+ALTER TABLE matches ADD COLUMN ended_by BIGINT;        -- кто разорвал пару, NULL при истечении срока
+ALTER TABLE matches ADD COLUMN end_reason TEXT;        -- unmatch | expired
+ALTER TABLE matches ADD COLUMN followed_up_at TIMESTAMPTZ;
+ALTER TABLE matches ADD COLUMN reminded_at TIMESTAMPTZ;
+
+CREATE INDEX idx_matches_active_created_at ON matches (created_at) WHERE ended_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS like_quotas;
+ALTER TABLE likes DROP COLUMN IF EXISTS super;
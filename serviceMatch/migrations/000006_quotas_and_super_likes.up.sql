@@ -0,0 +1,10 @@
+ALTER TABLE likes ADD COLUMN super BOOLEAN NOT NULL DEFAULT false;
+
+-- Резервные счетчики дневных квот на случай недоступности Redis
+CREATE TABLE like_quotas (
+    telegram_id BIGINT NOT NULL,
+    day DATE NOT NULL,
+    kind TEXT NOT NULL,            -- like | superlike
+    used INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (telegram_id, day, kind)
+);
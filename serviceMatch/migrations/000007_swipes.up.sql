@@ -0,0 +1,10 @@
+CREATE TABLE swipes (
+    id BIGSERIAL PRIMARY KEY,
+    from_user_id BIGINT NOT NULL,
+    to_user_id BIGINT NOT NULL,
+    kind TEXT NOT NULL,                -- like | superlike | dislike
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    undone_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_swipes_from_user_id ON swipes (from_user_id, id DESC);
@@ -0,0 +1,12 @@
+CREATE TABLE spam_flags (
+    user_id BIGINT PRIMARY KEY,
+    reason TEXT NOT NULL,              -- like_ratio | fast_swipes
+    swipes INT NOT NULL,
+    likes INT NOT NULL,
+    fast_swipes INT NOT NULL,
+    flagged_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    cleared_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_spam_flags_active ON spam_flags (flagged_at DESC) WHERE cleared_at IS NULL;
+CREATE INDEX idx_swipes_from_user_created_at ON swipes (from_user_id, created_at);
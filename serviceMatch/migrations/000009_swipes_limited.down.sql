@@ -0,0 +1 @@
+ALTER TABLE swipes DROP COLUMN IF EXISTS limited;
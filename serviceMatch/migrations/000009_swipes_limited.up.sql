@@ -0,0 +1 @@
+ALTER TABLE swipes ADD COLUMN limited BOOLEAN NOT NULL DEFAULT false;
@@ -2,24 +2,135 @@ package main
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"fmt"
 	"log"
+	"net/http"
 	"serviceNotification/internal/adapter"
 	clientsUser "serviceNotification/internal/client"
 	"serviceNotification/internal/config"
 	"serviceNotification/internal/delivery"
+	"serviceNotification/internal/handler"
+	"serviceNotification/internal/repository"
+	"serviceNotification/internal/scheduler"
+	"serviceNotification/internal/sendqueue"
+	"serviceNotification/internal/storage"
 	"serviceNotification/internal/usecase"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// initScheduler - задачи напоминаний. Без Redis нет ни блокировок, ни пауз
+// между напоминаниями, поэтому планировщик не запускается
+func initScheduler(cfg *config.Config, uc *usecase.BotUsecase, userClient *clientsUser.HTTPUserServiseClient) (*scheduler.Scheduler, error) {
+	if cfg.MatchURL == "" {
+		return nil, errors.New("MATCH_SERVICE не задан")
+	}
+	location, err := time.LoadLocation(cfg.SchedulerTimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SCHEDULER_TIMEZONE: %w", err)
+	}
+	store, err := storage.NewRedisSchedulerStore(cfg.RedisAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := usecase.NewReengagement(uc, clientsUser.NewHTTPMatchClient(cfg.MatchURL), userClient, store, usecase.ReengagementConfig{
+		LikesAfter:       cfg.UnansweredLikesAfter,
+		LikesCooldown:    cfg.UnansweredLikesCooldown,
+		InactiveFor:      cfg.InactiveFor,
+		InactiveCooldown: cfg.NewInCityCooldown,
+	})
+	s := scheduler.New(store, location, cfg.JobTimeout)
+	if err := s.Add(usecase.JobUnansweredLikes, cfg.UnansweredLikesSchedule, jobs.UnansweredLikes); err != nil {
+		return nil, err
+	}
+	if err := s.Add(usecase.JobNewInCity, cfg.NewInCitySchedule, jobs.NewInCity); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
 func main() {
 	cfg := config.NewConfig()
 	userClient := clientsUser.NewHTTPUserServiseClient(cfg.UserURL)
-	sender, err := adapter.NewTelegramBot(cfg.TelegramToken, userClient)
+	botClient := clientsUser.NewHTTPBotClient(cfg.BotURL)
+
+	// Все запросы к Telegram проходят через очередь с лимитами
+	metrics := sendqueue.NewMetrics()
+	expvar.Publish("send_queue", metrics.Var())
+	queue := sendqueue.New(sendqueue.Config{
+		PerSecond: float64(cfg.SendRate),
+		Burst:     cfg.SendRate,
+		PerChat:   cfg.SendPerChat,
+	}, metrics)
+	go queue.Run(context.Background())
+	if cfg.MetricsAddr != "" {
+		go func() {
+			// expvar отдает метрики на /debug/vars стандартного mux
+			if err := http.ListenAndServe(cfg.MetricsAddr, nil); err != nil {
+				log.Printf("Ошибка сервера метрик: %v", err)
+			}
+		}()
+	}
+
+	sender, err := adapter.NewTelegramBot(cfg.TelegramToken, userClient, botClient, queue)
 	if err != nil {
 		log.Fatal(err)
 	}
-	bot := adapter.NewBothandle(*sender)
-	go bot.BotStart()
-	uc := usecase.NewBotUsecase(sender)
+	// Без Redis отправленные уведомления помнит только этот экземпляр и до перезапуска
+	if notices, err := storage.NewRedisNoticeStore(cfg.RedisAddr); err != nil {
+		log.Printf("Redis не подключен, уведомления можно отозвать только до перезапуска: %v", err)
+	} else {
+		sender.SetNotices(notices, cfg.LikeNoticeTTL, cfg.MatchNoticeTTL)
+	}
+	// Без Redis уведомления отправляются сразу: отключенные типы по-прежнему
+	// не приходят, но тихие часы и сводки не работают
+	var held usecase.HeldStore
+	if store, err := storage.NewRedisHeldStore(cfg.RedisAddr); err != nil {
+		log.Printf("Redis не подключен, отложенные уведомления недоступны: %v", err)
+	} else {
+		held = store
+	}
+	// Без продюсера недоступные пользователи только перестают получать
+	// уведомления, но их анкеты остаются в выдаче
+	var unreachable usecase.UnreachablePublisher
+	if producer, err := delivery.NewUserEventProducer(cfg.KafkaBrokers, cfg.KafkaUserTopic); err != nil {
+		log.Printf("События о пользователях не публикуются: %v", err)
+	} else {
+		unreachable = producer
+	}
+	uc := usecase.NewBotUsecase(sender, userClient, held, unreachable, cfg.DigestInterval)
+	router := gin.Default()
+	if cfg.DatabaseURL == "" {
+		log.Println("DATABASE_URL не задан, журнал доставки и входящие недоступны")
+	} else {
+		pool, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
+		if err != nil {
+			log.Fatalf("failed to connect to the database: %v", err)
+		}
+		repo := repository.NewNotificationRepository(pool)
+		uc.SetDeliveryLog(repo)
+		handler.NewInboxHandler(usecase.NewInboxUsecase(repo), router)
+	}
+	if jobs, err := initScheduler(cfg, uc, userClient); err != nil {
+		log.Printf("Планировщик напоминаний отключен: %v", err)
+	} else {
+		handler.NewAdminHandler(jobs, router, cfg.AdminToken)
+		go jobs.Run(context.Background())
+	}
+	go func() {
+		log.Println("Starting server on", cfg.HTTPAddr)
+		if err := router.Run(cfg.HTTPAddr); err != nil {
+			log.Fatalf("Error starting server: %v", err)
+		}
+	}()
+
+	go uc.RunScheduler(context.Background(), cfg.HeldPollInterval)
 	kfk, err := delivery.NewKafkaConsumer(cfg.KafkaBrokers, cfg.KafkaLikeTopic, cfg.GroupId, uc)
 	if err != nil {
 		log.Fatal(err)
@@ -0,0 +1,34 @@
+package adapter
+
+import (
+	"errors"
+	"serviceNotification/internal/entity"
+
+	"gopkg.in/telebot.v4"
+)
+
+// classify - причина ошибки доставки, одна из entity.Failure*
+func classify(err error) string {
+	switch {
+	case errors.Is(err, telebot.ErrBlockedByUser), errors.Is(err, telebot.ErrNotStartedByUser):
+		return entity.FailureBlocked
+	case errors.Is(err, telebot.ErrUserIsDeactivated):
+		return entity.FailureDeactivated
+	case errors.Is(err, telebot.ErrChatNotFound):
+		return entity.FailureChatNotFound
+	default:
+		return entity.FailureTransient
+	}
+}
+
+// deliveryError - постоянные ошибки оборачиваются в UnreachableError, чтобы
+// usecase перестал писать пользователю; временные возвращаются как есть
+func deliveryError(userID int64, err error) error {
+	if err == nil {
+		return nil
+	}
+	if reason := classify(err); reason != entity.FailureTransient {
+		return &entity.UnreachableError{UserID: userID, Reason: reason, Err: err}
+	}
+	return err
+}
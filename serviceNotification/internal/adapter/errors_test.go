@@ -0,0 +1,53 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"serviceNotification/internal/entity"
+	"testing"
+
+	"gopkg.in/telebot.v4"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{telebot.ErrBlockedByUser, entity.FailureBlocked},
+		{telebot.ErrNotStartedByUser, entity.FailureBlocked},
+		{telebot.ErrUserIsDeactivated, entity.FailureDeactivated},
+		{telebot.ErrChatNotFound, entity.FailureChatNotFound},
+		{fmt.Errorf("handoff: %w", telebot.ErrBlockedByUser), entity.FailureBlocked},
+		{telebot.Err("Forbidden: bot was blocked by the user"), entity.FailureBlocked},
+		{errors.New("connection refused"), entity.FailureTransient},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.err); got != tt.want {
+			t.Errorf("classify(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestDeliveryError(t *testing.T) {
+	if err := deliveryError(1, nil); err != nil {
+		t.Fatalf("deliveryError(nil) = %v", err)
+	}
+
+	transient := errors.New("timeout")
+	if err := deliveryError(1, transient); err != transient {
+		t.Fatalf("transient error wrapped: %v", err)
+	}
+
+	var unreachable *entity.UnreachableError
+	err := deliveryError(42, telebot.ErrUserIsDeactivated)
+	if !errors.As(err, &unreachable) {
+		t.Fatalf("expected UnreachableError, got %v", err)
+	}
+	if unreachable.UserID != 42 || unreachable.Reason != entity.FailureDeactivated {
+		t.Fatalf("unexpected %+v", unreachable)
+	}
+	if !errors.Is(err, telebot.ErrUserIsDeactivated) {
+		t.Fatal("UnreachableError must unwrap to the telegram error")
+	}
+}
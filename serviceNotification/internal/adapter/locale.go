@@ -0,0 +1,27 @@
+package adapter
+
+import (
+	"log"
+	"serviceNotification/internal/entity"
+	"serviceNotification/internal/i18n"
+)
+
+// recipient - язык и анкета получателя. Если анкета недоступна, уведомление
+// все равно отправляется на языке по умолчанию
+func (bot *TelegramBot) recipient(userID int64) (string, *entity.User) {
+	user, err := bot.uc.GetUserByID(userID)
+	if err != nil || user == nil {
+		log.Printf("Ошибка загрузки анкеты %d: %v", userID, err)
+		return i18n.DefaultLocale, nil
+	}
+	return i18n.Locale(user.LanguageCode), user
+}
+
+// userName - имя из анкеты; если анкета недоступна, нейтральное обращение
+func (bot *TelegramBot) userName(locale string, userID int64) string {
+	user, err := bot.uc.GetUserByID(userID)
+	if err != nil || user == nil || user.Name == "" {
+		return bot.catalog.Text(locale, "someone", nil)
+	}
+	return user.Name
+}
@@ -0,0 +1,183 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"serviceNotification/internal/entity"
+	"serviceNotification/internal/i18n"
+	"sync"
+	"time"
+
+	"gopkg.in/telebot.v4"
+)
+
+// Сколько помнить отправленные уведомления, чтобы их можно было отозвать
+const (
+	// DefaultLikeNoticeTTL - окно отмены свайпа в serviceMatch (UNDO_WINDOW)
+	DefaultLikeNoticeTTL = 5 * time.Minute
+	// DefaultMatchNoticeTTL - бот может удалить свое сообщение только в течение 48 часов
+	DefaultMatchNoticeTTL = 48 * time.Hour
+)
+
+// NoticeStore - отправленные уведомления о парах и лайках, чтобы их можно было
+// отозвать, если пара разорвана или лайк отменен до того, как их увидели.
+// Записи живут ttl, после этого отзывать уже нечего
+type NoticeStore interface {
+	Add(ctx context.Context, key string, msg entity.SentMessage, ttl time.Duration) error
+	Take(ctx context.Context, key string) ([]entity.SentMessage, error)
+}
+
+// matchNoticeKey - уведомления о паре, порядок участников не важен
+func matchNoticeKey(a, b int64) string {
+	if a > b {
+		a, b = b, a
+	}
+	return fmt.Sprintf("match:%d:%d", a, b)
+}
+
+// likeNoticeKey - уведомление о лайке from -> to
+func likeNoticeKey(from, to int64) string {
+	return fmt.Sprintf("like:%d:%d", from, to)
+}
+
+func storedMessage(msg entity.SentMessage) telebot.StoredMessage {
+	return telebot.StoredMessage{MessageID: fmt.Sprint(msg.MessageID), ChatID: msg.ChatID}
+}
+
+// maxNotices - после такого числа записей в памяти устаревшие удаляются
+const maxNotices = 10000
+
+type memoryNotice struct {
+	messages  []entity.SentMessage
+	expiresAt time.Time
+}
+
+// MemoryNotices - NoticeStore в памяти процесса, если Redis недоступен.
+// После перезапуска сервиса отозвать старые уведомления уже нельзя
+type MemoryNotices struct {
+	mu      sync.Mutex
+	notices map[string]*memoryNotice
+	now     func() time.Time
+}
+
+func NewMemoryNotices() *MemoryNotices {
+	return &MemoryNotices{notices: make(map[string]*memoryNotice), now: time.Now}
+}
+
+func (n *MemoryNotices) Add(ctx context.Context, key string, msg entity.SentMessage, ttl time.Duration) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	now := n.now()
+	notice, ok := n.notices[key]
+	if !ok || !notice.expiresAt.After(now) {
+		notice = &memoryNotice{}
+		n.notices[key] = notice
+	}
+	notice.messages = append(notice.messages, msg)
+	notice.expiresAt = now.Add(ttl)
+	n.prune(now)
+	return nil
+}
+
+func (n *MemoryNotices) Take(ctx context.Context, key string) ([]entity.SentMessage, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	notice, ok := n.notices[key]
+	delete(n.notices, key)
+	if !ok || !notice.expiresAt.After(n.now()) {
+		return nil, nil
+	}
+	return notice.messages, nil
+}
+
+func (n *MemoryNotices) prune(now time.Time) {
+	if len(n.notices) <= maxNotices {
+		return
+	}
+	for key, notice := range n.notices {
+		if !notice.expiresAt.After(now) {
+			delete(n.notices, key)
+		}
+	}
+}
+
+// remember - запоминает отправленное уведомление, чтобы его можно было отозвать
+func (bot *TelegramBot) remember(key string, sent *entity.SentMessage, ttl time.Duration) {
+	if sent == nil {
+		return
+	}
+	if err := bot.notices.Add(context.Background(), key, *sent, ttl); err != nil {
+		log.Printf("Не удалось запомнить уведомление %s: %v", key, err)
+	}
+}
+
+// withdraw - удаляет запомненные уведомления по ключу
+func (bot *TelegramBot) withdraw(notifyType, key string) {
+	messages, err := bot.notices.Take(context.Background(), key)
+	if err != nil {
+		log.Printf("Не удалось загрузить уведомления %s: %v", key, err)
+		return
+	}
+	for _, msg := range messages {
+		if err := bot.delete(notifyType, storedMessage(msg)); err != nil {
+			log.Printf("Не удалось отозвать уведомление %s: %v", key, err)
+		}
+	}
+}
+
+// sendMatch - уведомляет получателя о новой паре с FromUserID. Второму
+// участнику usecase отправляет свое уведомление по его настройкам
+func (bot *TelegramBot) sendMatch(msg entity.Message) error {
+	locale, _ := bot.recipient(msg.ToUserID)
+	text := bot.catalog.Text(locale, entity.NotifyMatch, i18n.Args{
+		"Name":   bot.userName(locale, msg.FromUserID),
+		"UserID": msg.FromUserID,
+	})
+	sent, err := bot.handoff(msg.Type, entity.Handoff{
+		UserID:       msg.ToUserID,
+		Kind:         entity.HandoffMatch,
+		TargetID:     msg.FromUserID,
+		Text:         text,
+		ParseMode:    telebot.ModeHTML,
+		LanguageCode: locale,
+	})
+	if err != nil {
+		log.Printf("Ошибка при отправке уведомления о паре: %v", err)
+		return err
+	}
+	bot.remember(matchNoticeKey(msg.FromUserID, msg.ToUserID), sent, bot.matchNoticeTTL)
+	return nil
+}
+
+// withdrawMatch - пара разорвана: удаляем уведомления о ней
+func (bot *TelegramBot) withdrawMatch(msg entity.Message) error {
+	bot.withdraw(msg.Type, matchNoticeKey(msg.FromUserID, msg.ToUserID))
+	return nil
+}
+
+// withdrawLike - лайк отменен: удаляем уведомление о нем
+func (bot *TelegramBot) withdrawLike(msg entity.Message) error {
+	bot.withdraw(msg.Type, likeNoticeKey(msg.FromUserID, msg.ToUserID))
+	return nil
+}
+
+// sendMatchExpiring - напоминает получателю, что пара с FromUserID скоро истечет
+func (bot *TelegramBot) sendMatchExpiring(msg entity.Message) error {
+	// 0 часов - срок неизвестен или меньше получаса, шаблон пишет «скоро»
+	hours := 0
+	if msg.ExpiresAt != nil {
+		hours = int(time.Until(*msg.ExpiresAt).Round(time.Hour).Hours())
+	}
+	locale, _ := bot.recipient(msg.ToUserID)
+	text := bot.catalog.Text(locale, entity.NotifyMatchExpiring, i18n.Args{
+		"Name":    bot.userName(locale, msg.FromUserID),
+		"Hours":   max(hours, 0),
+		"MatchID": msg.MatchID,
+	})
+	if err := bot.send(msg.Type, msg.ToUserID, text); err != nil {
+		log.Printf("Ошибка при отправке напоминания о паре: %v", err)
+		return err
+	}
+	return nil
+}
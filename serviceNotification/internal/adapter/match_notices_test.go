@@ -0,0 +1,57 @@
+package adapter
+
+import (
+	"context"
+	"serviceNotification/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestMemoryNotices(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	newStore := func() *MemoryNotices {
+		store := NewMemoryNotices()
+		store.now = func() time.Time { return now }
+		return store
+	}
+
+	t.Run("take returns and forgets messages", func(t *testing.T) {
+		store := newStore()
+		key := matchNoticeKey(2, 1)
+		store.Add(ctx, key, entity.SentMessage{ChatID: 1, MessageID: 10}, time.Hour)
+		store.Add(ctx, matchNoticeKey(1, 2), entity.SentMessage{ChatID: 2, MessageID: 20}, time.Hour)
+
+		got, _ := store.Take(ctx, key)
+		if len(got) != 2 {
+			t.Fatalf("уведомлений о паре %v, ожидали оба участника", got)
+		}
+		if got, _ := store.Take(ctx, key); len(got) != 0 {
+			t.Fatalf("повторный Take вернул %v", got)
+		}
+	})
+
+	t.Run("expired messages are not withdrawn", func(t *testing.T) {
+		store := newStore()
+		key := likeNoticeKey(1, 2)
+		store.Add(ctx, key, entity.SentMessage{ChatID: 2, MessageID: 10}, time.Minute)
+
+		store.now = func() time.Time { return now.Add(time.Minute) }
+		if got, _ := store.Take(ctx, key); len(got) != 0 {
+			t.Fatalf("после TTL вернулись %v", got)
+		}
+	})
+
+	t.Run("expired entries are pruned", func(t *testing.T) {
+		store := newStore()
+		for i := int64(0); i < maxNotices; i++ {
+			store.Add(ctx, likeNoticeKey(i, 0), entity.SentMessage{ChatID: 0, MessageID: int(i)}, time.Minute)
+		}
+		store.now = func() time.Time { return now.Add(time.Hour) }
+		store.Add(ctx, likeNoticeKey(1, 2), entity.SentMessage{ChatID: 2, MessageID: 1}, time.Minute)
+
+		if len(store.notices) != 1 {
+			t.Fatalf("в памяти осталось %d записей, ожидали 1", len(store.notices))
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package adapter
+
+import (
+	"log"
+	"serviceNotification/internal/entity"
+	"serviceNotification/internal/i18n"
+	"strings"
+)
+
+// sendProfileApproved - анкета прошла модерацию
+func (bot *TelegramBot) sendProfileApproved(msg entity.Message) error {
+	return bot.sendNotice(msg, nil)
+}
+
+// sendProfileRejected - анкета отклонена модератором, причина показывается пользователю
+func (bot *TelegramBot) sendProfileRejected(msg entity.Message) error {
+	return bot.sendNotice(msg, i18n.Args{"Reason": msg.Reason})
+}
+
+// sendInactivityReminder - напоминание пользователю, который давно не заходил
+func (bot *TelegramBot) sendInactivityReminder(msg entity.Message) error {
+	return bot.sendNotice(msg, nil)
+}
+
+// sendUnansweredLikes - напоминание о лайках, которые давно ждут ответа
+func (bot *TelegramBot) sendUnansweredLikes(msg entity.Message) error {
+	return bot.sendNotice(msg, i18n.Args{"Count": msg.Count})
+}
+
+// sendNewInCity - напоминание о новых анкетах в городе тому, кто давно не заходил
+func (bot *TelegramBot) sendNewInCity(msg entity.Message) error {
+	return bot.sendNotice(msg, i18n.Args{"Count": msg.Count, "City": msg.City})
+}
+
+// sendNotice - уведомление без кнопок на языке получателя
+func (bot *TelegramBot) sendNotice(msg entity.Message, args i18n.Args) error {
+	locale, _ := bot.recipient(msg.ToUserID)
+	text := bot.catalog.Text(locale, msg.Type, args)
+	if err := bot.send(msg.Type, msg.ToUserID, text); err != nil {
+		log.Printf("Ошибка при отправке уведомления %s: %v", msg.Type, err)
+		return err
+	}
+	return nil
+}
+
+// digestOrder - порядок строк в сводке отложенных уведомлений
+var digestOrder = []string{
+	entity.NotifyMatch,
+	entity.NotifySuperLike,
+	entity.NotifyLike,
+	entity.NotifyMatchExpiring,
+	entity.NotifyInactivity,
+}
+
+// SendDigest - сводка вроде «У вас 5 новых лайков», по строке на тип
+func (bot *TelegramBot) SendDigest(userID int64, counts map[string]int) error {
+	locale, _ := bot.recipient(userID)
+	var lines []string
+	for _, notifyType := range digestOrder {
+		if n := counts[notifyType]; n > 0 {
+			lines = append(lines, bot.catalog.Text(locale, "digest."+notifyType, i18n.Args{"Count": n}))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	lines = append(lines, bot.catalog.Text(locale, "digest.footer", nil))
+	if err := bot.send(notifyDigest, userID, strings.Join(lines, "\n")); err != nil {
+		log.Printf("Ошибка при отправке сводки уведомлений: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package adapter
+
+import (
+	"context"
+	"serviceNotification/internal/entity"
+	"serviceNotification/internal/sendqueue"
+
+	"gopkg.in/telebot.v4"
+)
+
+// priority - полоса очереди отправки для типа уведомления: пары и суперлайки
+// уходят раньше обычных лайков, а сводки и напоминания пропускают всех вперед
+func priority(notifyType string) sendqueue.Priority {
+	switch notifyType {
+	case entity.NotifyMatch, entity.NotifyMatchEnded, entity.NotifySuperLike:
+		return sendqueue.PriorityHigh
+	case entity.NotifyInactivity, entity.NotifyUnansweredLikes, entity.NotifyNewInCity, notifyDigest:
+		return sendqueue.PriorityLow
+	default:
+		return sendqueue.PriorityNormal
+	}
+}
+
+// notifyDigest - полоса сводки отложенных уведомлений, у нее нет типа Notify*
+const notifyDigest = "digest"
+
+// send - текстовое сообщение через очередь отправки
+func (bot *TelegramBot) send(notifyType string, chatID int64, text string) error {
+	err := bot.queue.Send(context.Background(), chatID, priority(notifyType), func() error {
+		_, err := bot.b.Send(&telebot.User{ID: chatID}, text)
+		return err
+	})
+	return deliveryError(chatID, err)
+}
+
+// handoff - уведомление с кнопками через serviceBot. Сообщение отправляет бот,
+// но лимиты у токена общие, поэтому запрос тоже идет через очередь
+func (bot *TelegramBot) handoff(notifyType string, h entity.Handoff) (*entity.SentMessage, error) {
+	var sent *entity.SentMessage
+	err := bot.queue.Send(context.Background(), h.UserID, priority(notifyType), func() error {
+		var err error
+		sent, err = bot.bc.SendHandoff(h)
+		return err
+	})
+	return sent, deliveryError(h.UserID, err)
+}
+
+// delete - отзыв отправленного уведомления через очередь отправки
+func (bot *TelegramBot) delete(notifyType string, stored telebot.StoredMessage) error {
+	err := bot.queue.Send(context.Background(), stored.ChatID, priority(notifyType), func() error {
+		return bot.b.Delete(stored)
+	})
+	return deliveryError(stored.ChatID, err)
+}
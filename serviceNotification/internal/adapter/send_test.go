@@ -0,0 +1,60 @@
+package adapter
+
+import (
+	"context"
+	"serviceNotification/internal/entity"
+	"serviceNotification/internal/sendqueue"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPriority_SuperLikeBeforeLike(t *testing.T) {
+	metrics := sendqueue.NewMetrics()
+	q := sendqueue.New(sendqueue.Config{}, metrics)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+
+	// Первый запрос держит воркер, пока в очередь не встанут лайк и суперлайк
+	release := make(chan struct{})
+	go q.Send(ctx, 1, priority(entity.NotifyMatch), func() error {
+		<-release
+		return nil
+	})
+	waitQueued(t, metrics, 1)
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	for i, notifyType := range []string{entity.NotifyLike, entity.NotifySuperLike} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.Send(ctx, int64(i+2), priority(notifyType), func() error {
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, notifyType)
+				return nil
+			})
+		}()
+		waitQueued(t, metrics, int64(i+2))
+	}
+	close(release)
+	wg.Wait()
+
+	if len(order) != 2 || order[0] != entity.NotifySuperLike {
+		t.Fatalf("порядок отправки %v, суперлайк должен уйти раньше лайка", order)
+	}
+}
+
+func waitQueued(t *testing.T, metrics *sendqueue.Metrics, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for metrics.Get("queued") < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("в очередь встало %d запросов, ожидали %d", metrics.Get("queued"), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
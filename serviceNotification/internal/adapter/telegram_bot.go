@@ -1,12 +1,12 @@
 package adapter
 
 import (
-	"bytes"
-	"errors"
 	"fmt"
 	"log"
 	"serviceNotification/internal/entity"
-	"serviceNotification/internal/utilites"
+	"serviceNotification/internal/i18n"
+	"serviceNotification/internal/sendqueue"
+	"time"
 
 	"gopkg.in/telebot.v4"
 )
@@ -15,27 +15,31 @@ type UserClient interface {
 	GetUserByID(userID int64) (*entity.User, error)
 }
 
-type TelegramBot struct {
-	b           *telebot.Bot
-	uc          UserClient
-	likeTracker map[int64]int64 //Хранит, кто кого лайкнул (userID -> likerID)
-}
-
-type Bothandle struct {
-	t TelegramBot
+// BotClient - внутренний API serviceBot. Уведомления с кнопками отправляет
+// он, потому что нажатия приходят только в serviceBot
+type BotClient interface {
+	SendHandoff(h entity.Handoff) (*entity.SentMessage, error)
 }
 
-func NewBothandle(t TelegramBot) *Bothandle {
-	return &Bothandle{t: t}
+// TelegramBot только отправляет сообщения: обновления от Telegram получает
+// serviceBot, второй получатель на том же токене забирал бы часть нажатий
+type TelegramBot struct {
+	b  *telebot.Bot
+	uc UserClient
+	bc BotClient
+	// notices - отправленные уведомления, которые можно отозвать, и сколько их помнить
+	notices        NoticeStore
+	likeNoticeTTL  time.Duration
+	matchNoticeTTL time.Duration
+	catalog        *i18n.Catalog
+	// queue - все запросы к Telegram идут через нее, чтобы не упираться в лимиты
+	queue *sendqueue.Queue
+	// handlers - обработчик для каждого типа уведомления
+	handlers map[string]func(entity.Message) error
 }
 
-var likes int
-
-func NewTelegramBot(token string, userClient UserClient) (*TelegramBot, error) {
-	botAPI, err := telebot.NewBot(telebot.Settings{
-		Token:  token,
-		Poller: &telebot.LongPoller{Timeout: 10},
-	})
+func NewTelegramBot(token string, userClient UserClient, botClient BotClient, queue *sendqueue.Queue) (*TelegramBot, error) {
+	botAPI, err := telebot.NewBot(telebot.Settings{Token: token})
 	if err != nil {
 		log.Printf("Error creating Telegram bot: %v", err)
 		return nil, err
@@ -43,107 +47,86 @@ func NewTelegramBot(token string, userClient UserClient) (*TelegramBot, error) {
 	log.Printf("Authorized on account %s", botAPI.Me.FirstName)
 
 	bot := &TelegramBot{
-		b:           botAPI,
-		uc:          userClient,
-		likeTracker: make(map[int64]int64),
+		b:              botAPI,
+		uc:             userClient,
+		bc:             botClient,
+		notices:        NewMemoryNotices(),
+		likeNoticeTTL:  DefaultLikeNoticeTTL,
+		matchNoticeTTL: DefaultMatchNoticeTTL,
+		catalog:        i18n.New(),
+		queue:          queue,
+	}
+	bot.handlers = map[string]func(entity.Message) error{
+		entity.NotifyLike:            bot.sendLike,
+		entity.NotifySuperLike:       bot.sendLike,
+		entity.NotifyLikeUndone:      bot.withdrawLike,
+		entity.NotifyMatch:           bot.sendMatch,
+		entity.NotifyMatchEnded:      bot.withdrawMatch,
+		entity.NotifyMatchExpiring:   bot.sendMatchExpiring,
+		entity.NotifyProfileApproved: bot.sendProfileApproved,
+		entity.NotifyProfileRejected: bot.sendProfileRejected,
+		entity.NotifyInactivity:      bot.sendInactivityReminder,
+		entity.NotifyUnansweredLikes: bot.sendUnansweredLikes,
+		entity.NotifyNewInCity:       bot.sendNewInCity,
 	}
 
 	return bot, nil
 }
 
-func (bot *TelegramBot) SendMessage(msg entity.Message) error {
-	if msg.FromUserID == 0 || msg.ToUserID == 0 || msg.Text == "" {
-		return errors.New("Не верные данные")
+// SetNotices - хранилище отправленных уведомлений вместо памяти процесса и
+// сроки, в течение которых их можно отозвать; 0 - срок по умолчанию
+func (bot *TelegramBot) SetNotices(store NoticeStore, likeTTL, matchTTL time.Duration) {
+	bot.notices = store
+	if likeTTL > 0 {
+		bot.likeNoticeTTL = likeTTL
 	}
-	ToRecipient := &telebot.User{ID: msg.ToUserID}
-
-	bot.likeTracker[msg.ToUserID] = msg.FromUserID
-
-	profileKeys := &telebot.ReplyMarkup{ResizeKeyboard: true}
-	btnShowProfile := profileKeys.Text("Показать анкету")
-	profileKeys.Reply(profileKeys.Row(btnShowProfile))
-
-	switch msg.Text {
-	case "like":
-		_, err := bot.b.Send(ToRecipient, "Вас лайкнули! Нажмите смотреть анкету?", profileKeys)
-		if err != nil {
-			log.Printf("Ошибка при отправке сообщения: %v", err)
-			return err
+	if matchTTL > 0 {
+		bot.matchNoticeTTL = matchTTL
+	}
+}
 
-		}
+// SendMessage - отправляет уведомление обработчиком его типа
+func (bot *TelegramBot) SendMessage(msg entity.Message) error {
+	handle, ok := bot.handlers[msg.Type]
+	if !ok {
+		return fmt.Errorf("нет обработчика уведомлений %q", msg.Type)
 	}
+	return handle(msg)
+}
 
+// sendLike - уведомление о лайке или суперлайке; кнопку «Показать анкету» обрабатывает serviceBot
+func (bot *TelegramBot) sendLike(msg entity.Message) error {
+	locale, user := bot.recipient(msg.ToUserID)
+	text := bot.catalog.Text(locale, msg.Type, i18n.Args{
+		"Question": promptQuestion(user, msg.PromptID),
+		"Photo":    msg.Target == entity.TargetPhoto,
+		"Comment":  msg.Comment,
+	})
+	sent, err := bot.handoff(msg.Type, entity.Handoff{
+		UserID:       msg.ToUserID,
+		Kind:         entity.HandoffLiker,
+		TargetID:     msg.FromUserID,
+		Text:         text,
+		LanguageCode: locale,
+	})
+	if err != nil {
+		log.Printf("Ошибка при отправке сообщения: %v", err)
+		return err
+	}
+	bot.remember(likeNoticeKey(msg.FromUserID, msg.ToUserID), sent, bot.likeNoticeTTL)
 	return nil
 }
 
-func (bot *Bothandle) BotStart() {
-	bot.t.b.Handle(telebot.OnText, func(ctx telebot.Context) error {
-		if likes == 1 {
-			if ctx.Text() == "❤" {
-				chat, err := bot.t.b.ChatByID(bot.t.likeTracker[ctx.Sender().ID])
-				if err != nil {
-					return ctx.Send("Произошла какая-то ошибка")
-				}
-				msg := fmt.Sprintf("Взаимный лайк начинай общаться: [Начать общение!] (tg://user?id=%o)", ctx.Sender().ID)
-				profileKeys := [][]telebot.ReplyButton{
-					{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-				}
-				bot.t.b.Send(chat, msg, &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-
-			}
-			if ctx.Text() == "👎" {
-				profileKeys := [][]telebot.ReplyButton{
-					{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-				}
-				return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-			}
-		}
-		if ctx.Text() == "Показать анкету" {
-			userID := ctx.Sender().ID
-			likerID, exists := bot.t.likeTracker[userID]
-			if !exists {
-				profileKeys := [][]telebot.ReplyButton{
-					{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-				}
-				ctx.Send("Нет анкеты для показа")
-				return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-			}
-
-			user, err := bot.t.uc.GetUserByID(likerID)
-			if err != nil {
-				ctx.Send("Ошибка загрузки анкеты.")
-				profileKeys := [][]telebot.ReplyButton{
-					{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-				}
-				ctx.Send("Нет анкеты для показа")
-				return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-			}
-
-			imageBytes, err := utilites.DownloadImageAsBytes(user.Photo)
-			if err != nil {
-				log.Println(err)
-				ctx.Send("Ошибка загрузки фотографии из бд")
-				profileKeys := [][]telebot.ReplyButton{
-					{{Text: "1"}, {Text: "2"}, {Text: "3"}},
-				}
-				ctx.Send("Нет анкеты для показа")
-				return ctx.Send("1. Смотреть анкеты 🚀. \n2. Моя анкета 📱.\n3. Изменить анкету.", &telebot.ReplyMarkup{ReplyKeyboard: profileKeys, ResizeKeyboard: true})
-			}
-
-			Answer := &telebot.Photo{
-				File:    telebot.FromReader(bytes.NewReader(imageBytes)),
-				Caption: fmt.Sprintf("%s, %d, %s - %s", user.Name, user.Age, user.City, user.Description),
-			}
-
-			likes = 1
-			key := [][]telebot.ReplyButton{
-				{{Text: "❤"}, {Text: "👎"}},
-			}
-			return ctx.Send(Answer, &telebot.ReplyMarkup{ReplyKeyboard: key, ResizeKeyboard: true})
+// promptQuestion - текст вопроса-карточки получателя, на который отреагировали.
+// Если вопрос не найден, возвращает пустую строку и лайк показывается как обычный
+func promptQuestion(user *entity.User, promptID *int) string {
+	if promptID == nil || user == nil {
+		return ""
+	}
+	for _, p := range user.Prompts {
+		if p.PromptID == *promptID {
+			return p.Question
 		}
-		return nil
-	})
-
-	log.Println("Бот запущен...")
-	bot.t.b.Start()
+	}
+	return ""
 }
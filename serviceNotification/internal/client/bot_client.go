@@ -0,0 +1,87 @@
+package clientsUser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"serviceNotification/internal/entity"
+	"strconv"
+	"time"
+
+	"gopkg.in/telebot.v4"
+)
+
+// RateLimitedError - serviceBot получил от Telegram 429 и просит подождать
+type RateLimitedError struct {
+	After time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %v", e.After)
+}
+
+// RetryAfter - пауза, которую попросил Telegram; ее соблюдает очередь отправки
+func (e *RateLimitedError) RetryAfter() time.Duration {
+	return e.After
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// HTTPBotClient - клиент внутреннего API serviceBot
+type HTTPBotClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPBotClient(baseURL string) *HTTPBotClient {
+	return &HTTPBotClient{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SendHandoff - просит serviceBot отправить уведомление с кнопками
+func (c *HTTPBotClient) SendHandoff(h entity.Handoff) (*entity.SentMessage, error) {
+	body, err := json.Marshal(h)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode handoff: %w", err)
+	}
+
+	resp, err := c.client.Post(c.baseURL+"/internal/handoffs", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, &RateLimitedError{After: retryAfter(resp)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		// Ошибку Telegram восстанавливаем по описанию, чтобы ее можно было
+		// классифицировать так же, как ошибки собственных запросов
+		var apiErr struct {
+			Description string `json:"description"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Description != "" {
+			if tgErr := telebot.Err(apiErr.Description); tgErr != nil {
+				return nil, fmt.Errorf("serviceBot: %w", tgErr)
+			}
+		}
+		return nil, fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var sent entity.SentMessage
+	if err := json.NewDecoder(resp.Body).Decode(&sent); err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return &sent, nil
+}
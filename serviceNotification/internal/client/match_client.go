@@ -0,0 +1,60 @@
+package clientsUser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"serviceNotification/internal/entity"
+	"strconv"
+	"time"
+)
+
+// HTTPMatchClient - клиент serviceMatch для напоминаний планировщика
+type HTTPMatchClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPMatchClient(baseURL string) *HTTPMatchClient {
+	return &HTTPMatchClient{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// UnansweredLikes - получатели лайков, оставленных без ответа дольше olderThan
+func (c *HTTPMatchClient) UnansweredLikes(olderThan time.Duration, limit, offset int) ([]entity.PendingLikes, bool, error) {
+	query := url.Values{}
+	query.Set("older_than", olderThan.String())
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+
+	var page struct {
+		Items   []entity.PendingLikes `json:"items"`
+		HasMore bool                  `json:"has_more"`
+	}
+	if err := getJSON(c.client, c.baseURL+"/likes/unanswered?"+query.Encode(), &page); err != nil {
+		return nil, false, err
+	}
+	return page.Items, page.HasMore, nil
+}
+
+// getJSON - GET с разбором JSON-ответа; любой статус, кроме 200, - ошибка
+func getJSON(client *http.Client, url string, out any) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return nil
+}
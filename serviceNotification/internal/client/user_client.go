@@ -6,7 +6,10 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"serviceNotification/internal/entity"
+	"strconv"
+	"time"
 )
 
 type HTTPUserServiseClient struct {
@@ -56,3 +59,44 @@ func (c *HTTPUserServiseClient) GetUserByID(userID int64) (*entity.User, error)
 
 	return &user, nil
 }
+
+// GetNotificationSettings - настройки уведомлений пользователя; для тех, кто
+// их не менял, serviceUser возвращает настройки по умолчанию
+func (c *HTTPUserServiseClient) GetNotificationSettings(userID int64) (*entity.NotificationSettings, error) {
+	url := fmt.Sprintf("%s/users/%d/notification-settings", c.baseURL, userID)
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var settings entity.NotificationSettings
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return &settings, nil
+}
+
+// InactiveUsers - пользователи, не заходившие дольше inactiveFor, у которых
+// в городе появились новые анкеты
+func (c *HTTPUserServiseClient) InactiveUsers(inactiveFor time.Duration, limit, offset int) ([]entity.InactiveUser, bool, error) {
+	query := url.Values{}
+	query.Set("inactive_for", inactiveFor.String())
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+
+	var page struct {
+		Items   []entity.InactiveUser `json:"items"`
+		HasMore bool                  `json:"has_more"`
+	}
+	if err := getJSON(c.client, c.baseURL+"/users/inactive?"+query.Encode(), &page); err != nil {
+		return nil, false, err
+	}
+	return page.Items, page.HasMore, nil
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -10,6 +12,41 @@ type Config struct {
 	TelegramToken  string
 	GroupId        string
 	UserURL        string
+	BotURL         string
+	// Отложенные уведомления: тихие часы и сводки хранятся в Redis
+	RedisAddr string
+	// DigestInterval - длина окна сводки, HeldPollInterval - как часто проверять отложенные
+	DigestInterval   time.Duration
+	HeldPollInterval time.Duration
+	// LikeNoticeTTL и MatchNoticeTTL - сколько помнить уведомления о лайке и паре,
+	// чтобы отозвать их после отмены лайка или разрыва пары
+	LikeNoticeTTL  time.Duration
+	MatchNoticeTTL time.Duration
+	// Очередь отправки: общий лимит бота в секунду и интервал между сообщениями в один чат
+	SendRate    int
+	SendPerChat time.Duration
+	// KafkaUserTopic - события о пользователях для serviceUser, например user.unreachable
+	KafkaUserTopic string
+	// DatabaseURL - Postgres журнала доставки; пустой - уведомления не сохраняются
+	DatabaseURL string
+	// HTTPAddr - адрес HTTP API входящих
+	HTTPAddr string
+	// MatchURL - serviceMatch, источник неотвеченных лайков для напоминаний
+	MatchURL string
+	// AdminToken - X-Admin-Token для /admin; пустой - админка закрыта
+	AdminToken string
+	// Планировщик напоминаний: часовой пояс расписаний, предельная длительность задачи,
+	// расписания cron, пороги и паузы между напоминаниями одному пользователю
+	SchedulerTimeZone       string
+	JobTimeout              time.Duration
+	UnansweredLikesSchedule string
+	UnansweredLikesAfter    time.Duration
+	UnansweredLikesCooldown time.Duration
+	NewInCitySchedule       string
+	InactiveFor             time.Duration
+	NewInCityCooldown       time.Duration
+	// MetricsAddr - адрес /debug/vars с метриками очереди; пустой - не публиковать
+	MetricsAddr string
 }
 
 func NewConfig() *Config {
@@ -19,6 +56,34 @@ func NewConfig() *Config {
 		TelegramToken:  getEnv("TELEGRAM_BOT_TOKEN", ""),
 		GroupId:        getEnv("GROUP_ID", ""),
 		UserURL:        getEnv("USER_SERVICE", ""),
+		BotURL:         getEnv("BOT_SERVICE", ""),
+
+		RedisAddr:        getEnv("REDIS_ADDR", "localhost:6379"),
+		DigestInterval:   getEnvDuration("DIGEST_INTERVAL", 3*time.Hour),
+		HeldPollInterval: getEnvDuration("HELD_POLL_INTERVAL", time.Minute),
+		LikeNoticeTTL:    getEnvDuration("LIKE_NOTICE_TTL", 5*time.Minute),
+		MatchNoticeTTL:   getEnvDuration("MATCH_NOTICE_TTL", 48*time.Hour),
+
+		SendRate:    getEnvInt("SEND_RATE", 30),
+		SendPerChat: getEnvDuration("SEND_PER_CHAT", time.Second),
+		MetricsAddr: getEnv("METRICS_ADDR", ":8084"),
+
+		KafkaUserTopic: getEnv("KAFKA_USER_TOPIC", "users-topic"),
+
+		DatabaseURL: getEnv("DATABASE_URL", ""),
+		HTTPAddr:    getEnv("HTTP_ADDR", ":8085"),
+
+		MatchURL:   getEnv("MATCH_SERVICE", ""),
+		AdminToken: getEnv("ADMIN_TOKEN", ""),
+
+		SchedulerTimeZone:       getEnv("SCHEDULER_TIMEZONE", "Europe/Moscow"),
+		JobTimeout:              getEnvDuration("JOB_TIMEOUT", 30*time.Minute),
+		UnansweredLikesSchedule: getEnv("UNANSWERED_LIKES_SCHEDULE", "0 19 * * *"),
+		UnansweredLikesAfter:    getEnvDuration("UNANSWERED_LIKES_AFTER", 24*time.Hour),
+		UnansweredLikesCooldown: getEnvDuration("UNANSWERED_LIKES_COOLDOWN", 72*time.Hour),
+		NewInCitySchedule:       getEnv("NEW_IN_CITY_SCHEDULE", "0 12 * * 6"),
+		InactiveFor:             getEnvDuration("INACTIVE_FOR", 7*24*time.Hour),
+		NewInCityCooldown:       getEnvDuration("NEW_IN_CITY_COOLDOWN", 7*24*time.Hour),
 	}
 }
 
@@ -28,3 +93,21 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -2,21 +2,42 @@ package delivery
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"serviceNotification/internal/entity"
 	"serviceNotification/internal/usecase"
+	"time"
 
 	"github.com/segmentio/kafka-go"
 )
 
+// Повторы отправки уведомления: ошибка Telegram или serviceBot часто временная
+const (
+	maxAttempts  = 5
+	retryBackoff = time.Second
+	maxBackoff   = 30 * time.Second
+)
+
+// errUnrecognized - сообщение нельзя разобрать, повторять его бесполезно
+var errUnrecognized = errors.New("unrecognized message")
+
+// Notifier - прием уведомлений: Accept делит событие по получателям,
+// Deliver доставляет одному получателю и повторяется при временной ошибке
+type Notifier interface {
+	Accept(msg entity.Message) ([]entity.Message, error)
+	Deliver(msg entity.Message) error
+}
+
 type KafkaConsumer struct {
-	usecase *usecase.BotUsecase
-	Reader  *kafka.Reader
+	notifier Notifier
+	Reader   *kafka.Reader
+	// backoff - пауза перед первым повтором, дальше она удваивается
+	backoff time.Duration
 }
 
-func NewKafkaConsumer(brokers []string, topic string, groupId string, usecase *usecase.BotUsecase) (*KafkaConsumer, error) {
+func NewKafkaConsumer(brokers []string, topic string, groupId string, notifier Notifier) (*KafkaConsumer, error) {
 	if len(brokers) == 0 || brokers[0] == "" || topic == "" || groupId == "" {
 		return nil, errors.New("не указаны параметры подключения к Kafka")
 	}
@@ -30,8 +51,9 @@ func NewKafkaConsumer(brokers []string, topic string, groupId string, usecase *u
 	})
 
 	return &KafkaConsumer{
-		usecase: usecase,
-		Reader:  reader,
+		notifier: notifier,
+		Reader:   reader,
+		backoff:  retryBackoff,
 	}, nil
 }
 
@@ -50,11 +72,12 @@ func (c *KafkaConsumer) Start(ctx context.Context) {
 
 			log.Printf("Received message: %s", string(msg.Value))
 
-			// Парсим сообщение
-			err = c.processMessage(string(msg.Value))
-			if err != nil {
+			// Парсим сообщение; ошибки отправки повторяем, неверные сообщения пропускаем
+			if err := c.processWithRetry(ctx, msg); err != nil {
 				log.Printf("Error processing message: %v", err)
-				continue // Если ошибка обработки, не подтверждаем сообщение
+				if ctx.Err() != nil {
+					return
+				}
 			}
 
 			// Подтверждаем, что сообщение обработано
@@ -66,33 +89,131 @@ func (c *KafkaConsumer) Start(ctx context.Context) {
 	}
 }
 
-func (c *KafkaConsumer) processMessage(message string) error {
+// processWithRetry - разбирает сообщение и доставляет его каждому получателю.
+// Неверные сообщения пропускаются, временные ошибки повторяются по получателю
+func (c *KafkaConsumer) processWithRetry(ctx context.Context, message kafka.Message) error {
+	notification, err := c.processMessage(string(message.Value))
+	if err != nil {
+		return fmt.Errorf("skipping message: %w", err)
+	}
+	notification.EventKey = eventKey(message)
+	messages, err := c.notifier.Accept(notification)
+	if err != nil {
+		return fmt.Errorf("skipping message: %w", err)
+	}
+	return c.deliverAll(ctx, messages)
+}
+
+// eventKey - адрес сообщения в Kafka; одинаков для всех повторов события
+func eventKey(message kafka.Message) string {
+	return fmt.Sprintf("%s/%d/%d", message.Topic, message.Partition, message.Offset)
+}
+
+// deliverAll - доставка всем получателям; ошибка одного не мешает остальным
+func (c *KafkaConsumer) deliverAll(ctx context.Context, messages []entity.Message) error {
+	var errs []error
+	for _, msg := range messages {
+		if err := c.deliverWithRetry(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			errs = append(errs, fmt.Errorf("failed to send %s to %d: %w", msg.Type, msg.ToUserID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliverWithRetry - доставляет уведомление одному получателю, повторяя временные
+// ошибки с растущей паузой. После maxAttempts неудач уведомление пропускается,
+// чтобы не блокировать партицию
+func (c *KafkaConsumer) deliverWithRetry(ctx context.Context, msg entity.Message) error {
+	backoff := c.backoff
+	for attempt := 1; ; attempt++ {
+		err := c.notifier.Deliver(msg)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, usecase.ErrInvalidNotification) {
+			return err
+		}
+		if attempt == maxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		log.Printf("Attempt %d failed, retrying in %s: %v", attempt, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// processMessage - уведомление из сообщения Kafka
+func (c *KafkaConsumer) processMessage(message string) (entity.Message, error) {
 	if len(message) == 0 {
-		return fmt.Errorf("error: received empty message")
+		return entity.Message{}, fmt.Errorf("%w: received empty message", errUnrecognized)
+	}
+
+	// Новый формат событий - JSON
+	if message[0] == '{' {
+		var event entity.Event
+		if err := json.Unmarshal([]byte(message), &event); err != nil {
+			return entity.Message{}, fmt.Errorf("%w: error parsing event: %v", errUnrecognized, err)
+		}
+		return c.processEvent(event)
 	}
 
-	// Обрабатываем лайк-сообщение
+	// Обрабатываем лайк-сообщение в старом формате like_<from>_<to>
 	if len(message) >= 5 && message[:5] == "like_" {
 		var fromUserID, toUserID int64
 		n, err := fmt.Sscanf(message, "like_%d_%d", &fromUserID, &toUserID)
 		if err != nil || n != 2 {
-			return fmt.Errorf("error parsing like message: %v", err)
+			return entity.Message{}, fmt.Errorf("%w: error parsing like message: %v", errUnrecognized, err)
 		}
 
-		text := "like"
-		log.Printf("Processing like: from %d to %d", fromUserID, toUserID)
-
-		err = c.usecase.SendMessage(entity.Message{
+		return entity.Message{
+			Type:       entity.NotifyLike,
 			FromUserID: fromUserID,
 			ToUserID:   toUserID,
-			Text:       text,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to send like message: %v", err)
-		}
+		}, nil
+	}
+
+	return entity.Message{}, fmt.Errorf("%w: unrecognized message type: %s", errUnrecognized, message)
+}
 
-		return nil
+// eventNotifications - тип уведомления для каждого типа события
+var eventNotifications = map[string]string{
+	entity.EventLike:               entity.NotifyLike,
+	entity.EventLikeUndone:         entity.NotifyLikeUndone,
+	entity.EventMatch:              entity.NotifyMatch,
+	entity.EventMatchEnded:         entity.NotifyMatchEnded,
+	entity.EventUnmatch:            entity.NotifyMatchEnded,
+	entity.EventMatchExpiring:      entity.NotifyMatchExpiring,
+	entity.EventProfileApproved:    entity.NotifyProfileApproved,
+	entity.EventProfileRejected:    entity.NotifyProfileRejected,
+	entity.EventInactivityReminder: entity.NotifyInactivity,
+}
+
+func (c *KafkaConsumer) processEvent(event entity.Event) (entity.Message, error) {
+	notification, ok := eventNotifications[event.Type]
+	if !ok {
+		return entity.Message{}, fmt.Errorf("%w: unrecognized event type: %s", errUnrecognized, event.Type)
+	}
+	if event.Type == entity.EventLike && event.Super {
+		notification = entity.NotifySuperLike
 	}
 
-	return fmt.Errorf("unrecognized message type: %s", message)
+	log.Printf("Processing %s: %d and %d", event.Type, event.FromUserID, event.ToUserID)
+	return entity.Message{
+		Type:       notification,
+		FromUserID: event.FromUserID,
+		ToUserID:   event.ToUserID,
+		PromptID:   event.PromptID,
+		Target:     event.Target,
+		Comment:    event.Comment,
+		MatchID:    event.MatchID,
+		ExpiresAt:  event.ExpiresAt,
+		Reason:     event.Reason,
+	}, nil
 }
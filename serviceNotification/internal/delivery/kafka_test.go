@@ -0,0 +1,203 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"serviceNotification/internal/entity"
+	"serviceNotification/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// stubNotifier - Accept отдает заданных получателей (или само уведомление),
+// Deliver отвечает ошибками из failures по очереди для каждого получателя
+type stubNotifier struct {
+	acceptErr  error
+	recipients func(entity.Message) []entity.Message
+	failures   map[int64][]error
+
+	accepted  []entity.Message
+	delivered map[int64]int
+	attempts  map[int64]int
+}
+
+func newStubNotifier() *stubNotifier {
+	return &stubNotifier{
+		failures:  make(map[int64][]error),
+		delivered: make(map[int64]int),
+		attempts:  make(map[int64]int),
+	}
+}
+
+func (n *stubNotifier) Accept(msg entity.Message) ([]entity.Message, error) {
+	n.accepted = append(n.accepted, msg)
+	if n.acceptErr != nil {
+		return nil, n.acceptErr
+	}
+	if n.recipients != nil {
+		return n.recipients(msg), nil
+	}
+	return []entity.Message{msg}, nil
+}
+
+func (n *stubNotifier) Deliver(msg entity.Message) error {
+	n.attempts[msg.ToUserID]++
+	if queue := n.failures[msg.ToUserID]; len(queue) > 0 {
+		n.failures[msg.ToUserID] = queue[1:]
+		return queue[0]
+	}
+	n.delivered[msg.ToUserID]++
+	return nil
+}
+
+func newTestConsumer(n *stubNotifier) *KafkaConsumer {
+	return &KafkaConsumer{notifier: n, backoff: time.Millisecond}
+}
+
+func kafkaMessage(value string) kafka.Message {
+	return kafka.Message{Topic: "likes", Partition: 1, Offset: 7, Value: []byte(value)}
+}
+
+func TestProcessWithRetry_RetriesTransientErrors(t *testing.T) {
+	n := newStubNotifier()
+	timeout := errors.New("timeout")
+	n.failures[2] = []error{timeout, timeout}
+	c := newTestConsumer(n)
+
+	if err := c.processWithRetry(context.Background(), kafkaMessage("like_1_2")); err != nil {
+		t.Fatal(err)
+	}
+	if n.attempts[2] != 3 || n.delivered[2] != 1 {
+		t.Fatalf("attempts = %d, delivered = %d, want 3 and 1", n.attempts[2], n.delivered[2])
+	}
+}
+
+func TestProcessWithRetry_GivesUp(t *testing.T) {
+	n := newStubNotifier()
+	for i := 0; i < maxAttempts+1; i++ {
+		n.failures[2] = append(n.failures[2], errors.New("timeout"))
+	}
+	c := newTestConsumer(n)
+
+	err := c.processWithRetry(context.Background(), kafkaMessage("like_1_2"))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if n.attempts[2] != maxAttempts {
+		t.Fatalf("attempts = %d, want %d", n.attempts[2], maxAttempts)
+	}
+}
+
+func TestProcessWithRetry_StopsOnCancel(t *testing.T) {
+	n := newStubNotifier()
+	n.failures[2] = []error{errors.New("timeout"), errors.New("timeout")}
+	c := &KafkaConsumer{notifier: n, backoff: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := c.processWithRetry(ctx, kafkaMessage("like_1_2")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if n.attempts[2] != 1 {
+		t.Fatalf("attempts = %d, want 1", n.attempts[2])
+	}
+}
+
+func TestProcessWithRetry_NoRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		acceptErr error
+		deliver   error
+		wantErr   error
+		attempts  int
+	}{
+		{name: "empty message", value: "", wantErr: errUnrecognized},
+		{name: "broken json", value: "{", wantErr: errUnrecognized},
+		{name: "unknown event", value: `{"type":"unknown","to_user_id":2}`, wantErr: errUnrecognized},
+		{name: "unknown format", value: "hello", wantErr: errUnrecognized},
+		{name: "invalid notification", value: "like_1_2", acceptErr: usecase.ErrInvalidNotification, wantErr: usecase.ErrInvalidNotification},
+		{name: "invalid on delivery", value: "like_1_2", deliver: usecase.ErrInvalidNotification, wantErr: usecase.ErrInvalidNotification, attempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newStubNotifier()
+			n.acceptErr = tt.acceptErr
+			if tt.deliver != nil {
+				n.failures[2] = []error{tt.deliver, tt.deliver}
+			}
+			c := newTestConsumer(n)
+
+			err := c.processWithRetry(context.Background(), kafkaMessage(tt.value))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if n.attempts[2] != tt.attempts {
+				t.Fatalf("attempts = %d, want %d", n.attempts[2], tt.attempts)
+			}
+		})
+	}
+}
+
+func TestProcessWithRetry_RetriesOnlyFailedRecipient(t *testing.T) {
+	n := newStubNotifier()
+	n.recipients = func(msg entity.Message) []entity.Message {
+		swapped := msg
+		swapped.FromUserID, swapped.ToUserID = msg.ToUserID, msg.FromUserID
+		return []entity.Message{msg, swapped}
+	}
+	n.failures[1] = []error{errors.New("timeout")}
+	c := newTestConsumer(n)
+
+	if err := c.processWithRetry(context.Background(), kafkaMessage(`{"type":"match","from_user_id":1,"to_user_id":2}`)); err != nil {
+		t.Fatal(err)
+	}
+	if n.delivered[2] != 1 || n.attempts[2] != 1 {
+		t.Fatalf("first recipient: attempts = %d, delivered = %d, want 1 and 1", n.attempts[2], n.delivered[2])
+	}
+	if n.delivered[1] != 1 || n.attempts[1] != 2 {
+		t.Fatalf("second recipient: attempts = %d, delivered = %d, want 2 and 1", n.attempts[1], n.delivered[1])
+	}
+}
+
+func TestProcessWithRetry_Dispatch(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "like_1_2", want: entity.NotifyLike},
+		{value: `{"type":"like","from_user_id":1,"to_user_id":2}`, want: entity.NotifyLike},
+		{value: `{"type":"like","from_user_id":1,"to_user_id":2,"super":true}`, want: entity.NotifySuperLike},
+		{value: `{"type":"like.undone","from_user_id":1,"to_user_id":2}`, want: entity.NotifyLikeUndone},
+		{value: `{"type":"match","from_user_id":1,"to_user_id":2}`, want: entity.NotifyMatch},
+		{value: `{"type":"match.ended","from_user_id":1,"to_user_id":2}`, want: entity.NotifyMatchEnded},
+		{value: `{"type":"unmatch","from_user_id":1,"to_user_id":2}`, want: entity.NotifyMatchEnded},
+		{value: `{"type":"match.expiring","from_user_id":1,"to_user_id":2}`, want: entity.NotifyMatchExpiring},
+		{value: `{"type":"profile.approved","to_user_id":2}`, want: entity.NotifyProfileApproved},
+		{value: `{"type":"profile.rejected","to_user_id":2,"reason":"фото"}`, want: entity.NotifyProfileRejected},
+		{value: `{"type":"reminder.inactivity","to_user_id":2}`, want: entity.NotifyInactivity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			n := newStubNotifier()
+			c := newTestConsumer(n)
+
+			if err := c.processWithRetry(context.Background(), kafkaMessage(tt.value)); err != nil {
+				t.Fatal(err)
+			}
+			if len(n.accepted) != 1 {
+				t.Fatalf("accepted %d messages, want 1", len(n.accepted))
+			}
+			got := n.accepted[0]
+			if got.Type != tt.want || got.ToUserID != 2 {
+				t.Fatalf("got %s to %d, want %s to 2", got.Type, got.ToUserID, tt.want)
+			}
+			if want := fmt.Sprintf("%s/%d/%d", "likes", 1, 7); got.EventKey != want {
+				t.Fatalf("event key = %q, want %q", got.EventKey, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"serviceNotification/internal/entity"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// UserEventProducer - публикует события о пользователях для serviceUser
+type UserEventProducer struct {
+	writer *kafka.Writer
+}
+
+func NewUserEventProducer(brokers []string, topic string) (*UserEventProducer, error) {
+	if len(brokers) == 0 || brokers[0] == "" || topic == "" {
+		return nil, errors.New("не указаны параметры подключения к Kafka")
+	}
+	return &UserEventProducer{writer: &kafka.Writer{
+		Addr:                   kafka.TCP(brokers...),
+		Topic:                  topic,
+		Balancer:               &kafka.Hash{},
+		AllowAutoTopicCreation: true,
+	}}, nil
+}
+
+// PublishUnreachable - событие user.unreachable. Ключ - ID пользователя, чтобы
+// события об одном пользователе читались по порядку
+func (p *UserEventProducer) PublishUnreachable(ctx context.Context, userID int64, reason string) error {
+	data, err := json.Marshal(entity.UserEvent{
+		Type:   entity.EventUserUnreachable,
+		UserID: userID,
+		Reason: reason,
+		At:     time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(strconv.FormatInt(userID, 10)),
+		Value: data,
+	})
+}
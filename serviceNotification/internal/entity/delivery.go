@@ -0,0 +1,31 @@
+package entity
+
+import "fmt"
+
+// Причины, по которым сообщение не доставлено
+const (
+	// FailureBlocked - пользователь заблокировал бота
+	FailureBlocked = "blocked"
+	// FailureChatNotFound - чата с пользователем нет
+	FailureChatNotFound = "chat_not_found"
+	// FailureDeactivated - аккаунт Telegram удален
+	FailureDeactivated = "deactivated"
+	// FailureTransient - сеть, 5xx, 429 и прочее, что стоит повторить
+	FailureTransient = "transient"
+)
+
+// UnreachableError - постоянная ошибка доставки: повторять бесполезно, пока
+// пользователь сам не вернется в бота
+type UnreachableError struct {
+	UserID int64
+	Reason string
+	Err    error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("пользователь %d недоступен (%s): %v", e.UserID, e.Reason, e.Err)
+}
+
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
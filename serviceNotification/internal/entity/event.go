@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// Типы событий, которые приходят из serviceMatch
+const (
+	EventLike          = "like"
+	EventMatch         = "match"
+	EventUnmatch       = "unmatch"
+	EventMatchEnded    = "match.ended"
+	EventMatchExpiring = "match.expiring"
+	EventLikeUndone    = "like.undone"
+)
+
+// Типы событий модерации и напоминаний; получатель - to_user_id
+const (
+	EventProfileApproved    = "profile.approved"
+	EventProfileRejected    = "profile.rejected"
+	EventInactivityReminder = "reminder.inactivity"
+)
+
+// Цели лайка
+const (
+	TargetPhoto  = "photo"
+	TargetPrompt = "prompt"
+)
+
+// Event - событие из Kafka в формате JSON
+type Event struct {
+	Type       string     `json:"type"`
+	FromUserID int64      `json:"from_user_id"`
+	ToUserID   int64      `json:"to_user_id"`
+	Target     string     `json:"target,omitempty"`
+	PromptID   *int       `json:"prompt_id,omitempty"`
+	Comment    string     `json:"comment,omitempty"`
+	Super      bool       `json:"super,omitempty"`
+	Priority   string     `json:"priority,omitempty"`
+	MatchID    int64      `json:"match_id,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// EventUserUnreachable - бот не может писать пользователю; событие читает
+// serviceUser и скрывает анкету из выдачи
+const EventUserUnreachable = "user.unreachable"
+
+// UserEvent - событие о пользователе для serviceUser
+type UserEvent struct {
+	Type   string    `json:"type"`
+	UserID int64     `json:"user_id"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
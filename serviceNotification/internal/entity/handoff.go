@@ -0,0 +1,25 @@
+package entity
+
+// Виды уведомлений, кнопки которых обрабатывает serviceBot
+const (
+	HandoffLiker = "liker"
+	HandoffMatch = "match"
+)
+
+// Handoff - уведомление, которое отправляет serviceBot: обновления от Telegram
+// получает только он, поэтому и интерактивные кнопки должны быть его
+type Handoff struct {
+	UserID    int64  `json:"user_id"`
+	Kind      string `json:"kind"`
+	TargetID  int64  `json:"target_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode,omitempty"`
+	// LanguageCode - язык получателя из анкеты, на нем подписываются кнопки
+	LanguageCode string `json:"language_code,omitempty"`
+}
+
+// SentMessage - отправленное ботом сообщение, по нему уведомление можно отозвать
+type SentMessage struct {
+	ChatID    int64 `json:"chat_id"`
+	MessageID int   `json:"message_id"`
+}
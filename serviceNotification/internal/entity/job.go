@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+// JobReport - итог запуска задачи планировщика или ее предпросмотра
+type JobReport struct {
+	Job        string    `json:"job"`
+	DryRun     bool      `json:"dry_run"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	// Candidates - сколько пользователей подошло под условие задачи
+	Candidates int `json:"candidates"`
+	// Sent - при предпросмотре: сколько напоминаний ушло бы
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"`
+	// Skipped - пропущенные получатели по причинам: disabled, cooldown
+	Skipped map[string]int `json:"skipped,omitempty"`
+	// Targets - кому ушло бы уведомление; заполняется только при предпросмотре
+	Targets []JobTarget `json:"targets,omitempty"`
+}
+
+// JobTarget - получатель напоминания в предпросмотре
+type JobTarget struct {
+	UserID int64  `json:"user_id"`
+	Count  int    `json:"count"`
+	City   string `json:"city,omitempty"`
+}
+
+// Причины пропуска получателя задачей
+const (
+	JobSkipDisabled = "disabled"
+	JobSkipCooldown = "cooldown"
+)
+
+// Skip - учитывает пропущенного получателя
+func (r *JobReport) Skip(reason string) {
+	if r.Skipped == nil {
+		r.Skipped = make(map[string]int)
+	}
+	r.Skipped[reason]++
+}
+
+// JobInfo - задача планировщика для админки
+type JobInfo struct {
+	Name       string     `json:"name"`
+	Schedule   string     `json:"schedule"`
+	NextRun    time.Time  `json:"next_run"`
+	LastReport *JobReport `json:"last_report,omitempty"`
+}
@@ -1,7 +1,47 @@
 package entity
 
+import "time"
+
+// Типы уведомлений; у каждого свой обработчик и шаблон в адаптере Telegram
+const (
+	NotifyLike            = "like"
+	NotifySuperLike       = "superlike"
+	NotifyLikeUndone      = "like_undone"
+	NotifyMatch           = "match"
+	NotifyMatchEnded      = "match_ended"
+	NotifyMatchExpiring   = "match_expiring"
+	NotifyProfileApproved = "profile_approved"
+	NotifyProfileRejected = "profile_rejected"
+	NotifyInactivity      = "inactivity_reminder"
+	// Напоминания планировщика; отключаются вместе с inactivity_reminder
+	NotifyUnansweredLikes = "unanswered_likes"
+	NotifyNewInCity       = "new_in_city"
+)
+
 type Message struct {
+	// Type - тип уведомления, одна из констант Notify*
+	Type string
+	// FromUserID - второй участник; у уведомлений о модерации и напоминаний пустой
 	FromUserID int64
 	ToUserID   int64
-	Text       string
+	// PromptID - вопрос-карточка получателя, на который отреагировали лайком
+	PromptID *int
+	// Target - на что поставлен лайк: фото или вопрос-карточка
+	Target string
+	// Comment - сообщение, оставленное вместе с лайком
+	Comment string
+	// MatchID и ExpiresAt - для уведомлений о парах
+	MatchID   int64
+	ExpiresAt *time.Time
+	// Reason - причина отклонения анкеты модератором
+	Reason string
+	// Count и City - для напоминаний: сколько лайков ждут ответа или сколько
+	// новых анкет появилось в городе
+	Count int
+	City  string
+	// InboxID - запись во входящих; заполняется при приеме уведомления
+	InboxID int64
+	// EventKey - сообщение Kafka (topic/partition/offset), из которого пришло
+	// уведомление. Повтор того же события обновляет прежнюю запись во входящих
+	EventKey string
 }
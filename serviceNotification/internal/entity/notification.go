@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Статусы записи во входящих
+const (
+	// StatusQueued - принято, но еще не отправлено: ждет тихих часов или сводки
+	StatusQueued = "queued"
+	StatusSent   = "sent"
+	StatusFailed = "failed"
+	// StatusRead - пользователь открыл уведомление; ставится только после sent
+	StatusRead = "read"
+)
+
+// Причины отказа, кроме ошибок доставки Failure*
+const (
+	// SkipUnreachable - serviceUser уже пометил пользователя недоступным
+	SkipUnreachable = "unreachable"
+	// SkipDisabled - пользователь отключил тип, пока уведомление ждало отправки
+	SkipDisabled = "disabled"
+	// SkipCancelled - лайк отменен или пара разорвана до отправки
+	SkipCancelled = "cancelled"
+	// SkipReplaced - такое же уведомление от того же пользователя пришло снова
+	// и заменило собой ожидающее
+	SkipReplaced = "replaced"
+)
+
+// Notification - запись журнала доставки и входящих пользователя
+type Notification struct {
+	ID         int64           `json:"id"`
+	TelegramID int64           `json:"telegram_id"`
+	Type       string          `json:"type"`
+	FromUserID int64           `json:"from_user_id,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+	Status     string          `json:"status"`
+	// Error - причина статуса failed
+	Error     string     `json:"error,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
+
+// Payload - поля уведомления, зависящие от типа
+type Payload struct {
+	PromptID  *int       `json:"prompt_id,omitempty"`
+	Target    string     `json:"target,omitempty"`
+	Comment   string     `json:"comment,omitempty"`
+	MatchID   int64      `json:"match_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	Count     int        `json:"count,omitempty"`
+	City      string     `json:"city,omitempty"`
+}
+
+// PayloadOf - содержимое уведомления для журнала
+func PayloadOf(msg Message) Payload {
+	return Payload{
+		PromptID:  msg.PromptID,
+		Target:    msg.Target,
+		Comment:   msg.Comment,
+		MatchID:   msg.MatchID,
+		ExpiresAt: msg.ExpiresAt,
+		Reason:    msg.Reason,
+		Count:     msg.Count,
+		City:      msg.City,
+	}
+}
+
+// Page - параметры постраничной выдачи
+type Page struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
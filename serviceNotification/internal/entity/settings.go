@@ -0,0 +1,28 @@
+package entity
+
+import "slices"
+
+// Режимы доставки уведомлений
+const (
+	// ModeInstant - уведомление приходит сразу, если не идут тихие часы
+	ModeInstant = "instant"
+	// ModeDigest - уведомления копятся и приходят одной сводкой
+	ModeDigest = "digest"
+)
+
+// NotificationSettings - настройки уведомлений пользователя из serviceUser
+type NotificationSettings struct {
+	// Disabled - отключенные типы уведомлений
+	Disabled []string `json:"disabled"`
+	// QuietFrom и QuietTo - тихие часы по местному времени, окно может
+	// переходить через полночь; nil - тихих часов нет
+	QuietFrom *int   `json:"quiet_from,omitempty"`
+	QuietTo   *int   `json:"quiet_to,omitempty"`
+	TimeZone  string `json:"time_zone"`
+	Mode      string `json:"mode"`
+}
+
+// Enabled - пользователь не отключал уведомления этого типа
+func (s NotificationSettings) Enabled(notifyType string) bool {
+	return !slices.Contains(s.Disabled, notifyType)
+}
@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type User struct {
 	ID          int    `json:"id"`
 	TelegramID  int64  `json:"telegram_id"`
@@ -9,4 +11,33 @@ type User struct {
 	Gender      string `json:"gender,omitempty"`
 	Description string `json:"description"`
 	Photo       string `json:"photo"`
+	// LanguageCode - language_code из Telegram, на этом языке пишутся уведомления
+	LanguageCode string `json:"language_code,omitempty"`
+
+	LastActiveAt time.Time `json:"last_active_at"`
+	// UnreachableAt - бот не может писать пользователю, уведомления не отправляются
+	UnreachableAt *time.Time     `json:"unreachable_at,omitempty"`
+	Prompts       []PromptAnswer `json:"prompts,omitempty"`
+}
+
+// PromptAnswer - ответ пользователя на вопрос-карточку
+type PromptAnswer struct {
+	PromptID int    `json:"prompt_id"`
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// PendingLikes - неотвеченные лайки пользователя из serviceMatch
+type PendingLikes struct {
+	UserID int64     `json:"user_id"`
+	Count  int       `json:"count"`
+	Oldest time.Time `json:"oldest"`
+}
+
+// InactiveUser - давно не заходивший пользователь и новые анкеты в его городе из serviceUser
+type InactiveUser struct {
+	TelegramID   int64     `json:"telegram_id"`
+	City         string    `json:"city"`
+	LastActiveAt time.Time `json:"last_active_at"`
+	NewProfiles  int       `json:"new_profiles"`
 }
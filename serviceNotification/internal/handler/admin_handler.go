@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"serviceNotification/internal/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+func NewAdminHandler(s *scheduler.Scheduler, router *gin.Engine, adminToken string) *AdminHandler {
+	h := &AdminHandler{scheduler: s}
+	admin := router.Group("/admin", AdminAuth(adminToken))
+	admin.GET("/jobs", h.Jobs)
+	admin.GET("/jobs/:name/preview", h.Preview)
+	admin.POST("/jobs/:name/run", h.Run)
+	return h
+}
+
+// AdminAuth пропускает только запросы с верным X-Admin-Token.
+// Если токен не настроен, админские эндпоинты закрыты полностью.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got := c.GetHeader("X-Admin-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Jobs - GET /admin/jobs задачи планировщика, расписание и итог последнего запуска
+func (h *AdminHandler) Jobs(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"items": h.scheduler.Jobs()})
+}
+
+// Preview - GET /admin/jobs/:name/preview кому ушли бы напоминания сейчас
+func (h *AdminHandler) Preview(c *gin.Context) {
+	report, err := h.scheduler.Preview(c.Request.Context(), c.Param("name"))
+	if writeJobError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Run - POST /admin/jobs/:name/run внеплановый запуск задачи
+func (h *AdminHandler) Run(c *gin.Context) {
+	// Задача доводится до конца, даже если клиент отключился
+	report, err := h.scheduler.Trigger(context.Background(), c.Param("name"))
+	if writeJobError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// writeJobError - ответ с ошибкой задачи; false, если ошибки нет
+func writeJobError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, scheduler.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, scheduler.ErrJobRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"serviceNotification/internal/entity"
+	"serviceNotification/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InboxHandler struct {
+	uc *usecase.InboxUsecase
+}
+
+func NewInboxHandler(uc *usecase.InboxUsecase, router *gin.Engine) *InboxHandler {
+	handler := &InboxHandler{uc: uc}
+	router.GET("/notifications/:telegram_id", handler.List)
+	router.POST("/notifications/:telegram_id/read", handler.MarkRead)
+	router.POST("/notifications/:telegram_id/read-all", handler.MarkAllRead)
+	return handler
+}
+
+// List - GET /notifications/:telegram_id?status=&limit=&offset=
+func (h *InboxHandler) List(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	items, hasMore, unread, err := h.uc.List(c.Request.Context(), userID, c.Query("status"), page)
+	if errors.Is(err, usecase.ErrInvalidStatus) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "has_more": hasMore, "unread": unread})
+}
+
+// MarkRead - POST /notifications/:telegram_id/read {"ids": [...]}
+func (h *InboxHandler) MarkRead(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	updated, unread, err := h.uc.MarkRead(c.Request.Context(), userID, req.IDs)
+	if errors.Is(err, usecase.ErrNoIDs) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"updated": updated, "unread": unread})
+}
+
+// MarkAllRead - POST /notifications/:telegram_id/read-all
+func (h *InboxHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	updated, unread, err := h.uc.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"updated": updated, "unread": unread})
+}
+
+// parseUserID - telegram_id из пути; при ошибке ответ уже отправлен
+func parseUserID(c *gin.Context) (int64, bool) {
+	userID, err := strconv.ParseInt(c.Param("telegram_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// parsePage - limit и offset из query; при ошибке ответ уже отправлен
+func parsePage(c *gin.Context) (entity.Page, bool) {
+	var (
+		page entity.Page
+		err  error
+	)
+	if raw := c.Query("limit"); raw != "" {
+		if page.Limit, err = strconv.Atoi(raw); err != nil || page.Limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return entity.Page{}, false
+		}
+	}
+	if raw := c.Query("offset"); raw != "" {
+		if page.Offset, err = strconv.Atoi(raw); err != nil || page.Offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return entity.Page{}, false
+		}
+	}
+	return page, true
+}
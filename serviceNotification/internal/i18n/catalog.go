@@ -0,0 +1,117 @@
+// Package i18n - тексты уведомлений по языкам.
+//
+// Это урезанная копия serviceBot/internal/i18n: сервисы - отдельные Go-модули
+// со своими образами, а общего модуля в репозитории нет. Здесь оставлено
+// только то, что нужно адаптеру Telegram: встроенный каталог, Text и Locale.
+// Формат шаблонов и правила множественного числа должны совпадать с ботом,
+// чтобы одно и то же уведомление звучало одинаково в обоих сервисах
+package i18n
+
+import (
+	"log"
+	"strings"
+	"text/template"
+)
+
+// DefaultLocale - язык анкет, для которых language_code неизвестен
+const DefaultLocale = "ru"
+
+// Args - данные для шаблона сообщения
+type Args map[string]any
+
+// Catalog - шаблоны сообщений по языкам. Если сообщения нет в языке
+// пользователя, берется DefaultLocale
+type Catalog struct {
+	locales map[string]*template.Template
+}
+
+// New - каталог из встроенных наборов сообщений. Ошибка в шаблоне -
+// ошибка программиста, поэтому паникует при старте
+func New() *Catalog {
+	c := &Catalog{locales: make(map[string]*template.Template, len(bundles))}
+	for locale, bundle := range bundles {
+		root := template.New(locale).Funcs(template.FuncMap{"plural": pluralFunc(locale)})
+		for key, text := range bundle {
+			template.Must(root.New(key).Parse(text))
+		}
+		c.locales[locale] = root
+	}
+	return c
+}
+
+// Text - сообщение key на языке locale. Неизвестный ключ возвращается как есть,
+// чтобы пропущенный перевод был виден, но не ломал уведомление
+func (c *Catalog) Text(locale, key string, args Args) string {
+	t := c.lookup(locale, key)
+	if t == nil {
+		log.Printf("i18n: нет сообщения %q", key)
+		return key
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, args); err != nil {
+		log.Printf("i18n: ошибка шаблона %q (%s): %v", key, locale, err)
+		return key
+	}
+	return sb.String()
+}
+
+func (c *Catalog) lookup(locale, key string) *template.Template {
+	if root, ok := c.locales[locale]; ok {
+		if t := root.Lookup(key); t != nil {
+			return t
+		}
+	}
+	if root, ok := c.locales[DefaultLocale]; ok {
+		return root.Lookup(key)
+	}
+	return nil
+}
+
+// Locale - язык каталога по language_code из Telegram: "ru-RU" -> "ru".
+// Пустой код - DefaultLocale, любой другой неизвестный язык - английский
+func Locale(languageCode string) string {
+	if languageCode == "" {
+		return DefaultLocale
+	}
+	lang, _, _ := strings.Cut(strings.ToLower(languageCode), "-")
+	if _, ok := bundles[lang]; ok {
+		return lang
+	}
+	return "en"
+}
+
+// pluralFunc - функция шаблона {{plural .Count "лайк" "лайка" "лайков"}}:
+// в русском три формы (1 лайк, 2 лайка, 5 лайков), в остальных языках две
+func pluralFunc(locale string) func(n int, forms ...string) string {
+	return func(n int, forms ...string) string {
+		if len(forms) == 0 {
+			return ""
+		}
+		i := englishPlural(n)
+		if locale == "ru" {
+			i = russianPlural(n)
+		}
+		return forms[min(i, len(forms)-1)]
+	}
+}
+
+func russianPlural(n int) int {
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	default:
+		return 2
+	}
+}
+
+func englishPlural(n int) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,23 @@
+package i18n
+
+var en = map[string]string{
+	"someone": "a user",
+	"comment": `{{if .Comment}}` + "\n\n" + `✉️ “{{.Comment}}”{{end}}`,
+
+	"like":                `{{if .Question}}Someone liked your answer to “{{.Question}}”!{{else if .Photo}}Someone liked your photo!{{else}}Someone liked you!{{end}}{{template "comment" .}}`,
+	"superlike":           `🌟 {{if .Question}}You got a super like for your answer to “{{.Question}}”!{{else if .Photo}}You got a super like for your photo!{{else}}You got a super like!{{end}}{{template "comment" .}}`,
+	"match":               `💞 It's a match with {{html .Name}}! <a href="tg://user?id={{.UserID}}">Start chatting</a>`,
+	"match_expiring":      `⏳ Your match with {{.Name}} expires {{if .Hours}}in {{.Hours}} {{plural .Hours "hour" "hours"}}{{else}}soon{{end}}. If you are talking, send /keep{{.MatchID}} to keep it.`,
+	"profile_approved":    `✅ Your profile passed moderation and is visible to other users.`,
+	"profile_rejected":    `❌ Your profile did not pass moderation{{if .Reason}}: {{.Reason}}{{end}}. Edit it in the bot menu: /start`,
+	"inactivity_reminder": `👋 Long time no see! New profiles are waiting for you: /browse`,
+	"unanswered_likes":    `❤ {{.Count}} {{plural .Count "person is" "people are"}} waiting for you to answer their like. Take a look: /start`,
+	"new_in_city":         `👋 While you were away, {{.Count}} new {{plural .Count "profile" "profiles"}} appeared{{if .City}} in {{.City}}{{end}}: /browse`,
+
+	"digest.match":               `💞 You have {{.Count}} new {{plural .Count "match" "matches"}}`,
+	"digest.superlike":           `🌟 You have {{.Count}} new super {{plural .Count "like" "likes"}}`,
+	"digest.like":                `❤ You have {{.Count}} new {{plural .Count "like" "likes"}}`,
+	"digest.match_expiring":      `⏳ {{.Count}} {{plural .Count "match expires" "matches expire"}} soon`,
+	"digest.inactivity_reminder": `👋 New profiles are waiting for you`,
+	"digest.footer":              `Open the bot: /start`,
+}
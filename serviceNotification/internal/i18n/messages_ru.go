@@ -0,0 +1,29 @@
+package i18n
+
+// bundles - встроенные наборы сообщений по языкам. Ключ сообщения - тип уведомления
+var bundles = map[string]map[string]string{
+	"ru": ru,
+	"en": en,
+}
+
+var ru = map[string]string{
+	"someone": "пользователем",
+	"comment": `{{if .Comment}}` + "\n\n" + `✉️ «{{.Comment}}»{{end}}`,
+
+	"like":                `{{if .Question}}Вас лайкнули за ответ на «{{.Question}}»!{{else if .Photo}}Вас лайкнули за фото!{{else}}Вас лайкнули!{{end}}{{template "comment" .}}`,
+	"superlike":           `🌟 {{if .Question}}Вам отправили суперлайк за ответ на «{{.Question}}»!{{else if .Photo}}Вам отправили суперлайк за фото!{{else}}Вам отправили суперлайк!{{end}}{{template "comment" .}}`,
+	"match":               `💞 У вас взаимная симпатия с {{html .Name}}! <a href="tg://user?id={{.UserID}}">Начать общение</a>`,
+	"match_expiring":      `⏳ Пара с {{.Name}} истечет {{if .Hours}}через {{.Hours}} {{plural .Hours "час" "часа" "часов"}}{{else}}скоро{{end}}. Если вы общаетесь, отправь /keep{{.MatchID}}, чтобы сохранить ее.`,
+	"profile_approved":    `✅ Анкета прошла модерацию и видна другим пользователям.`,
+	"profile_rejected":    `❌ Анкета не прошла модерацию{{if .Reason}}: {{.Reason}}{{end}}. Измени ее в меню бота: /start`,
+	"inactivity_reminder": `👋 Давно не виделись! Тебя ждут новые анкеты: /browse`,
+	"unanswered_likes":    `❤ {{.Count}} {{plural .Count "человек ждет" "человека ждут" "человек ждут"}} твоего ответа на лайк. Посмотреть: /start`,
+	"new_in_city":         `👋 Пока тебя не было, {{if .City}}в городе {{.City}} {{end}}{{plural .Count "появилась" "появились" "появилось"}} {{.Count}} {{plural .Count "новая анкета" "новые анкеты" "новых анкет"}}: /browse`,
+
+	"digest.match":               `💞 У вас {{.Count}} {{plural .Count "новая пара" "новые пары" "новых пар"}}`,
+	"digest.superlike":           `🌟 У вас {{.Count}} {{plural .Count "новый суперлайк" "новых суперлайка" "новых суперлайков"}}`,
+	"digest.like":                `❤ У вас {{.Count}} {{plural .Count "новый лайк" "новых лайка" "новых лайков"}}`,
+	"digest.match_expiring":      `⏳ {{.Count}} {{plural .Count "пара скоро истечет" "пары скоро истекут" "пар скоро истекут"}}`,
+	"digest.inactivity_reminder": `👋 Тебя ждут новые анкеты`,
+	"digest.footer":              `Открыть бота: /start`,
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"serviceNotification/internal/entity"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NotificationRepository - журнал доставки и входящие пользователей в Postgres
+type NotificationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotificationRepository(pool *pgxpool.Pool) *NotificationRepository {
+	return &NotificationRepository{pool: pool}
+}
+
+// Record - новая запись журнала, возвращает ее ID. Повтор события с тем же
+// EventKey возвращает прежнюю запись; отправленную или прочитанную он не меняет
+func (r *NotificationRepository) Record(ctx context.Context, msg entity.Message, status, reason string) (int64, error) {
+	payload, err := json.Marshal(entity.PayloadOf(msg))
+	if err != nil {
+		return 0, err
+	}
+	query := `
+		INSERT INTO notifications (telegram_id, type, from_user_id, payload, status, error, sent_at, event_key)
+		VALUES ($1, $2, NULLIF($3::bigint, 0), $4, $5::text, NULLIF($6::text, ''), CASE WHEN $5::text = 'sent' THEN now() END, NULLIF($7::text, ''))
+		ON CONFLICT (event_key, telegram_id) DO UPDATE
+		SET status = CASE WHEN notifications.status IN ('sent', 'read') THEN notifications.status ELSE EXCLUDED.status END,
+			error = CASE WHEN notifications.status IN ('sent', 'read') THEN notifications.error ELSE EXCLUDED.error END,
+			updated_at = now()
+		RETURNING id
+	`
+	var id int64
+	err = r.pool.QueryRow(ctx, query, msg.ToUserID, msg.Type, msg.FromUserID, payload, status, reason, msg.EventKey).Scan(&id)
+	return id, err
+}
+
+// SetStatus - результат попытки доставки. Без reason прежняя ошибка сохраняется
+// до успешной отправки. Прочитанные записи не меняются
+func (r *NotificationRepository) SetStatus(ctx context.Context, ids []int64, status, reason string) error {
+	query := `
+		UPDATE notifications
+		SET status = $2::text,
+			error = CASE WHEN $2::text = 'sent' THEN NULL ELSE COALESCE(NULLIF($3::text, ''), error) END,
+			sent_at = CASE WHEN $2::text = 'sent' THEN now() ELSE sent_at END,
+			updated_at = now()
+		WHERE id = ANY($1) AND status <> 'read'
+	`
+	_, err := r.pool.Exec(ctx, query, ids, status, reason)
+	return err
+}
+
+// CancelQueued - ожидающие отправки уведомления типов types от fromUserID
+// больше не будут отправлены
+func (r *NotificationRepository) CancelQueued(ctx context.Context, userID, fromUserID int64, types []string, reason string) error {
+	query := `
+		UPDATE notifications
+		SET status = 'failed', error = $4, updated_at = now()
+		WHERE telegram_id = $1 AND from_user_id = $2 AND type = ANY($3) AND status = 'queued'
+	`
+	_, err := r.pool.Exec(ctx, query, userID, fromUserID, types, reason)
+	return err
+}
+
+// List - записи пользователя, новые сверху. Пустой status - все статусы
+func (r *NotificationRepository) List(ctx context.Context, userID int64, status string, page entity.Page) ([]entity.Notification, error) {
+	query := `
+		SELECT id, telegram_id, type, COALESCE(from_user_id, 0), payload, status, COALESCE(error, ''),
+			created_at, updated_at, sent_at, read_at
+		FROM notifications
+		WHERE telegram_id = $1 AND ($2::text = '' OR status = $2::text)
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.pool.Query(ctx, query, userID, status, page.Limit, page.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []entity.Notification{}
+	for rows.Next() {
+		var n entity.Notification
+		if err := rows.Scan(&n.ID, &n.TelegramID, &n.Type, &n.FromUserID, &n.Payload, &n.Status, &n.Error,
+			&n.CreatedAt, &n.UpdatedAt, &n.SentAt, &n.ReadAt); err != nil {
+			return nil, err
+		}
+		items = append(items, n)
+	}
+	return items, rows.Err()
+}
+
+// CountUnread - доставленные, но не прочитанные уведомления
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT count(*) FROM notifications WHERE telegram_id = $1 AND status = 'sent'`, userID).Scan(&count)
+	return count, err
+}
+
+// MarkRead - отмечает прочитанными уведомления ids пользователя, а при пустом
+// ids - все доставленные. Возвращает число измененных записей
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID int64, ids []int64) (int64, error) {
+	query := `
+		UPDATE notifications
+		SET status = 'read', read_at = now(), updated_at = now()
+		WHERE telegram_id = $1 AND status = 'sent' AND (cardinality($2::bigint[]) = 0 OR id = ANY($2::bigint[]))
+	`
+	if ids == nil {
+		ids = []int64{}
+	}
+	tag, err := r.pool.Exec(ctx, query, userID, ids)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
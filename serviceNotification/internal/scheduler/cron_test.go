@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q): expected error", spec)
+		}
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	at := func(s string) time.Time {
+		v, err := time.ParseInLocation("2006-01-02 15:04", s, msk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		spec  string
+		after string
+		want  string
+	}{
+		{"0 19 * * *", "2024-05-10 12:30", "2024-05-10 19:00"},
+		{"0 19 * * *", "2024-05-10 19:00", "2024-05-11 19:00"},
+		{"*/15 * * * *", "2024-05-10 12:31", "2024-05-10 12:45"},
+		{"5/20 * * * *", "2024-05-10 12:26", "2024-05-10 12:45"},
+		{"0 9-11 * * *", "2024-05-10 11:00", "2024-05-11 09:00"},
+		{"30 8 * * 1,3", "2024-05-10 12:00", "2024-05-13 08:30"}, // пятница -> понедельник
+		{"0 12 * * 7", "2024-05-10 12:00", "2024-05-12 12:00"},   // 7 - воскресенье
+		{"0 0 31 * *", "2024-04-15 00:00", "2024-05-31 00:00"},
+		{"0 0 1 1 *", "2024-05-10 00:00", "2025-01-01 00:00"},
+		// день месяца или день недели, как в cron
+		{"0 0 13 * 5", "2024-05-10 01:00", "2024-05-13 00:00"},
+		{"0 0 13 * 5", "2024-05-13 01:00", "2024-05-17 00:00"},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.spec, err)
+		}
+		if got := s.Next(at(tt.after)); !got.Equal(at(tt.want)) {
+			t.Errorf("%q after %s: got %s, want %s", tt.spec, tt.after, got.Format("2006-01-02 15:04"), tt.want)
+		}
+	}
+}
+
+func TestSchedule_NextNever(t *testing.T) {
+	s, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Next(time.Now()); !got.IsZero() {
+		t.Fatalf("31 февраля не бывает, got %s", got)
+	}
+}
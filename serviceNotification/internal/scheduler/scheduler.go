@@ -0,0 +1,221 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"serviceNotification/internal/entity"
+	"sync"
+	"time"
+)
+
+var (
+	ErrJobNotFound = errors.New("задача не найдена")
+	// ErrJobRunning - задача уже выполняется на этом или другом экземпляре
+	ErrJobRunning = errors.New("задача уже выполняется")
+)
+
+// RunFunc - тело задачи. При dryRun задача только считает, кому ушли бы
+// уведомления, и ничего не отправляет
+type RunFunc func(ctx context.Context, dryRun bool) (entity.JobReport, error)
+
+// Locker - распределенная блокировка: задачу выполняет только один экземпляр
+// сервиса. release снимает блокировку раньше ttl
+type Locker interface {
+	Acquire(ctx context.Context, key string, ttl time.Duration) (release func(), ok bool, err error)
+}
+
+type job struct {
+	name     string
+	schedule Schedule
+	run      RunFunc
+	next     time.Time
+	last     *entity.JobReport
+}
+
+// Scheduler - запускает задачи по расписанию cron. Каждый плановый запуск
+// выполняется один раз на все экземпляры сервиса
+type Scheduler struct {
+	locker   Locker
+	location *time.Location
+	// timeout - предельная длительность задачи, на столько же берется блокировка
+	timeout time.Duration
+	now     func() time.Time
+
+	mu   sync.Mutex
+	jobs []*job
+}
+
+func New(locker Locker, location *time.Location, timeout time.Duration) *Scheduler {
+	if location == nil {
+		location = time.UTC
+	}
+	return &Scheduler{locker: locker, location: location, timeout: timeout, now: time.Now}
+}
+
+// Add - регистрирует задачу с расписанием spec в часовом поясе планировщика
+func (s *Scheduler) Add(name, spec string, run RunFunc) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if j.name == name {
+			return fmt.Errorf("задача %s уже зарегистрирована", name)
+		}
+	}
+	s.jobs = append(s.jobs, &job{
+		name:     name,
+		schedule: schedule,
+		run:      run,
+		next:     schedule.Next(s.now().In(s.location)),
+	})
+	return nil
+}
+
+// Jobs - задачи, ближайшие запуски и итоги последних запусков
+func (s *Scheduler) Jobs() []entity.JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	infos := make([]entity.JobInfo, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		infos = append(infos, entity.JobInfo{
+			Name:       j.name,
+			Schedule:   j.schedule.String(),
+			NextRun:    j.next,
+			LastReport: j.last,
+		})
+	}
+	return infos
+}
+
+// Run - ждет ближайший запуск и выполняет подошедшие задачи. Работает до отмены ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		next, ok := s.nextRun()
+		if !ok {
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		for _, j := range s.due() {
+			go s.runScheduled(ctx, j)
+		}
+	}
+}
+
+func (s *Scheduler) nextRun() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for _, j := range s.jobs {
+		if !j.next.IsZero() && (next.IsZero() || j.next.Before(next)) {
+			next = j.next
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// due - задачи, время которых пришло, с плановым временем запуска;
+// следующий запуск сразу переносится
+func (s *Scheduler) due() []scheduled {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now().In(s.location)
+	var due []scheduled
+	for _, j := range s.jobs {
+		if !j.next.IsZero() && !j.next.After(now) {
+			due = append(due, scheduled{job: j, at: j.next})
+			j.next = j.schedule.Next(now)
+		}
+	}
+	return due
+}
+
+type scheduled struct {
+	job *job
+	at  time.Time
+}
+
+// runScheduled - плановый запуск. Отметка о слоте не снимается, пока не
+// истечет, поэтому другой экземпляр с отстающими часами не повторит запуск
+func (s *Scheduler) runScheduled(ctx context.Context, d scheduled) {
+	slot := fmt.Sprintf("scheduler:slot:%s:%d", d.job.name, d.at.Unix())
+	_, ok, err := s.locker.Acquire(ctx, slot, s.timeout+time.Hour)
+	if err != nil {
+		log.Printf("Задача %s пропущена: %v", d.job.name, err)
+		return
+	}
+	if !ok {
+		return
+	}
+	if _, err := s.execute(ctx, d.job); err != nil && !errors.Is(err, ErrJobRunning) {
+		log.Printf("Ошибка задачи %s: %v", d.job.name, err)
+	}
+}
+
+// Trigger - внеплановый запуск задачи из админки
+func (s *Scheduler) Trigger(ctx context.Context, name string) (entity.JobReport, error) {
+	j, err := s.find(name)
+	if err != nil {
+		return entity.JobReport{}, err
+	}
+	return s.execute(ctx, j)
+}
+
+// Preview - кому ушли бы уведомления, если запустить задачу сейчас
+func (s *Scheduler) Preview(ctx context.Context, name string) (entity.JobReport, error) {
+	j, err := s.find(name)
+	if err != nil {
+		return entity.JobReport{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return j.run(ctx, true)
+}
+
+func (s *Scheduler) find(name string) (*job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if j.name == name {
+			return j, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+}
+
+// execute - выполняет задачу под блокировкой, чтобы плановый и ручной запуски
+// не пересеклись
+func (s *Scheduler) execute(ctx context.Context, j *job) (entity.JobReport, error) {
+	release, ok, err := s.locker.Acquire(ctx, "scheduler:lock:"+j.name, s.timeout)
+	if err != nil {
+		return entity.JobReport{}, fmt.Errorf("блокировка задачи %s: %w", j.name, err)
+	}
+	if !ok {
+		return entity.JobReport{}, fmt.Errorf("%w: %s", ErrJobRunning, j.name)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	report, err := j.run(ctx, false)
+	if err != nil {
+		return report, err
+	}
+	log.Printf("Задача %s: кандидатов %d, отправлено %d, ошибок %d, пропущено %v",
+		j.name, report.Candidates, report.Sent, report.Failed, report.Skipped)
+
+	s.mu.Lock()
+	j.last = &report
+	s.mu.Unlock()
+	return report, nil
+}
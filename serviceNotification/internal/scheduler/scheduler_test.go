@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"serviceNotification/internal/entity"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memLocker - блокировки в памяти, общие для нескольких планировщиков,
+// как Redis у нескольких экземпляров сервиса
+type memLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *memLocker) Acquire(_ context.Context, key string, _ time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held == nil {
+		l.held = make(map[string]bool)
+	}
+	if l.held[key] {
+		return nil, false, nil
+	}
+	l.held[key] = true
+	return func() {
+		l.mu.Lock()
+		delete(l.held, key)
+		l.mu.Unlock()
+	}, true, nil
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	s := New(&memLocker{}, time.UTC, time.Minute)
+	var dryRuns []bool
+	err := s.Add("job", "0 12 * * *", func(_ context.Context, dryRun bool) (entity.JobReport, error) {
+		dryRuns = append(dryRuns, dryRun)
+		return entity.JobReport{Job: "job", Sent: 2}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := s.Trigger(context.Background(), "job")
+	if err != nil || report.Sent != 2 {
+		t.Fatalf("Trigger: %+v, %v", report, err)
+	}
+	if _, err := s.Preview(context.Background(), "job"); err != nil {
+		t.Fatal(err)
+	}
+	if len(dryRuns) != 2 || dryRuns[0] || !dryRuns[1] {
+		t.Fatalf("expected a real run and a dry run, got %v", dryRuns)
+	}
+	if jobs := s.Jobs(); len(jobs) != 1 || jobs[0].LastReport == nil || jobs[0].LastReport.Sent != 2 {
+		t.Fatalf("last report is not kept: %+v", jobs)
+	}
+
+	if _, err := s.Trigger(context.Background(), "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+	if err := s.Add("job", "* * * * *", nil); err == nil {
+		t.Fatal("duplicate job must be rejected")
+	}
+	if err := s.Add("other", "bad", nil); err == nil {
+		t.Fatal("invalid schedule must be rejected")
+	}
+}
+
+func TestScheduler_TriggerWhileRunning(t *testing.T) {
+	s := New(&memLocker{}, time.UTC, time.Minute)
+	started, finish := make(chan struct{}), make(chan struct{})
+	s.Add("job", "0 12 * * *", func(context.Context, bool) (entity.JobReport, error) {
+		close(started)
+		<-finish
+		return entity.JobReport{}, nil
+	})
+
+	done := make(chan error)
+	go func() {
+		_, err := s.Trigger(context.Background(), "job")
+		done <- err
+	}()
+	<-started
+	if _, err := s.Trigger(context.Background(), "job"); !errors.Is(err, ErrJobRunning) {
+		t.Fatalf("expected ErrJobRunning, got %v", err)
+	}
+	close(finish)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScheduler_SingleExecution(t *testing.T) {
+	locker := &memLocker{}
+	now := time.Date(2024, 5, 10, 11, 59, 30, 0, time.UTC)
+
+	var mu sync.Mutex
+	runs := 0
+	run := func(context.Context, bool) (entity.JobReport, error) {
+		mu.Lock()
+		runs++
+		mu.Unlock()
+		return entity.JobReport{}, nil
+	}
+
+	// Два экземпляра сервиса с общим Redis
+	var instances []*Scheduler
+	for range 2 {
+		s := New(locker, time.UTC, time.Minute)
+		s.now = func() time.Time { return now }
+		if err := s.Add("job", "0 12 * * *", run); err != nil {
+			t.Fatal(err)
+		}
+		instances = append(instances, s)
+	}
+
+	now = now.Add(time.Minute)
+	for _, s := range instances {
+		for _, d := range s.due() {
+			s.runScheduled(context.Background(), d)
+		}
+	}
+	if runs != 1 {
+		t.Fatalf("job must run once per slot, ran %d times", runs)
+	}
+	if next := instances[0].Jobs()[0].NextRun; !next.Equal(time.Date(2024, 5, 11, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("next run is not moved: %s", next)
+	}
+}
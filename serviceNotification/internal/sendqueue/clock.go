@@ -0,0 +1,14 @@
+package sendqueue
+
+import "time"
+
+// Clock - источник времени очереди; в тестах подменяется ручными часами
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
@@ -0,0 +1,43 @@
+package sendqueue
+
+import (
+	"expvar"
+	"time"
+)
+
+// Metrics - счетчики очереди в формате expvar. Публикуются отдельно через
+// expvar.Publish, чтобы в тестах можно было создавать сколько угодно очередей
+type Metrics struct {
+	vars *expvar.Map
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{vars: new(expvar.Map).Init()}
+}
+
+// Var - значение для expvar.Publish
+func (m *Metrics) Var() expvar.Var {
+	return m.vars
+}
+
+// Get - текущее значение счетчика, нужно для тестов и логов
+func (m *Metrics) Get(name string) int64 {
+	if v, ok := m.vars.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func (m *Metrics) add(name string, delta int64) {
+	m.vars.Add(name, delta)
+}
+
+func (m *Metrics) setDepth(p Priority, depth int) {
+	v := new(expvar.Int)
+	v.Set(int64(depth))
+	m.vars.Set("depth_"+p.String(), v)
+}
+
+func (m *Metrics) observeWait(d time.Duration) {
+	m.add("wait_ms_total", d.Milliseconds())
+}
@@ -0,0 +1,289 @@
+// Package sendqueue - очередь исходящих запросов к Telegram: общий лимит бота,
+// лимит на чат, паузы по retry_after из ответов 429 и приоритетные полосы
+package sendqueue
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"gopkg.in/telebot.v4"
+)
+
+// Priority - полоса очереди; из более высокой полосы запросы уходят раньше
+type Priority int
+
+const (
+	// PriorityHigh - пары, суперлайки и отзыв уведомлений о них
+	PriorityHigh Priority = iota
+	// PriorityNormal - лайки и остальные уведомления
+	PriorityNormal
+	// PriorityLow - сводки и напоминания: им не страшно подождать
+	PriorityLow
+
+	priorityCount = 3
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "high"
+	case PriorityNormal:
+		return "normal"
+	default:
+		return "low"
+	}
+}
+
+// Config - лимиты очереди. Нулевые значения заменяются ограничениями Telegram
+type Config struct {
+	// PerSecond и Burst - общий token bucket бота
+	PerSecond float64
+	Burst     int
+	// PerChat - минимальный интервал между сообщениями в один чат
+	PerChat time.Duration
+	// MaxFloodRetries - сколько раз повторять запрос после ответа 429
+	MaxFloodRetries int
+}
+
+func (c Config) withDefaults() Config {
+	if c.PerSecond <= 0 {
+		c.PerSecond = 30
+	}
+	if c.Burst <= 0 {
+		c.Burst = int(c.PerSecond)
+	}
+	if c.PerChat <= 0 {
+		c.PerChat = time.Second
+	}
+	if c.MaxFloodRetries <= 0 {
+		c.MaxFloodRetries = 3
+	}
+	return c
+}
+
+// maxChats - после стольких записей о чатах устаревшие удаляются
+const maxChats = 10000
+
+type job struct {
+	chatID   int64
+	priority Priority
+	send     func() error
+	queuedAt time.Time
+	attempts int
+	done     chan error
+}
+
+// Queue - очередь отправки. Send ставит запрос в очередь и ждет результата,
+// отправляет один воркер Run
+type Queue struct {
+	cfg     Config
+	clock   Clock
+	metrics *Metrics
+
+	mu          sync.Mutex
+	lanes       [priorityCount][]*job
+	tokens      float64
+	refilledAt  time.Time
+	chatNext    map[int64]time.Time
+	pausedUntil time.Time
+	wake        chan struct{}
+}
+
+func New(cfg Config, metrics *Metrics) *Queue {
+	return newQueue(cfg, realClock{}, metrics)
+}
+
+func newQueue(cfg Config, clock Clock, metrics *Metrics) *Queue {
+	cfg = cfg.withDefaults()
+	if metrics == nil {
+		metrics = NewMetrics()
+	}
+	return &Queue{
+		cfg:        cfg,
+		clock:      clock,
+		metrics:    metrics,
+		tokens:     float64(cfg.Burst),
+		refilledAt: clock.Now(),
+		chatNext:   make(map[int64]time.Time),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Send - ставит запрос к Telegram в полосу priority и ждет, пока воркер его
+// выполнит. chatID - чат, в который уходит сообщение, для лимита на чат
+func (q *Queue) Send(ctx context.Context, chatID int64, priority Priority, send func() error) error {
+	if priority < PriorityHigh || priority > PriorityLow {
+		priority = PriorityLow
+	}
+	j := &job{chatID: chatID, priority: priority, send: send, queuedAt: q.clock.Now(), done: make(chan error, 1)}
+
+	q.mu.Lock()
+	q.lanes[priority] = append(q.lanes[priority], j)
+	q.metrics.setDepth(priority, len(q.lanes[priority]))
+	q.mu.Unlock()
+	q.metrics.add("queued", 1)
+	q.notify()
+
+	select {
+	case err := <-j.done:
+		return err
+	case <-ctx.Done():
+		q.remove(j)
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// remove - убирает из очереди запрос, который больше никто не ждет
+func (q *Queue) remove(j *job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	lane := q.lanes[j.priority]
+	for i, queued := range lane {
+		if queued == j {
+			q.lanes[j.priority] = append(lane[:i:i], lane[i+1:]...)
+			q.metrics.setDepth(j.priority, len(q.lanes[j.priority]))
+			return
+		}
+	}
+}
+
+// Run - воркер очереди, работает до отмены ctx
+func (q *Queue) Run(ctx context.Context) {
+	for {
+		j, wait := q.take(q.clock.Now())
+		if j != nil {
+			q.finish(j, j.send())
+			continue
+		}
+
+		var timer <-chan time.Time
+		if wait > 0 {
+			timer = q.clock.After(wait)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-timer:
+		}
+	}
+}
+
+// take - следующий запрос, который можно отправить сейчас. Если такого нет,
+// возвращает, сколько ждать; 0 - очередь пуста, ждать нового запроса
+func (q *Queue) take(now time.Time) (*job, time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if now.Before(q.pausedUntil) {
+		return nil, q.pausedUntil.Sub(now)
+	}
+
+	q.tokens = min(float64(q.cfg.Burst), q.tokens+now.Sub(q.refilledAt).Seconds()*q.cfg.PerSecond)
+	q.refilledAt = now
+	if q.tokens < 1 {
+		// Округляем вверх, иначе после ожидания токена может не хватить на доли наносекунды
+		return nil, time.Duration(math.Ceil((1 - q.tokens) / q.cfg.PerSecond * float64(time.Second)))
+	}
+
+	var wait time.Duration
+	for p := range q.lanes {
+		for i, j := range q.lanes[p] {
+			next := q.chatNext[j.chatID]
+			if next.After(now) {
+				if d := next.Sub(now); wait == 0 || d < wait {
+					wait = d
+				}
+				continue
+			}
+			q.lanes[p] = append(q.lanes[p][:i:i], q.lanes[p][i+1:]...)
+			q.metrics.setDepth(Priority(p), len(q.lanes[p]))
+			q.tokens--
+			q.chatNext[j.chatID] = now.Add(q.cfg.PerChat)
+			q.pruneChats(now)
+			q.metrics.observeWait(now.Sub(j.queuedAt))
+			return j, 0
+		}
+	}
+	return nil, wait
+}
+
+func (q *Queue) pruneChats(now time.Time) {
+	if len(q.chatNext) <= maxChats {
+		return
+	}
+	for chatID, next := range q.chatNext {
+		if !next.After(now) {
+			delete(q.chatNext, chatID)
+		}
+	}
+}
+
+// finish - результат запроса. На 429 очередь останавливается на retry_after
+// и запрос возвращается в начало своей полосы
+func (q *Queue) finish(j *job, err error) {
+	after, flood := RetryAfter(err)
+	if !flood {
+		if err != nil {
+			q.metrics.add("failed", 1)
+		} else {
+			q.metrics.add("sent", 1)
+		}
+		j.done <- err
+		return
+	}
+
+	q.metrics.add("rate_limited", 1)
+	now := q.clock.Now()
+	q.mu.Lock()
+	// Лимит Telegram мог быть и общим, поэтому пауза для всей очереди
+	if until := now.Add(after); until.After(q.pausedUntil) {
+		q.pausedUntil = until
+	}
+	j.attempts++
+	retry := j.attempts <= q.cfg.MaxFloodRetries
+	if retry {
+		q.lanes[j.priority] = append([]*job{j}, q.lanes[j.priority]...)
+		q.metrics.setDepth(j.priority, len(q.lanes[j.priority]))
+	}
+	q.mu.Unlock()
+
+	if retry {
+		q.metrics.add("retried", 1)
+		return
+	}
+	q.metrics.add("failed", 1)
+	j.done <- err
+}
+
+// retryAfterer - ошибка, в которой сервер попросил подождать, например
+// ответ 429 внутреннего API serviceBot
+type retryAfterer interface {
+	RetryAfter() time.Duration
+}
+
+// RetryAfter - сколько ждать после ошибки 429 Telegram или serviceBot
+func RetryAfter(err error) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+	var flood telebot.FloodError
+	if errors.As(err, &flood) {
+		return time.Duration(flood.RetryAfter) * time.Second, true
+	}
+	var ra retryAfterer
+	if errors.As(err, &ra) {
+		return ra.RetryAfter(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,269 @@
+package sendqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock - ручные часы: время идет только по Advance
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// enqueue - ставит запрос в очередь без воркера: take вызывается в тесте вручную
+func enqueue(q *Queue, chatID int64, priority Priority, send func() error) *job {
+	j := &job{chatID: chatID, priority: priority, send: send, queuedAt: q.clock.Now(), done: make(chan error, 1)}
+	q.mu.Lock()
+	q.lanes[priority] = append(q.lanes[priority], j)
+	q.mu.Unlock()
+	return j
+}
+
+func noop() error { return nil }
+
+func TestQueue_GlobalTokenBucket(t *testing.T) {
+	clock := newFakeClock()
+	q := newQueue(Config{PerSecond: 30, Burst: 30}, clock, nil)
+	for chat := int64(1); chat <= 31; chat++ {
+		enqueue(q, chat, PriorityNormal, noop)
+	}
+
+	for i := 0; i < 30; i++ {
+		if j, _ := q.take(clock.Now()); j == nil {
+			t.Fatalf("запрос %d не отправлен, хотя токены есть", i+1)
+		}
+	}
+	j, wait := q.take(clock.Now())
+	if j != nil {
+		t.Fatal("31-й запрос ушел сверх лимита")
+	}
+	if want := time.Second / 30; wait < want-time.Millisecond || wait > want+time.Millisecond {
+		t.Fatalf("ожидание токена %v, ожидали около %v", wait, want)
+	}
+
+	clock.Advance(wait)
+	if j, _ := q.take(clock.Now()); j == nil || j.chatID != 31 {
+		t.Fatal("после пополнения токена запрос не отправлен")
+	}
+}
+
+func TestQueue_PerChatLimit(t *testing.T) {
+	clock := newFakeClock()
+	q := newQueue(Config{PerChat: time.Second}, clock, nil)
+	enqueue(q, 1, PriorityNormal, noop)
+	enqueue(q, 1, PriorityNormal, noop)
+	enqueue(q, 2, PriorityNormal, noop)
+
+	if j, _ := q.take(clock.Now()); j == nil || j.chatID != 1 {
+		t.Fatal("первый запрос в чат 1 не отправлен")
+	}
+	// Второй запрос в чат 1 ждет, а чат 2 его обгоняет
+	if j, _ := q.take(clock.Now()); j == nil || j.chatID != 2 {
+		t.Fatal("запрос в чат 2 должен уйти раньше второго запроса в чат 1")
+	}
+	j, wait := q.take(clock.Now())
+	if j != nil || wait != time.Second {
+		t.Fatalf("второй запрос в чат 1: job=%v wait=%v, ожидали паузу 1s", j, wait)
+	}
+
+	clock.Advance(time.Second)
+	if j, _ := q.take(clock.Now()); j == nil || j.chatID != 1 {
+		t.Fatal("второй запрос в чат 1 не отправлен через секунду")
+	}
+}
+
+func TestQueue_PriorityLanes(t *testing.T) {
+	clock := newFakeClock()
+	q := newQueue(Config{}, clock, nil)
+	enqueue(q, 1, PriorityLow, noop)
+	enqueue(q, 2, PriorityNormal, noop)
+	enqueue(q, 3, PriorityHigh, noop)
+
+	var got []int64
+	for {
+		j, _ := q.take(clock.Now())
+		if j == nil {
+			break
+		}
+		got = append(got, j.chatID)
+	}
+	if len(got) != 3 || got[0] != 3 || got[1] != 2 || got[2] != 1 {
+		t.Fatalf("порядок отправки %v, ожидали [3 2 1]", got)
+	}
+}
+
+// floodError - ответ 429 с retry_after
+type floodError struct{ after time.Duration }
+
+func (e floodError) Error() string             { return "too many requests" }
+func (e floodError) RetryAfter() time.Duration { return e.after }
+
+func TestQueue_RetryAfter(t *testing.T) {
+	clock := newFakeClock()
+	metrics := NewMetrics()
+	q := newQueue(Config{}, clock, metrics)
+
+	calls := 0
+	flooded := enqueue(q, 1, PriorityHigh, func() error {
+		calls++
+		if calls == 1 {
+			return floodError{after: 5 * time.Second}
+		}
+		return nil
+	})
+	enqueue(q, 2, PriorityNormal, noop)
+
+	j, _ := q.take(clock.Now())
+	q.finish(j, j.send())
+
+	// Вся очередь стоит retry_after, даже запросы в другие чаты
+	j, wait := q.take(clock.Now())
+	if j != nil || wait != 5*time.Second {
+		t.Fatalf("после 429: job=%v wait=%v, ожидали паузу 5s", j, wait)
+	}
+
+	clock.Advance(5 * time.Second)
+	j, _ = q.take(clock.Now())
+	if j != flooded {
+		t.Fatal("после паузы первым должен повториться запрос, получивший 429")
+	}
+	q.finish(j, j.send())
+	if err := <-flooded.done; err != nil {
+		t.Fatalf("повтор завершился ошибкой: %v", err)
+	}
+
+	if metrics.Get("rate_limited") != 1 || metrics.Get("retried") != 1 || metrics.Get("sent") != 1 {
+		t.Fatalf("метрики: rate_limited=%d retried=%d sent=%d",
+			metrics.Get("rate_limited"), metrics.Get("retried"), metrics.Get("sent"))
+	}
+}
+
+func TestQueue_RetryAfterGivesUp(t *testing.T) {
+	clock := newFakeClock()
+	q := newQueue(Config{MaxFloodRetries: 1}, clock, nil)
+	j := enqueue(q, 1, PriorityNormal, func() error { return floodError{after: time.Second} })
+
+	for i := 0; i < 2; i++ {
+		clock.Advance(time.Second)
+		taken, _ := q.take(clock.Now())
+		if taken != j {
+			t.Fatalf("попытка %d: запрос не взят из очереди", i+1)
+		}
+		q.finish(taken, taken.send())
+	}
+
+	var flood floodError
+	if err := <-j.done; !errors.As(err, &flood) {
+		t.Fatalf("после исчерпания повторов ожидали ошибку 429, получили %v", err)
+	}
+}
+
+func TestQueue_Run(t *testing.T) {
+	clock := newFakeClock()
+	metrics := NewMetrics()
+	q := newQueue(Config{PerChat: time.Second}, clock, metrics)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+
+	var mu sync.Mutex
+	var sent []time.Time
+	send := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, clock.Now())
+		return nil
+	}
+
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { results <- q.Send(ctx, 1, PriorityNormal, send) }()
+	}
+
+	// Первый запрос уходит сразу, второй - только когда часы сдвинутся на секунду
+	waitFor(t, func() bool { return metrics.Get("sent") == 1 })
+	waitFor(t, func() bool {
+		clock.mu.Lock()
+		defer clock.mu.Unlock()
+		return len(clock.waiters) > 0
+	})
+	clock.Advance(time.Second)
+	for i := 0; i < 2; i++ {
+		if err := <-results; err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gap := sent[1].Sub(sent[0]); gap < time.Second {
+		t.Fatalf("сообщения в один чат с интервалом %v, ожидали не меньше 1s", gap)
+	}
+}
+
+func TestQueue_SendCancelled(t *testing.T) {
+	q := newQueue(Config{}, newFakeClock(), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := q.Send(ctx, 1, PriorityNormal, noop); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ожидали context.Canceled, получили %v", err)
+	}
+	if j, _ := q.take(q.clock.Now()); j != nil {
+		t.Fatal("отмененный запрос остался в очереди")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("условие не выполнилось за 2s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
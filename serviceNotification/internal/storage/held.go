@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"serviceNotification/internal/entity"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// heldDueKey - sorted set: получатель -> unix-время, когда отдать его уведомления
+	heldDueKey = "notifications:due"
+	// heldPrefix - hash отложенных уведомлений получателя: ключ уведомления -> JSON
+	heldPrefix = "notifications:held:"
+	// dueBatch - сколько получателей разбирается за один проход
+	dueBatch = 100
+)
+
+// RedisHeldStore - отложенные уведомления в Redis: переживают перезапуск
+// сервиса, а уведомления с одинаковым ключом схлопываются в одно
+type RedisHeldStore struct {
+	client *redis.Client
+}
+
+func NewRedisHeldStore(addr string) (*RedisHeldStore, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("ошибка подключения к Redis: %w", err)
+	}
+	return &RedisHeldStore{client: client}, nil
+}
+
+func heldKey(userID int64) string {
+	return heldPrefix + strconv.FormatInt(userID, 10)
+}
+
+// Hold - откладывает уведомление до at. Срок получателя задает первое
+// отложенное уведомление, следующие его не сдвигают
+func (s *RedisHeldStore) Hold(ctx context.Context, key string, msg entity.Message, at time.Time) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, heldKey(msg.ToUserID), key, data)
+		pipe.ZAddNX(ctx, heldDueKey, redis.Z{Score: float64(at.Unix()), Member: msg.ToUserID})
+		return nil
+	})
+	return err
+}
+
+// Cancel - убирает отложенные уведомления получателя, например об отмененном лайке
+func (s *RedisHeldStore) Cancel(ctx context.Context, userID int64, keys ...string) error {
+	return s.client.HDel(ctx, heldKey(userID), keys...).Err()
+}
+
+// TakeDue - забирает уведомления получателей, срок которых наступил. Получателя
+// забирает тот, кто первым удалил его из очереди, поэтому несколько экземпляров
+// сервиса не отправят одно уведомление дважды
+func (s *RedisHeldStore) TakeDue(ctx context.Context, now time.Time) (map[int64][]entity.Message, error) {
+	ids, err := s.client.ZRangeByScore(ctx, heldDueKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: dueBatch,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	due := make(map[int64][]entity.Message, len(ids))
+	for _, id := range ids {
+		removed, err := s.client.ZRem(ctx, heldDueKey, id).Result()
+		if err != nil {
+			return due, err
+		}
+		if removed == 0 {
+			continue
+		}
+		userID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		var fields *redis.MapStringStringCmd
+		_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			fields = pipe.HGetAll(ctx, heldKey(userID))
+			pipe.Del(ctx, heldKey(userID))
+			return nil
+		})
+		if err != nil {
+			// Возвращаем получателя в очередь, иначе его уведомления потеряются
+			s.client.ZAdd(ctx, heldDueKey, redis.Z{Score: float64(now.Unix()), Member: id})
+			return due, err
+		}
+		for _, data := range fields.Val() {
+			var msg entity.Message
+			if err := json.Unmarshal([]byte(data), &msg); err != nil {
+				continue
+			}
+			due[userID] = append(due[userID], msg)
+		}
+	}
+	return due, nil
+}
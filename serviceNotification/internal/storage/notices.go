@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"serviceNotification/internal/entity"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// noticesPrefix - список отправленных уведомлений, которые можно отозвать
+const noticesPrefix = "notifications:sent:"
+
+// RedisNoticeStore - отправленные уведомления в Redis: их можно отозвать и после
+// перезапуска, и из другого экземпляра сервиса, а Redis сам удаляет их по TTL
+type RedisNoticeStore struct {
+	client *redis.Client
+}
+
+func NewRedisNoticeStore(addr string) (*RedisNoticeStore, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("ошибка подключения к Redis: %w", err)
+	}
+	return &RedisNoticeStore{client: client}, nil
+}
+
+// Add - добавляет уведомление к ключу и продлевает срок ключа до ttl
+func (s *RedisNoticeStore) Add(ctx context.Context, key string, msg entity.SentMessage, ttl time.Duration) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, noticesPrefix+key, data)
+		pipe.Expire(ctx, noticesPrefix+key, ttl)
+		return nil
+	})
+	return err
+}
+
+// Take - забирает уведомления ключа; второй экземпляр сервиса их уже не получит
+func (s *RedisNoticeStore) Take(ctx context.Context, key string) ([]entity.SentMessage, error) {
+	var values *redis.StringSliceCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		values = pipe.LRange(ctx, noticesPrefix+key, 0, -1)
+		pipe.Del(ctx, noticesPrefix+key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]entity.SentMessage, 0, len(values.Val()))
+	for _, value := range values.Val() {
+		var msg entity.SentMessage
+		if err := json.Unmarshal([]byte(value), &msg); err != nil {
+			return messages, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// cooldownPrefix - ключ паузы напоминаний: задача и получатель
+const cooldownPrefix = "scheduler:cooldown:"
+
+// releaseScript - снимает блокировку, только если она все еще наша
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisSchedulerStore - блокировки задач планировщика и паузы между
+// напоминаниями одному пользователю
+type RedisSchedulerStore struct {
+	client *redis.Client
+}
+
+func NewRedisSchedulerStore(addr string) (*RedisSchedulerStore, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("ошибка подключения к Redis: %w", err)
+	}
+	return &RedisSchedulerStore{client: client}, nil
+}
+
+// Acquire - SET NX со случайным токеном. Блокировка истекает через ttl, даже
+// если экземпляр упал, не сняв ее
+func (s *RedisSchedulerStore) Acquire(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, false, err
+	}
+	token := hex.EncodeToString(buf)
+
+	ok, err := s.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	release := func() {
+		// ctx задачи к этому моменту может быть отменен
+		if err := releaseScript.Run(context.Background(), s.client, []string{key}, token).Err(); err != nil {
+			log.Printf("Не удалось снять блокировку %s: %v", key, err)
+		}
+	}
+	return release, true, nil
+}
+
+func cooldownKey(job string, userID int64) string {
+	return fmt.Sprintf("%s%s:%d", cooldownPrefix, job, userID)
+}
+
+// Claim - ставит паузу напоминаний задачи job пользователю на ttl. false -
+// пауза уже идет
+func (s *RedisSchedulerStore) Claim(ctx context.Context, job string, userID int64, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, cooldownKey(job, userID), time.Now().Unix(), ttl).Result()
+}
+
+// Active - идет ли пауза, без ее установки; для предпросмотра
+func (s *RedisSchedulerStore) Active(ctx context.Context, job string, userID int64) (bool, error) {
+	n, err := s.client.Exists(ctx, cooldownKey(job, userID)).Result()
+	return n > 0, err
+}
+
+// Release - снимает паузу, если напоминание так и не отправилось
+func (s *RedisSchedulerStore) Release(ctx context.Context, job string, userID int64) error {
+	return s.client.Del(ctx, cooldownKey(job, userID)).Err()
+}
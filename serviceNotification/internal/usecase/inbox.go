@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"serviceNotification/internal/entity"
+)
+
+// Ограничения постраничной выдачи входящих
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var (
+	ErrInvalidStatus = errors.New("неизвестный статус уведомления")
+	ErrNoIDs         = errors.New("не указаны уведомления")
+)
+
+// DeliveryLog - журнал доставки: каждое принятое уведомление и его судьба
+type DeliveryLog interface {
+	Record(ctx context.Context, msg entity.Message, status, reason string) (int64, error)
+	SetStatus(ctx context.Context, ids []int64, status, reason string) error
+	CancelQueued(ctx context.Context, userID, fromUserID int64, types []string, reason string) error
+}
+
+// InboxRepository - входящие пользователя для HTTP API
+type InboxRepository interface {
+	List(ctx context.Context, userID int64, status string, page entity.Page) ([]entity.Notification, error)
+	CountUnread(ctx context.Context, userID int64) (int, error)
+	MarkRead(ctx context.Context, userID int64, ids []int64) (int64, error)
+}
+
+// SetDeliveryLog - включает журнал доставки. Без него уведомления
+// отправляются как раньше, но нигде не сохраняются
+func (u *BotUsecase) SetDeliveryLog(deliveryLog DeliveryLog) {
+	u.deliveries = deliveryLog
+}
+
+// record - запись в журнал; ошибка журнала не мешает доставке
+func (u *BotUsecase) record(ctx context.Context, msg entity.Message, status, reason string) int64 {
+	if u.deliveries == nil {
+		return 0
+	}
+	id, err := u.deliveries.Record(ctx, msg, status, reason)
+	if err != nil {
+		log.Printf("Ошибка записи уведомления %s для %d в журнал: %v", msg.Type, msg.ToUserID, err)
+	}
+	return id
+}
+
+// setStatus - итог попытки доставки для уже записанных уведомлений
+func (u *BotUsecase) setStatus(ctx context.Context, status, reason string, messages ...entity.Message) {
+	if u.deliveries == nil {
+		return
+	}
+	ids := make([]int64, 0, len(messages))
+	for _, msg := range messages {
+		if msg.InboxID != 0 {
+			ids = append(ids, msg.InboxID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	if err := u.deliveries.SetStatus(ctx, ids, status, reason); err != nil {
+		log.Printf("Ошибка обновления журнала уведомлений %v: %v", ids, err)
+	}
+}
+
+// logResult - sent или failed с причиной по результату отправки
+func (u *BotUsecase) logResult(ctx context.Context, err error, messages ...entity.Message) {
+	if err == nil {
+		u.setStatus(ctx, entity.StatusSent, "", messages...)
+		return
+	}
+	reason := err.Error()
+	var unreachable *entity.UnreachableError
+	if errors.As(err, &unreachable) {
+		reason = unreachable.Reason
+	}
+	u.setStatus(ctx, entity.StatusFailed, reason, messages...)
+}
+
+func (u *BotUsecase) cancelQueued(ctx context.Context, userID, fromUserID int64, reason string, types ...string) {
+	if u.deliveries == nil {
+		return
+	}
+	if err := u.deliveries.CancelQueued(ctx, userID, fromUserID, types, reason); err != nil {
+		log.Printf("Ошибка обновления журнала уведомлений %d: %v", userID, err)
+	}
+}
+
+// InboxUsecase - входящие пользователя: история уведомлений и отметки о прочтении
+type InboxUsecase struct {
+	repo InboxRepository
+}
+
+func NewInboxUsecase(repo InboxRepository) *InboxUsecase {
+	return &InboxUsecase{repo: repo}
+}
+
+// NormalizePage - подставляет лимит по умолчанию и обрезает слишком большие значения
+func NormalizePage(page entity.Page) entity.Page {
+	if page.Limit <= 0 {
+		page.Limit = DefaultPageLimit
+	}
+	if page.Limit > MaxPageLimit {
+		page.Limit = MaxPageLimit
+	}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+	return page
+}
+
+// List - уведомления пользователя, новые сверху, и число непрочитанных.
+// Пустой status - все статусы
+func (uc *InboxUsecase) List(ctx context.Context, userID int64, status string, page entity.Page) (items []entity.Notification, hasMore bool, unread int, err error) {
+	switch status {
+	case "", entity.StatusQueued, entity.StatusSent, entity.StatusFailed, entity.StatusRead:
+	default:
+		return nil, false, 0, fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+	}
+	page = NormalizePage(page)
+
+	// Запрашиваем на одну запись больше, чтобы узнать о следующей странице
+	items, err = uc.repo.List(ctx, userID, status, entity.Page{Limit: page.Limit + 1, Offset: page.Offset})
+	if err != nil {
+		return nil, false, 0, fmt.Errorf("failed to load notifications: %w", err)
+	}
+	if len(items) > page.Limit {
+		items, hasMore = items[:page.Limit], true
+	}
+	unread, err = uc.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, false, 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return items, hasMore, unread, nil
+}
+
+// MarkRead - отмечает прочитанными уведомления ids. Повторная отметка ничего не меняет
+func (uc *InboxUsecase) MarkRead(ctx context.Context, userID int64, ids []int64) (updated int64, unread int, err error) {
+	if len(ids) == 0 {
+		return 0, 0, ErrNoIDs
+	}
+	return uc.markRead(ctx, userID, ids)
+}
+
+// MarkAllRead - отмечает прочитанными все доставленные уведомления
+func (uc *InboxUsecase) MarkAllRead(ctx context.Context, userID int64) (updated int64, unread int, err error) {
+	return uc.markRead(ctx, userID, nil)
+}
+
+func (uc *InboxUsecase) markRead(ctx context.Context, userID int64, ids []int64) (int64, int, error) {
+	updated, err := uc.repo.MarkRead(ctx, userID, ids)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	unread, err := uc.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return updated, unread, nil
+}
@@ -0,0 +1,347 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"serviceNotification/internal/entity"
+	"testing"
+	"time"
+
+	"gopkg.in/telebot.v4"
+)
+
+type fakeSender struct {
+	err     error
+	sent    []entity.Message
+	digests []map[string]int
+}
+
+func (s *fakeSender) SendMessage(msg entity.Message) error {
+	s.sent = append(s.sent, msg)
+	return s.err
+}
+
+func (s *fakeSender) SendDigest(_ int64, counts map[string]int) error {
+	s.digests = append(s.digests, counts)
+	return s.err
+}
+
+type fakeUsers struct {
+	user     entity.User
+	settings entity.NotificationSettings
+}
+
+func (u *fakeUsers) GetUserByID(int64) (*entity.User, error) {
+	return &u.user, nil
+}
+
+func (u *fakeUsers) GetNotificationSettings(int64) (*entity.NotificationSettings, error) {
+	return &u.settings, nil
+}
+
+type logEntry struct {
+	msg    entity.Message
+	status string
+	reason string
+}
+
+// fakeLog - журнал доставки в памяти; ID записи - индекс в entries плюс один
+type fakeLog struct {
+	entries []*logEntry
+}
+
+func (l *fakeLog) Record(_ context.Context, msg entity.Message, status, reason string) (int64, error) {
+	for i, e := range l.entries {
+		if msg.EventKey != "" && e.msg.EventKey == msg.EventKey && e.msg.ToUserID == msg.ToUserID {
+			if e.status != entity.StatusSent && e.status != entity.StatusRead {
+				e.status, e.reason = status, reason
+			}
+			return int64(i + 1), nil
+		}
+	}
+	l.entries = append(l.entries, &logEntry{msg: msg, status: status, reason: reason})
+	return int64(len(l.entries)), nil
+}
+
+func (l *fakeLog) SetStatus(_ context.Context, ids []int64, status, reason string) error {
+	for _, id := range ids {
+		l.entries[id-1].status, l.entries[id-1].reason = status, reason
+	}
+	return nil
+}
+
+func (l *fakeLog) CancelQueued(_ context.Context, userID, fromUserID int64, types []string, reason string) error {
+	for _, e := range l.entries {
+		for _, t := range types {
+			if e.status == entity.StatusQueued && e.msg.ToUserID == userID && e.msg.FromUserID == fromUserID && e.msg.Type == t {
+				e.status, e.reason = entity.StatusFailed, reason
+			}
+		}
+	}
+	return nil
+}
+
+type memHeld struct {
+	held map[int64]map[string]entity.Message
+	// lastAt - время отправки, с которым отложено последнее уведомление
+	lastAt time.Time
+}
+
+func (h *memHeld) Hold(_ context.Context, key string, msg entity.Message, at time.Time) error {
+	h.lastAt = at
+	if h.held == nil {
+		h.held = make(map[int64]map[string]entity.Message)
+	}
+	if h.held[msg.ToUserID] == nil {
+		h.held[msg.ToUserID] = make(map[string]entity.Message)
+	}
+	h.held[msg.ToUserID][key] = msg
+	return nil
+}
+
+func (h *memHeld) Cancel(_ context.Context, userID int64, keys ...string) error {
+	for _, key := range keys {
+		delete(h.held[userID], key)
+	}
+	return nil
+}
+
+func (h *memHeld) TakeDue(context.Context, time.Time) (map[int64][]entity.Message, error) {
+	due := make(map[int64][]entity.Message)
+	for userID, messages := range h.held {
+		for _, msg := range messages {
+			due[userID] = append(due[userID], msg)
+		}
+	}
+	h.held = nil
+	return due, nil
+}
+
+func newLoggedUsecase(sender *fakeSender, users *fakeUsers, held HeldStore) (*BotUsecase, *fakeLog) {
+	deliveries := &fakeLog{}
+	uc := NewBotUsecase(sender, users, held, nil, time.Hour)
+	uc.SetDeliveryLog(deliveries)
+	return uc, deliveries
+}
+
+func TestDeliveryLog_Sent(t *testing.T) {
+	sender := &fakeSender{}
+	uc, deliveries := newLoggedUsecase(sender, &fakeUsers{}, nil)
+
+	if err := uc.SendMessage(entity.Message{Type: entity.NotifyLike, FromUserID: 1, ToUserID: 2, Comment: "привет"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries.entries) != 1 || deliveries.entries[0].status != entity.StatusSent {
+		t.Fatalf("expected one sent entry, got %+v", deliveries.entries)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].InboxID != 1 {
+		t.Fatalf("sender must get the inbox ID, got %+v", sender.sent)
+	}
+}
+
+func TestDeliveryLog_Failed(t *testing.T) {
+	t.Run("transient error is returned for retry", func(t *testing.T) {
+		sender := &fakeSender{err: errors.New("timeout")}
+		uc, deliveries := newLoggedUsecase(sender, &fakeUsers{}, nil)
+
+		err := uc.SendMessage(entity.Message{Type: entity.NotifyProfileApproved, ToUserID: 2})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if e := deliveries.entries[0]; e.status != entity.StatusFailed || e.reason != "timeout" {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+	})
+
+	t.Run("retry of the same event keeps one entry", func(t *testing.T) {
+		sender := &fakeSender{err: errors.New("timeout")}
+		uc, deliveries := newLoggedUsecase(sender, &fakeUsers{}, nil)
+		msg := entity.Message{Type: entity.NotifyMatch, FromUserID: 1, ToUserID: 2, EventKey: "likes/0/42"}
+
+		for i := 0; i < 3; i++ {
+			if err := uc.Deliver(msg); err == nil {
+				t.Fatal("expected error")
+			}
+		}
+		sender.err = nil
+		if err := uc.Deliver(msg); err != nil {
+			t.Fatal(err)
+		}
+		if len(deliveries.entries) != 1 || deliveries.entries[0].status != entity.StatusSent {
+			t.Fatalf("expected one sent entry, got %+v", deliveries.entries)
+		}
+
+		// Повтор уже доставленного события не возвращает запись в очередь
+		if _, err := deliveries.Record(context.Background(), msg, entity.StatusQueued, ""); err != nil {
+			t.Fatal(err)
+		}
+		if deliveries.entries[0].status != entity.StatusSent {
+			t.Fatalf("sent entry must stay sent, got %+v", deliveries.entries[0])
+		}
+	})
+
+	t.Run("permanent error is recorded with its reason", func(t *testing.T) {
+		sender := &fakeSender{err: &entity.UnreachableError{UserID: 2, Reason: entity.FailureBlocked, Err: telebot.ErrBlockedByUser}}
+		uc, deliveries := newLoggedUsecase(sender, &fakeUsers{}, nil)
+
+		if err := uc.SendMessage(entity.Message{Type: entity.NotifyProfileApproved, ToUserID: 2}); err != nil {
+			t.Fatal(err)
+		}
+		if e := deliveries.entries[0]; e.status != entity.StatusFailed || e.reason != entity.FailureBlocked {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+	})
+
+	t.Run("known unreachable user is not contacted", func(t *testing.T) {
+		sender := &fakeSender{}
+		now := time.Now()
+		uc, deliveries := newLoggedUsecase(sender, &fakeUsers{user: entity.User{UnreachableAt: &now}}, nil)
+
+		if err := uc.SendMessage(entity.Message{Type: entity.NotifyProfileApproved, ToUserID: 2}); err != nil {
+			t.Fatal(err)
+		}
+		if len(sender.sent) != 0 {
+			t.Fatal("message must not be sent")
+		}
+		if e := deliveries.entries[0]; e.status != entity.StatusFailed || e.reason != entity.SkipUnreachable {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+	})
+}
+
+func TestDeliveryLog_Disabled(t *testing.T) {
+	users := &fakeUsers{settings: entity.NotificationSettings{Disabled: []string{entity.NotifyLike}}}
+	uc, deliveries := newLoggedUsecase(&fakeSender{}, users, nil)
+
+	if err := uc.SendMessage(entity.Message{Type: entity.NotifyLike, FromUserID: 1, ToUserID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries.entries) != 0 {
+		t.Fatalf("disabled notification must not be logged, got %+v", deliveries.entries)
+	}
+}
+
+func TestDeliveryLog_Held(t *testing.T) {
+	digest := entity.NotificationSettings{Mode: entity.ModeDigest}
+
+	t.Run("queued until released in a digest", func(t *testing.T) {
+		sender := &fakeSender{}
+		held := &memHeld{}
+		uc, deliveries := newLoggedUsecase(sender, &fakeUsers{settings: digest}, held)
+
+		for _, from := range []int64{1, 3} {
+			if err := uc.SendMessage(entity.Message{Type: entity.NotifyLike, FromUserID: from, ToUserID: 2}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, e := range deliveries.entries {
+			if e.status != entity.StatusQueued {
+				t.Fatalf("expected queued, got %+v", e)
+			}
+		}
+
+		uc.releaseDue(context.Background())
+		if len(sender.digests) != 1 {
+			t.Fatalf("expected one digest, got %v", sender.digests)
+		}
+		for _, e := range deliveries.entries {
+			if e.status != entity.StatusSent {
+				t.Fatalf("expected sent, got %+v", e)
+			}
+		}
+	})
+
+	t.Run("repeated like replaces the queued one", func(t *testing.T) {
+		uc, deliveries := newLoggedUsecase(&fakeSender{}, &fakeUsers{settings: digest}, &memHeld{})
+
+		for range 2 {
+			if err := uc.SendMessage(entity.Message{Type: entity.NotifyLike, FromUserID: 1, ToUserID: 2}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if e := deliveries.entries[0]; e.status != entity.StatusFailed || e.reason != entity.SkipReplaced {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+		if e := deliveries.entries[1]; e.status != entity.StatusQueued {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+	})
+
+	t.Run("undone like is cancelled", func(t *testing.T) {
+		uc, deliveries := newLoggedUsecase(&fakeSender{}, &fakeUsers{settings: digest}, &memHeld{})
+
+		if err := uc.SendMessage(entity.Message{Type: entity.NotifyLike, FromUserID: 1, ToUserID: 2}); err != nil {
+			t.Fatal(err)
+		}
+		if err := uc.SendMessage(entity.Message{Type: entity.NotifyLikeUndone, FromUserID: 1, ToUserID: 2}); err != nil {
+			t.Fatal(err)
+		}
+		if e := deliveries.entries[0]; e.status != entity.StatusFailed || e.reason != entity.SkipCancelled {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+	})
+}
+
+type fakeInboxRepo struct {
+	items  []entity.Notification
+	page   entity.Page
+	marked []int64
+}
+
+func (r *fakeInboxRepo) List(_ context.Context, _ int64, _ string, page entity.Page) ([]entity.Notification, error) {
+	r.page = page
+	end := min(page.Offset+page.Limit, len(r.items))
+	return r.items[min(page.Offset, end):end], nil
+}
+
+func (r *fakeInboxRepo) CountUnread(context.Context, int64) (int, error) {
+	return 3, nil
+}
+
+func (r *fakeInboxRepo) MarkRead(_ context.Context, _ int64, ids []int64) (int64, error) {
+	r.marked = ids
+	return int64(len(ids)), nil
+}
+
+func TestInboxUsecase_List(t *testing.T) {
+	repo := &fakeInboxRepo{items: make([]entity.Notification, 5)}
+	uc := NewInboxUsecase(repo)
+
+	items, hasMore, unread, err := uc.List(context.Background(), 1, "", entity.Page{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || !hasMore || unread != 3 {
+		t.Fatalf("got %d items, hasMore=%v, unread=%d", len(items), hasMore, unread)
+	}
+	if repo.page != (entity.Page{Limit: 3, Offset: 2}) {
+		t.Fatalf("one extra item must be requested, got %+v", repo.page)
+	}
+
+	items, hasMore, _, _ = uc.List(context.Background(), 1, entity.StatusSent, entity.Page{Offset: 4})
+	if len(items) != 1 || hasMore {
+		t.Fatalf("last page: got %d items, hasMore=%v", len(items), hasMore)
+	}
+
+	if _, _, _, err := uc.List(context.Background(), 1, "deleted", entity.Page{}); !errors.Is(err, ErrInvalidStatus) {
+		t.Fatalf("expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestInboxUsecase_MarkRead(t *testing.T) {
+	repo := &fakeInboxRepo{}
+	uc := NewInboxUsecase(repo)
+
+	if _, _, err := uc.MarkRead(context.Background(), 1, nil); !errors.Is(err, ErrNoIDs) {
+		t.Fatalf("expected ErrNoIDs, got %v", err)
+	}
+
+	updated, unread, err := uc.MarkRead(context.Background(), 1, []int64{4, 5})
+	if err != nil || updated != 2 || unread != 3 {
+		t.Fatalf("got updated=%d unread=%d err=%v", updated, unread, err)
+	}
+
+	if _, _, err := uc.MarkAllRead(context.Background(), 1); err != nil || repo.marked != nil {
+		t.Fatalf("mark all must pass no IDs, got %v err=%v", repo.marked, err)
+	}
+}
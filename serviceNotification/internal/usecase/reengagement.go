@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"serviceNotification/internal/entity"
+	"time"
+)
+
+// Задачи планировщика, возвращающие пользователей в бота
+const (
+	JobUnansweredLikes = "unanswered_likes"
+	JobNewInCity       = "new_in_city"
+)
+
+// reengagementPage - сколько кандидатов запрашивается за раз; previewLimit -
+// сколько получателей показывается в предпросмотре
+const (
+	reengagementPage = 100
+	previewLimit     = 100
+)
+
+// MatchClient - неотвеченные лайки из serviceMatch
+type MatchClient interface {
+	UnansweredLikes(olderThan time.Duration, limit, offset int) ([]entity.PendingLikes, bool, error)
+}
+
+// InactiveUsersClient - давно не заходившие пользователи из serviceUser
+type InactiveUsersClient interface {
+	InactiveUsers(inactiveFor time.Duration, limit, offset int) ([]entity.InactiveUser, bool, error)
+}
+
+// CooldownStore - паузы между напоминаниями одной задачи одному пользователю
+type CooldownStore interface {
+	Claim(ctx context.Context, job string, userID int64, ttl time.Duration) (bool, error)
+	Active(ctx context.Context, job string, userID int64) (bool, error)
+	Release(ctx context.Context, job string, userID int64) error
+}
+
+// ReengagementConfig - пороги и паузы напоминаний
+type ReengagementConfig struct {
+	// LikesAfter - сколько лайк должен ждать ответа, чтобы о нем напомнить
+	LikesAfter    time.Duration
+	LikesCooldown time.Duration
+	// InactiveFor - после скольких дней без визитов напоминать о новых анкетах
+	InactiveFor      time.Duration
+	InactiveCooldown time.Duration
+}
+
+// Reengagement - задачи планировщика: напоминания о неотвеченных лайках и о
+// новых анкетах в городе. Уведомления уходят через BotUsecase, поэтому тихие
+// часы, сводки и журнал доставки работают как для обычных уведомлений
+type Reengagement struct {
+	bot       *BotUsecase
+	matches   MatchClient
+	users     InactiveUsersClient
+	cooldowns CooldownStore
+	cfg       ReengagementConfig
+}
+
+func NewReengagement(bot *BotUsecase, matches MatchClient, users InactiveUsersClient, cooldowns CooldownStore, cfg ReengagementConfig) *Reengagement {
+	return &Reengagement{bot: bot, matches: matches, users: users, cooldowns: cooldowns, cfg: cfg}
+}
+
+// UnansweredLikes - «У вас N лайков без ответа» тем, кто не отвечает дольше LikesAfter
+func (r *Reengagement) UnansweredLikes(ctx context.Context, dryRun bool) (entity.JobReport, error) {
+	report := r.newReport(JobUnansweredLikes, dryRun)
+	for offset := 0; ; offset += reengagementPage {
+		items, hasMore, err := r.matches.UnansweredLikes(r.cfg.LikesAfter, reengagementPage, offset)
+		if err != nil {
+			return report, fmt.Errorf("failed to load unanswered likes: %w", err)
+		}
+		for _, item := range items {
+			msg := entity.Message{Type: entity.NotifyUnansweredLikes, ToUserID: item.UserID, Count: item.Count}
+			if err := r.remind(ctx, &report, msg, r.cfg.LikesCooldown); err != nil {
+				return report, err
+			}
+		}
+		if !hasMore {
+			break
+		}
+	}
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// NewInCity - «В твоем городе N новых анкет» тем, кто не заходил дольше InactiveFor
+func (r *Reengagement) NewInCity(ctx context.Context, dryRun bool) (entity.JobReport, error) {
+	report := r.newReport(JobNewInCity, dryRun)
+	for offset := 0; ; offset += reengagementPage {
+		items, hasMore, err := r.users.InactiveUsers(r.cfg.InactiveFor, reengagementPage, offset)
+		if err != nil {
+			return report, fmt.Errorf("failed to load inactive users: %w", err)
+		}
+		for _, item := range items {
+			msg := entity.Message{Type: entity.NotifyNewInCity, ToUserID: item.TelegramID, Count: item.NewProfiles, City: item.City}
+			if err := r.remind(ctx, &report, msg, r.cfg.InactiveCooldown); err != nil {
+				return report, err
+			}
+		}
+		if !hasMore {
+			break
+		}
+	}
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+func (r *Reengagement) newReport(job string, dryRun bool) entity.JobReport {
+	return entity.JobReport{Job: job, DryRun: dryRun, StartedAt: time.Now()}
+}
+
+// remind - проверяет настройки и паузу получателя и отправляет напоминание.
+// Ошибка возвращается, только если продолжать задачу бессмысленно
+func (r *Reengagement) remind(ctx context.Context, report *entity.JobReport, msg entity.Message, cooldown time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	report.Candidates++
+	job := report.Job
+
+	if !r.bot.settingsFor(msg.ToUserID).Enabled(preference(msg.Type)) {
+		report.Skip(entity.JobSkipDisabled)
+		return nil
+	}
+
+	if report.DryRun {
+		active, err := r.cooldowns.Active(ctx, job, msg.ToUserID)
+		if err != nil {
+			return fmt.Errorf("failed to check cooldown: %w", err)
+		}
+		if active {
+			report.Skip(entity.JobSkipCooldown)
+			return nil
+		}
+		if len(report.Targets) < previewLimit {
+			report.Targets = append(report.Targets, entity.JobTarget{UserID: msg.ToUserID, Count: msg.Count, City: msg.City})
+		}
+		report.Sent++
+		return nil
+	}
+
+	claimed, err := r.cooldowns.Claim(ctx, job, msg.ToUserID, cooldown)
+	if err != nil {
+		return fmt.Errorf("failed to claim cooldown: %w", err)
+	}
+	if !claimed {
+		report.Skip(entity.JobSkipCooldown)
+		return nil
+	}
+	if err := r.bot.SendMessage(msg); err != nil {
+		log.Printf("Напоминание %s для %d не отправлено: %v", msg.Type, msg.ToUserID, err)
+		report.Failed++
+		// Пауза не должна мешать следующей попытке
+		if err := r.cooldowns.Release(ctx, job, msg.ToUserID); err != nil {
+			log.Printf("Не удалось снять паузу напоминаний %s для %d: %v", job, msg.ToUserID, err)
+		}
+		return nil
+	}
+	report.Sent++
+	return nil
+}
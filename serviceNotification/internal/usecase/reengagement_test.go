@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"serviceNotification/internal/entity"
+	"testing"
+	"time"
+)
+
+type fakeMatches struct {
+	pages [][]entity.PendingLikes
+	calls []int
+}
+
+func (m *fakeMatches) UnansweredLikes(_ time.Duration, _, offset int) ([]entity.PendingLikes, bool, error) {
+	m.calls = append(m.calls, offset)
+	page := offset / reengagementPage
+	return m.pages[page], page+1 < len(m.pages), nil
+}
+
+type fakeInactive struct {
+	users []entity.InactiveUser
+	err   error
+}
+
+func (u *fakeInactive) InactiveUsers(time.Duration, int, int) ([]entity.InactiveUser, bool, error) {
+	return u.users, false, u.err
+}
+
+type memCooldowns struct {
+	active   map[int64]bool
+	released []int64
+}
+
+func (c *memCooldowns) Claim(_ context.Context, _ string, userID int64, _ time.Duration) (bool, error) {
+	if c.active[userID] {
+		return false, nil
+	}
+	if c.active == nil {
+		c.active = make(map[int64]bool)
+	}
+	c.active[userID] = true
+	return true, nil
+}
+
+func (c *memCooldowns) Active(_ context.Context, _ string, userID int64) (bool, error) {
+	return c.active[userID], nil
+}
+
+func (c *memCooldowns) Release(_ context.Context, _ string, userID int64) error {
+	delete(c.active, userID)
+	c.released = append(c.released, userID)
+	return nil
+}
+
+// perUserSettings - настройки отдельных пользователей, у остальных по умолчанию
+type perUserSettings struct {
+	fakeUsers
+	byUser map[int64]entity.NotificationSettings
+}
+
+func (u *perUserSettings) GetNotificationSettings(userID int64) (*entity.NotificationSettings, error) {
+	settings := u.byUser[userID]
+	return &settings, nil
+}
+
+var testReengagement = ReengagementConfig{
+	LikesAfter:       24 * time.Hour,
+	LikesCooldown:    72 * time.Hour,
+	InactiveFor:      7 * 24 * time.Hour,
+	InactiveCooldown: 7 * 24 * time.Hour,
+}
+
+func TestReengagement_UnansweredLikes(t *testing.T) {
+	sender := &fakeSender{}
+	users := &perUserSettings{byUser: map[int64]entity.NotificationSettings{
+		3: {Disabled: []string{entity.NotifyInactivity}},
+	}}
+	bot := NewBotUsecase(sender, users, nil, nil, time.Hour)
+	matches := &fakeMatches{pages: [][]entity.PendingLikes{
+		make([]entity.PendingLikes, 0, reengagementPage),
+		{{UserID: 3, Count: 1}, {UserID: 4, Count: 7}},
+	}}
+	matches.pages[0] = append(matches.pages[0], entity.PendingLikes{UserID: 1, Count: 2}, entity.PendingLikes{UserID: 2, Count: 5})
+	cooldowns := &memCooldowns{active: map[int64]bool{2: true}}
+	r := NewReengagement(bot, matches, nil, cooldowns, testReengagement)
+
+	preview, err := r.UnansweredLikes(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sender.sent) != 0 {
+		t.Fatal("preview must not send anything")
+	}
+	if preview.Candidates != 4 || preview.Sent != 2 || preview.Skipped[entity.JobSkipCooldown] != 1 || preview.Skipped[entity.JobSkipDisabled] != 1 {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+	if len(preview.Targets) != 2 || preview.Targets[1] != (entity.JobTarget{UserID: 4, Count: 7}) {
+		t.Fatalf("unexpected targets %+v", preview.Targets)
+	}
+	if cooldowns.active[1] {
+		t.Fatal("preview must not claim cooldowns")
+	}
+
+	report, err := r.UnansweredLikes(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Sent != 2 || len(report.Targets) != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if len(sender.sent) != 2 || sender.sent[0].Type != entity.NotifyUnansweredLikes || sender.sent[1].Count != 7 {
+		t.Fatalf("unexpected messages %+v", sender.sent)
+	}
+	if matches.calls[1] != reengagementPage {
+		t.Fatalf("second page must be requested, got offsets %v", matches.calls)
+	}
+
+	// Повторный запуск упирается в паузу
+	report, _ = r.UnansweredLikes(context.Background(), false)
+	if report.Sent != 0 || report.Skipped[entity.JobSkipCooldown] != 3 {
+		t.Fatalf("cooldown is not respected: %+v", report)
+	}
+}
+
+func TestReengagement_NewInCity(t *testing.T) {
+	t.Run("failed send releases cooldown", func(t *testing.T) {
+		sender := &fakeSender{err: errors.New("timeout")}
+		cooldowns := &memCooldowns{}
+		inactive := &fakeInactive{users: []entity.InactiveUser{{TelegramID: 1, City: "Казань", NewProfiles: 4}}}
+		r := NewReengagement(NewBotUsecase(sender, &fakeUsers{}, nil, nil, time.Hour), nil, inactive, cooldowns, testReengagement)
+
+		report, err := r.NewInCity(context.Background(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Failed != 1 || report.Sent != 0 {
+			t.Fatalf("unexpected report %+v", report)
+		}
+		if sender.sent[0].City != "Казань" || sender.sent[0].Type != entity.NotifyNewInCity {
+			t.Fatalf("unexpected message %+v", sender.sent[0])
+		}
+		if cooldowns.active[1] || len(cooldowns.released) != 1 {
+			t.Fatal("cooldown must be released after a failed send")
+		}
+	})
+
+	t.Run("serviceUser is down", func(t *testing.T) {
+		inactive := &fakeInactive{err: errors.New("connection refused")}
+		r := NewReengagement(NewBotUsecase(&fakeSender{}, &fakeUsers{}, nil, nil, time.Hour), nil, inactive, &memCooldowns{}, testReengagement)
+
+		if _, err := r.NewInCity(context.Background(), false); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestPreference(t *testing.T) {
+	if preference(entity.NotifyNewInCity) != entity.NotifyInactivity || preference(entity.NotifyLike) != entity.NotifyLike {
+		t.Fatal("reminders must follow the inactivity_reminder switch")
+	}
+	if err := validate(entity.Message{Type: entity.NotifyUnansweredLikes, ToUserID: 1}); !errors.Is(err, ErrInvalidNotification) {
+		t.Fatalf("reminder without count must be invalid, got %v", err)
+	}
+}
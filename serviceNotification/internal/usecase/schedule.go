@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"serviceNotification/internal/entity"
+	"time"
+)
+
+// defaultTimeZone - часовой пояс, если в настройках неизвестный
+const defaultTimeZone = "Europe/Moscow"
+
+func location(s entity.NotificationSettings) *time.Location {
+	if loc, err := time.LoadLocation(s.TimeZone); err == nil && s.TimeZone != "" {
+		return loc
+	}
+	loc, err := time.LoadLocation(defaultTimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// quietUntil - если сейчас тихие часы пользователя, возвращает их конец
+func quietUntil(s entity.NotificationSettings, now time.Time) (time.Time, bool) {
+	if s.QuietFrom == nil || s.QuietTo == nil || *s.QuietFrom == *s.QuietTo {
+		return time.Time{}, false
+	}
+	from, to := *s.QuietFrom, *s.QuietTo
+	local := now.In(location(s))
+	h := local.Hour()
+
+	quiet := h >= from && h < to
+	if from > to {
+		quiet = h >= from || h < to
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+	end := time.Date(local.Year(), local.Month(), local.Day(), to, 0, 0, 0, local.Location())
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end, true
+}
+
+// nextDigest - конец текущего окна сводки. Окна идут по местному времени от
+// полуночи с шагом every; если окно заканчивается в тихие часы, сводка ждет их конца
+func nextDigest(s entity.NotificationSettings, now time.Time, every time.Duration) time.Time {
+	local := now.In(location(s))
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	end := midnight.AddDate(0, 0, 1)
+	if every > 0 {
+		if next := midnight.Add((local.Sub(midnight)/every + 1) * every); next.Before(end) {
+			end = next
+		}
+	}
+	if until, ok := quietUntil(s, end); ok {
+		return until
+	}
+	return end
+}
+
+// deliverAt - когда отправить уведомление; false - отправить сразу
+func deliverAt(s entity.NotificationSettings, now time.Time, digestEvery time.Duration) (time.Time, bool) {
+	if s.Mode == entity.ModeDigest {
+		return nextDigest(s, now, digestEvery), true
+	}
+	return quietUntil(s, now)
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"serviceNotification/internal/entity"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// moscow - время по Москве (UTC+3, без перехода на летнее время)
+func moscow(t *testing.T, day, hour, minute int) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation(defaultTimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return time.Date(2026, time.March, day, hour, minute, 0, 0, loc)
+}
+
+func hours(from, to int) entity.NotificationSettings {
+	return entity.NotificationSettings{QuietFrom: &from, QuietTo: &to, TimeZone: defaultTimeZone}
+}
+
+func TestQuietUntil(t *testing.T) {
+	tests := []struct {
+		name      string
+		settings  entity.NotificationSettings
+		now       time.Time
+		wantQuiet bool
+		want      time.Time
+	}{
+		{name: "no quiet hours", settings: entity.NotificationSettings{}, now: moscow(t, 10, 23, 0)},
+		{name: "empty window", settings: hours(8, 8), now: moscow(t, 10, 8, 30)},
+		{name: "inside daytime window", settings: hours(13, 15), now: moscow(t, 10, 14, 10), wantQuiet: true, want: moscow(t, 10, 15, 0)},
+		{name: "daytime window end is exclusive", settings: hours(13, 15), now: moscow(t, 10, 15, 0)},
+		{name: "wrap before midnight", settings: hours(22, 7), now: moscow(t, 10, 23, 30), wantQuiet: true, want: moscow(t, 11, 7, 0)},
+		{name: "wrap start is inclusive", settings: hours(22, 7), now: moscow(t, 10, 22, 0), wantQuiet: true, want: moscow(t, 11, 7, 0)},
+		{name: "wrap after midnight", settings: hours(22, 7), now: moscow(t, 11, 3, 0), wantQuiet: true, want: moscow(t, 11, 7, 0)},
+		{name: "wrap end is exclusive", settings: hours(22, 7), now: moscow(t, 11, 7, 0)},
+		{name: "outside wrap", settings: hours(22, 7), now: moscow(t, 10, 12, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, quiet := quietUntil(tt.settings, tt.now)
+			if quiet != tt.wantQuiet || !got.Equal(tt.want) {
+				t.Fatalf("quietUntil = %v, %v; want %v, %v", got, quiet, tt.want, tt.wantQuiet)
+			}
+		})
+	}
+}
+
+func TestQuietUntil_TimeZone(t *testing.T) {
+	// 14:00 UTC - это 23:00 в Токио и 17:00 в Москве
+	now := time.Date(2026, time.March, 10, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		zone      string
+		wantQuiet bool
+		want      time.Time
+	}{
+		{name: "user zone", zone: "Asia/Tokyo", wantQuiet: true, want: time.Date(2026, time.March, 10, 22, 0, 0, 0, time.UTC)},
+		{name: "unknown zone falls back to Moscow", zone: "Mars/Olympus"},
+		{name: "empty zone falls back to Moscow", zone: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := hours(22, 7)
+			settings.TimeZone = tt.zone
+			got, quiet := quietUntil(settings, now)
+			if quiet != tt.wantQuiet || !got.Equal(tt.want) {
+				t.Fatalf("quietUntil = %v, %v; want %v, %v", got, quiet, tt.want, tt.wantQuiet)
+			}
+		})
+	}
+
+	// Для запасного пояса тихие часы тоже считаются по Москве: 20:00 UTC - 23:00 МСК
+	settings := hours(22, 7)
+	settings.TimeZone = "Mars/Olympus"
+	got, quiet := quietUntil(settings, now.Add(6*time.Hour))
+	if !quiet || !got.Equal(moscow(t, 11, 7, 0)) {
+		t.Fatalf("fallback quietUntil = %v, %v; want %v", got, quiet, moscow(t, 11, 7, 0))
+	}
+}
+
+func TestNextDigest(t *testing.T) {
+	plain := entity.NotificationSettings{TimeZone: defaultTimeZone}
+
+	tests := []struct {
+		name     string
+		settings entity.NotificationSettings
+		now      time.Time
+		every    time.Duration
+		want     time.Time
+	}{
+		{name: "inside window", settings: plain, now: moscow(t, 10, 10, 15), every: 3 * time.Hour, want: moscow(t, 10, 12, 0)},
+		{name: "on window boundary", settings: plain, now: moscow(t, 10, 12, 0), every: 3 * time.Hour, want: moscow(t, 10, 15, 0)},
+		{name: "last window ends at midnight", settings: plain, now: moscow(t, 10, 22, 30), every: 3 * time.Hour, want: moscow(t, 11, 0, 0)},
+		{name: "uneven window is cut at midnight", settings: plain, now: moscow(t, 10, 21, 0), every: 5 * time.Hour, want: moscow(t, 11, 0, 0)},
+		{name: "no window means daily", settings: plain, now: moscow(t, 10, 9, 0), want: moscow(t, 11, 0, 0)},
+		{name: "window ending in quiet hours waits", settings: hours(23, 8), now: moscow(t, 10, 22, 30), every: 3 * time.Hour, want: moscow(t, 11, 8, 0)},
+		{name: "window ending before quiet hours", settings: hours(23, 8), now: moscow(t, 10, 19, 0), every: 3 * time.Hour, want: moscow(t, 10, 21, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextDigest(tt.settings, tt.now, tt.every); !got.Equal(tt.want) {
+				t.Fatalf("nextDigest = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliverAt(t *testing.T) {
+	digest := hours(22, 7)
+	digest.Mode = entity.ModeDigest
+	instant := hours(22, 7)
+	instant.Mode = entity.ModeInstant
+
+	tests := []struct {
+		name     string
+		settings entity.NotificationSettings
+		now      time.Time
+		wantHold bool
+		want     time.Time
+	}{
+		{name: "instant outside quiet hours", settings: instant, now: moscow(t, 10, 12, 0)},
+		{name: "instant in quiet hours", settings: instant, now: moscow(t, 10, 23, 0), wantHold: true, want: moscow(t, 11, 7, 0)},
+		{name: "digest is always held", settings: digest, now: moscow(t, 10, 12, 0), wantHold: true, want: moscow(t, 10, 15, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hold := deliverAt(tt.settings, tt.now, 3*time.Hour)
+			if hold != tt.wantHold || !got.Equal(tt.want) {
+				t.Fatalf("deliverAt = %v, %v; want %v, %v", got, hold, tt.want, tt.wantHold)
+			}
+		})
+	}
+}
+
+func TestDeliver_HoldsInQuietHours(t *testing.T) {
+	sender := &fakeSender{}
+	held := &memHeld{}
+	uc, deliveries := newLoggedUsecase(sender, &fakeUsers{settings: hours(22, 7)}, held)
+	uc.now = func() time.Time { return moscow(t, 10, 23, 30) }
+
+	if err := uc.SendMessage(entity.Message{Type: entity.NotifyLike, FromUserID: 1, ToUserID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if len(sender.sent) != 0 {
+		t.Fatalf("message must wait for the end of quiet hours, sent %+v", sender.sent)
+	}
+	if !held.lastAt.Equal(moscow(t, 11, 7, 0)) {
+		t.Fatalf("held until %v, want %v", held.lastAt, moscow(t, 11, 7, 0))
+	}
+	if len(deliveries.entries) != 1 || deliveries.entries[0].status != entity.StatusQueued {
+		t.Fatalf("expected one queued entry, got %+v", deliveries.entries)
+	}
+
+	// Модерация не откладывается даже в тихие часы
+	if err := uc.SendMessage(entity.Message{Type: entity.NotifyProfileApproved, ToUserID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("moderation must be sent immediately, sent %+v", sender.sent)
+	}
+}
+
+func TestRelease_RequeuesFailed(t *testing.T) {
+	now := moscow(t, 11, 7, 0)
+	digest := entity.NotificationSettings{Mode: entity.ModeDigest, TimeZone: defaultTimeZone}
+
+	t.Run("single notification", func(t *testing.T) {
+		sender := &fakeSender{err: errors.New("timeout")}
+		held := &memHeld{}
+		uc, deliveries := newLoggedUsecase(sender, &fakeUsers{settings: digest}, held)
+		uc.now = func() time.Time { return now }
+
+		if err := uc.SendMessage(entity.Message{Type: entity.NotifyLike, FromUserID: 1, ToUserID: 2}); err != nil {
+			t.Fatal(err)
+		}
+		uc.releaseDue(context.Background())
+
+		if len(held.held[2]) != 1 {
+			t.Fatalf("failed notification must be held again, got %+v", held.held)
+		}
+		if !held.lastAt.Equal(now.Add(retryDelay)) {
+			t.Fatalf("retry at %v, want %v", held.lastAt, now.Add(retryDelay))
+		}
+		if e := deliveries.entries[0]; e.status != entity.StatusQueued {
+			t.Fatalf("expected queued entry, got %+v", e)
+		}
+
+		sender.err = nil
+		uc.releaseDue(context.Background())
+		if len(held.held[2]) != 0 || deliveries.entries[0].status != entity.StatusSent {
+			t.Fatalf("retry must send the notification, held %+v, entry %+v", held.held, deliveries.entries[0])
+		}
+	})
+
+	t.Run("digest", func(t *testing.T) {
+		sender := &fakeSender{err: errors.New("timeout")}
+		held := &memHeld{}
+		uc, deliveries := newLoggedUsecase(sender, &fakeUsers{settings: digest}, held)
+		uc.now = func() time.Time { return now }
+
+		for _, from := range []int64{1, 3} {
+			if err := uc.SendMessage(entity.Message{Type: entity.NotifyLike, FromUserID: from, ToUserID: 2}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		uc.releaseDue(context.Background())
+
+		if len(held.held[2]) != 2 {
+			t.Fatalf("both notifications must be held again, got %+v", held.held)
+		}
+		for _, e := range deliveries.entries {
+			if e.status != entity.StatusQueued {
+				t.Fatalf("expected queued entry, got %+v", e)
+			}
+		}
+
+		sender.err = nil
+		uc.releaseDue(context.Background())
+		if len(sender.digests) != 2 || sender.digests[1][entity.NotifyLike] != 2 {
+			t.Fatalf("retry must send one digest with two likes, got %v", sender.digests)
+		}
+	})
+
+	t.Run("unreachable user is not retried", func(t *testing.T) {
+		sender := &fakeSender{}
+		held := &memHeld{}
+		users := &fakeUsers{settings: digest}
+		uc, deliveries := newLoggedUsecase(sender, users, held)
+		uc.now = func() time.Time { return now }
+
+		if err := uc.SendMessage(entity.Message{Type: entity.NotifyLike, FromUserID: 1, ToUserID: 2}); err != nil {
+			t.Fatal(err)
+		}
+		users.user.UnreachableAt = &now
+		uc.releaseDue(context.Background())
+
+		if len(held.held) != 0 || len(sender.sent) != 0 {
+			t.Fatalf("unreachable user must not be contacted, held %+v, sent %+v", held.held, sender.sent)
+		}
+		if e := deliveries.entries[0]; e.status != entity.StatusFailed || e.reason != entity.SkipUnreachable {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+	})
+}
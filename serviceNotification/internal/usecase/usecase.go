@@ -1,28 +1,361 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"serviceNotification/internal/entity"
+	"strconv"
+	"time"
 )
 
+// ErrInvalidNotification - уведомление неизвестного типа или без получателя.
+// Повторная отправка такого уведомления не поможет
+var ErrInvalidNotification = errors.New("неверное уведомление")
+
 // TelegramBotSender интерфейс для отправки сообщений в Telegram
 type TelegramBotSender interface {
 	SendMessage(entity.Message) error
+	// SendDigest - сводка отложенных уведомлений: тип -> количество
+	SendDigest(userID int64, counts map[string]int) error
+}
+
+// UserClient - анкеты и настройки уведомлений из serviceUser
+type UserClient interface {
+	GetUserByID(userID int64) (*entity.User, error)
+	GetNotificationSettings(userID int64) (*entity.NotificationSettings, error)
+}
+
+// UnreachablePublisher - сообщает serviceUser, что пользователю нельзя писать
+type UnreachablePublisher interface {
+	PublishUnreachable(ctx context.Context, userID int64, reason string) error
 }
 
+// HeldStore - отложенные уведомления: тихие часы и режим сводки
+type HeldStore interface {
+	Hold(ctx context.Context, key string, msg entity.Message, at time.Time) error
+	Cancel(ctx context.Context, userID int64, keys ...string) error
+	TakeDue(ctx context.Context, now time.Time) (map[int64][]entity.Message, error)
+}
+
+// retryDelay - через сколько повторить отложенные уведомления, которые не удалось отправить
+const retryDelay = time.Minute
+
 // BotUsecase - бизнес-логика для обработки сообщений
 type BotUsecase struct {
-	sender TelegramBotSender
+	sender      TelegramBotSender
+	users       UserClient
+	held        HeldStore
+	unreachable UnreachablePublisher
+	deliveries  DeliveryLog
+	digestEvery time.Duration
+	now         func() time.Time
 }
 
-// NewBotUsecase создает новый экземпляр BotUsecase
-func NewBotUsecase(sender TelegramBotSender) *BotUsecase {
+// NewBotUsecase создает новый экземпляр BotUsecase. digestEvery - длина окна
+// сводки для пользователей в режиме ModeDigest
+func NewBotUsecase(sender TelegramBotSender, users UserClient, held HeldStore, unreachable UnreachablePublisher, digestEvery time.Duration) *BotUsecase {
 	return &BotUsecase{
-		sender: sender,
+		sender:      sender,
+		users:       users,
+		held:        held,
+		unreachable: unreachable,
+		digestEvery: digestEvery,
+		now:         time.Now,
 	}
 }
 
-// HandleEvent обрабатывает событие и отправляет сообщение в Telegram
+// SendMessage проверяет уведомление и отправляет его в Telegram с учетом
+// настроек получателя. Ошибка отправки возвращается, чтобы уведомление
+// можно было повторить
 func (u *BotUsecase) SendMessage(msg entity.Message) error {
-	u.sender.SendMessage(msg)
+	messages, err := u.Accept(msg)
+	if err != nil {
+		return err
+	}
+	for _, m := range messages {
+		if err := u.Deliver(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Accept - проверяет уведомление, отменяет отложенные уведомления, которые оно
+// отзывает, и делит его по получателям. Доставка каждому получателю
+// повторяется отдельно: сбой у второго участника пары не должен
+// отправлять уведомление первому еще раз
+func (u *BotUsecase) Accept(msg entity.Message) ([]entity.Message, error) {
+	if err := validate(msg); err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	// Отмененный лайк или разорванная пара могли еще лежать в отложенных
+	switch msg.Type {
+	case entity.NotifyLikeUndone:
+		u.cancel(ctx, msg.ToUserID, msg.FromUserID, entity.NotifyLike, entity.NotifySuperLike)
+	case entity.NotifyMatchEnded:
+		for _, pair := range [][2]int64{{msg.FromUserID, msg.ToUserID}, {msg.ToUserID, msg.FromUserID}} {
+			u.cancel(ctx, pair[0], pair[1], entity.NotifyMatch, entity.NotifyMatchExpiring)
+		}
+	}
+
+	return recipients(msg), nil
+}
+
+// Deliver - доставка уведомления одному получателю из Accept
+func (u *BotUsecase) Deliver(msg entity.Message) error {
+	return u.deliver(context.Background(), msg)
+}
+
+// recipients - уведомления о паре получают оба участника, и у каждого свои
+// настройки, поэтому такое уведомление делится на два
+func recipients(msg entity.Message) []entity.Message {
+	switch msg.Type {
+	case entity.NotifyMatch, entity.NotifyMatchExpiring:
+		swapped := msg
+		swapped.FromUserID, swapped.ToUserID = msg.ToUserID, msg.FromUserID
+		return []entity.Message{msg, swapped}
+	default:
+		return []entity.Message{msg}
+	}
+}
+
+// configurable - типы, которые пользователь может отключить или отложить.
+// Решения модерации и отзыв уведомлений отправляются всегда и сразу
+func configurable(notifyType string) bool {
+	switch notifyType {
+	case entity.NotifyLike, entity.NotifySuperLike, entity.NotifyMatch,
+		entity.NotifyMatchExpiring, entity.NotifyInactivity,
+		entity.NotifyUnansweredLikes, entity.NotifyNewInCity:
+		return true
+	default:
+		return false
+	}
+}
+
+// preference - настройка, которая управляет типом. Все напоминания
+// отключаются одним переключателем inactivity_reminder
+func preference(notifyType string) string {
+	switch notifyType {
+	case entity.NotifyUnansweredLikes, entity.NotifyNewInCity:
+		return entity.NotifyInactivity
+	default:
+		return notifyType
+	}
+}
+
+// deliver - отправляет уведомление сразу или откладывает его. Каждое
+// уведомление, которое получатель не отключил, попадает в журнал
+func (u *BotUsecase) deliver(ctx context.Context, msg entity.Message) error {
+	if u.isUnreachable(msg.ToUserID) {
+		u.record(ctx, msg, entity.StatusFailed, entity.SkipUnreachable)
+		return nil
+	}
+	if !configurable(msg.Type) {
+		return u.sendNew(ctx, msg)
+	}
+	settings := u.settingsFor(msg.ToUserID)
+	if !settings.Enabled(preference(msg.Type)) {
+		return nil
+	}
+	at, hold := deliverAt(settings, u.now(), u.digestEvery)
+	if !hold || u.held == nil {
+		return u.sendNew(ctx, msg)
+	}
+
+	// Отложенное с тем же ключом заменяется новым - как и в журнале
+	u.cancelQueued(ctx, msg.ToUserID, msg.FromUserID, entity.SkipReplaced, msg.Type)
+	msg.InboxID = u.record(ctx, msg, entity.StatusQueued, "")
+	if err := u.held.Hold(ctx, holdKey(msg.Type, msg.FromUserID), msg, at); err != nil {
+		u.logResult(ctx, err, msg)
+		return err
+	}
+	return nil
+}
+
+// sendNew - записывает уведомление в журнал и отправляет его
+func (u *BotUsecase) sendNew(ctx context.Context, msg entity.Message) error {
+	msg.InboxID = u.record(ctx, msg, entity.StatusQueued, "")
+	return u.send(ctx, msg)
+}
+
+// send - отправляет уведомление; постоянная ошибка доставки не повторяется,
+// а сообщается в serviceUser
+func (u *BotUsecase) send(ctx context.Context, msg entity.Message) error {
+	err := u.sender.SendMessage(msg)
+	u.logResult(ctx, err, msg)
+	return u.handleFailure(ctx, err)
+}
+
+// handleFailure - пользователь заблокировал бота или удалил аккаунт: записываем
+// это в serviceUser и больше не пытаемся. Временные ошибки возвращаются для повтора
+func (u *BotUsecase) handleFailure(ctx context.Context, err error) error {
+	var unreachable *entity.UnreachableError
+	if !errors.As(err, &unreachable) {
+		return err
+	}
+	log.Printf("Пользователь %d недоступен (%s), уведомления прекращены: %v", unreachable.UserID, unreachable.Reason, unreachable.Err)
+	if u.unreachable == nil {
+		return nil
+	}
+	if err := u.unreachable.PublishUnreachable(ctx, unreachable.UserID, unreachable.Reason); err != nil {
+		// Без события serviceUser продолжит показывать анкету; пусть Kafka повторит
+		return fmt.Errorf("не удалось опубликовать %s: %w", entity.EventUserUnreachable, err)
+	}
 	return nil
 }
+
+// isUnreachable - serviceUser уже знает, что пользователю нельзя писать.
+// Если анкета недоступна, пробуем отправить: ошибка доставки скажет больше
+func (u *BotUsecase) isUnreachable(userID int64) bool {
+	if u.users == nil {
+		return false
+	}
+	user, err := u.users.GetUserByID(userID)
+	return err == nil && user != nil && user.UnreachableAt != nil
+}
+
+// settingsFor - настройки получателя; если serviceUser недоступен, уведомление
+// лучше отправить сразу, чем потерять
+func (u *BotUsecase) settingsFor(userID int64) entity.NotificationSettings {
+	if u.users != nil {
+		settings, err := u.users.GetNotificationSettings(userID)
+		if err == nil && settings != nil {
+			return *settings
+		}
+		log.Printf("Не удалось загрузить настройки уведомлений %d: %v", userID, err)
+	}
+	return entity.NotificationSettings{Mode: entity.ModeInstant}
+}
+
+// holdKey - ключ отложенного уведомления: повторный лайк от того же
+// пользователя заменяет прежний, а не считается дважды
+func holdKey(notifyType string, fromUserID int64) string {
+	return notifyType + ":" + strconv.FormatInt(fromUserID, 10)
+}
+
+// cancel - отменяет отложенные уведомления типов types от fromUserID
+func (u *BotUsecase) cancel(ctx context.Context, userID, fromUserID int64, types ...string) {
+	if u.held == nil {
+		return
+	}
+	keys := make([]string, len(types))
+	for i, notifyType := range types {
+		keys[i] = holdKey(notifyType, fromUserID)
+	}
+	if err := u.held.Cancel(ctx, userID, keys...); err != nil {
+		log.Printf("Не удалось отменить отложенные уведомления %d: %v", userID, err)
+		return
+	}
+	u.cancelQueued(ctx, userID, fromUserID, entity.SkipCancelled, types...)
+}
+
+// RunScheduler - отправляет отложенные уведомления, когда закончились тихие
+// часы или окно сводки. Работает до отмены ctx
+func (u *BotUsecase) RunScheduler(ctx context.Context, interval time.Duration) {
+	if u.held == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			u.releaseDue(ctx)
+		}
+	}
+}
+
+func (u *BotUsecase) releaseDue(ctx context.Context) {
+	due, err := u.held.TakeDue(ctx, u.now())
+	if err != nil {
+		log.Printf("Ошибка загрузки отложенных уведомлений: %v", err)
+	}
+	for userID, messages := range due {
+		u.release(ctx, userID, messages)
+	}
+}
+
+// release - отправляет накопленное получателю. Единственное уведомление типа
+// уходит как есть, несколько однотипных - одной строкой сводки
+func (u *BotUsecase) release(ctx context.Context, userID int64, messages []entity.Message) {
+	if u.isUnreachable(userID) {
+		u.setStatus(ctx, entity.StatusFailed, entity.SkipUnreachable, messages...)
+		return
+	}
+	settings := u.settingsFor(userID)
+	byType := make(map[string][]entity.Message)
+	for _, msg := range messages {
+		if settings.Enabled(preference(msg.Type)) {
+			byType[msg.Type] = append(byType[msg.Type], msg)
+		} else {
+			u.setStatus(ctx, entity.StatusFailed, entity.SkipDisabled, msg)
+		}
+	}
+
+	counts := make(map[string]int)
+	var failed []entity.Message
+	for notifyType, group := range byType {
+		if len(group) > 1 {
+			counts[notifyType] = len(group)
+			continue
+		}
+		if err := u.send(ctx, group[0]); err != nil {
+			log.Printf("Ошибка отправки отложенного уведомления %s для %d: %v", notifyType, userID, err)
+			failed = append(failed, group[0])
+		}
+	}
+	if len(counts) > 0 {
+		var digest []entity.Message
+		for notifyType := range counts {
+			digest = append(digest, byType[notifyType]...)
+		}
+		err := u.sender.SendDigest(userID, counts)
+		u.logResult(ctx, err, digest...)
+		if err := u.handleFailure(ctx, err); err != nil {
+			log.Printf("Ошибка отправки сводки для %d: %v", userID, err)
+			for notifyType := range counts {
+				failed = append(failed, byType[notifyType]...)
+			}
+		}
+	}
+
+	retryAt := u.now().Add(retryDelay)
+	for _, msg := range failed {
+		if err := u.held.Hold(ctx, holdKey(msg.Type, msg.FromUserID), msg, retryAt); err != nil {
+			log.Printf("Уведомление %s для %d потеряно: %v", msg.Type, userID, err)
+			u.logResult(ctx, err, msg)
+			continue
+		}
+		// Уведомление снова ждет отправки, последняя ошибка остается в журнале
+		u.setStatus(ctx, entity.StatusQueued, "", msg)
+	}
+}
+
+func validate(msg entity.Message) error {
+	if msg.ToUserID == 0 {
+		return fmt.Errorf("%w: %s без получателя", ErrInvalidNotification, msg.Type)
+	}
+	switch msg.Type {
+	case entity.NotifyLike, entity.NotifySuperLike, entity.NotifyLikeUndone,
+		entity.NotifyMatch, entity.NotifyMatchEnded, entity.NotifyMatchExpiring:
+		if msg.FromUserID == 0 {
+			return fmt.Errorf("%w: %s без отправителя", ErrInvalidNotification, msg.Type)
+		}
+		return nil
+	case entity.NotifyProfileApproved, entity.NotifyProfileRejected, entity.NotifyInactivity:
+		return nil
+	case entity.NotifyUnansweredLikes, entity.NotifyNewInCity:
+		if msg.Count <= 0 {
+			return fmt.Errorf("%w: %s без количества", ErrInvalidNotification, msg.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: неизвестный тип %q", ErrInvalidNotification, msg.Type)
+	}
+}
@@ -0,0 +1,17 @@
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    telegram_id BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    from_user_id BIGINT,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(16) NOT NULL DEFAULT 'queued', -- queued | sent | failed | read
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at TIMESTAMPTZ,
+    read_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_notifications_telegram_id ON notifications (telegram_id, id DESC);
+CREATE INDEX idx_notifications_unread ON notifications (telegram_id) WHERE status = 'sent';
+CREATE INDEX idx_notifications_queued ON notifications (telegram_id, type, from_user_id) WHERE status = 'queued';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notifications_event_key;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS event_key;
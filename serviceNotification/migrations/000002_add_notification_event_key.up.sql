@@ -0,0 +1,5 @@
+ALTER TABLE notifications ADD COLUMN event_key TEXT;
+
+-- Повтор события из Kafka обновляет свою запись, а не создает новую.
+-- У уведомлений планировщика event_key пустой, NULL не конфликтуют
+CREATE UNIQUE INDEX idx_notifications_event_key ON notifications (event_key, telegram_id);
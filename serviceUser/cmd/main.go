@@ -3,10 +3,12 @@ package main
 import (
 	"context"
 	"service1/internal/config"
+	"service1/internal/delivery"
 	"service1/internal/handler"
 	"service1/internal/repository"
 	"service1/internal/storage"
 	"service1/internal/usecase"
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -26,8 +28,12 @@ func InitUserUse(cfg *config.Config) (*gin.Engine, error) {
 	// Настройка логирования
 	logger := logrus.New()
 
-	// Инициализация репозитория
+	// Инициализация репозиториев
 	repo := repository.NewUserRepository(pool, logger)
+	attributeRepo := repository.NewAttributeRepository(pool, logger)
+	promptRepo := repository.NewPromptRepository(pool, logger)
+	questionRepo := repository.NewQuestionRepository(pool, logger)
+	notificationRepo := repository.NewNotificationRepository(pool, logger)
 
 	// Подключение к MinIO
 	s3, err := storage.NewMinioStorage(cfg)
@@ -45,8 +51,27 @@ func InitUserUse(cfg *config.Config) (*gin.Engine, error) {
 	// Создание Usecase
 	uc := usecase.NewUserUsecase(repo, s3, redis)
 
+	// Фоновый сброс активности пользователей в Postgres
+	go uc.RunActivityFlusher(context.Background(), cfg.ActivityFlushInterval)
+
+	// События о пользователях от других сервисов
+	if consumer, err := delivery.NewUserEventConsumer(cfg.KafkaBrokers, cfg.KafkaUserTopic, cfg.KafkaGroupID, uc); err != nil {
+		logrus.Warn("События о пользователях из Kafka не читаются: ", err)
+	} else {
+		go consumer.Start(context.Background())
+	}
+
+	attributes := usecase.NewAttributeUsecase(attributeRepo, redis)
+	prompts := usecase.NewPromptUsecase(promptRepo, redis)
+	questions := usecase.NewQuestionUsecase(questionRepo)
+	notifications := usecase.NewNotificationUsecase(notificationRepo)
+
 	// Инициализация хендлеров
-	_, router := handler.NewUserHandler(*uc)
+	_, router := handler.NewUserHandler(*uc, attributes)
+	handler.NewAttributeHandler(attributes, router, cfg.AdminToken)
+	handler.NewPromptHandler(prompts, router, cfg.AdminToken)
+	handler.NewQuestionHandler(questions, router, cfg.AdminToken)
+	handler.NewNotificationHandler(notifications, router)
 
 	// Подключение Swagger документации
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
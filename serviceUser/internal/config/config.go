@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"time"
 )
 
 type Config struct {
@@ -12,6 +13,14 @@ type Config struct {
 	MinioRootPassword string
 	S3Bucket          string
 	RedisAddr         string
+	// Как часто активность пользователей сбрасывается из Redis в Postgres
+	ActivityFlushInterval time.Duration
+	// Токен для /admin/* эндпоинтов, пустой - админка закрыта
+	AdminToken string
+	// События о пользователях из Kafka, например user.unreachable; пустой KAFKA_URL - не читать
+	KafkaBrokers   []string
+	KafkaUserTopic string
+	KafkaGroupID   string
 }
 
 func NewConfig() *Config {
@@ -23,6 +32,13 @@ func NewConfig() *Config {
 		MinioRootPassword: getEnv("MINIO_ROOT_PASSWORD", "minioadmin"),
 		S3Bucket:          getEnv("S3_BUCKET", "my-bucket"),
 		RedisAddr:         getEnv("REDIS_ADDR", "localhost:6379"),
+
+		ActivityFlushInterval: getEnvDuration("ACTIVITY_FLUSH_INTERVAL", time.Minute),
+		AdminToken:            getEnv("ADMIN_TOKEN", ""),
+
+		KafkaBrokers:   []string{getEnv("KAFKA_URL", "")},
+		KafkaUserTopic: getEnv("KAFKA_USER_TOPIC", "users-topic"),
+		KafkaGroupID:   getEnv("KAFKA_GROUP_ID", "serviceUser"),
 	}
 }
 
@@ -32,3 +48,12 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
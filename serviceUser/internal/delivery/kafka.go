@@ -0,0 +1,89 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"service1/internal/usecase"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// EventUserUnreachable - serviceNotification не может писать пользователю
+const EventUserUnreachable = "user.unreachable"
+
+// UserEvent - событие о пользователе из Kafka
+type UserEvent struct {
+	Type   string    `json:"type"`
+	UserID int64     `json:"user_id"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// UserEventConsumer - читает события о пользователях от других сервисов
+type UserEventConsumer struct {
+	usecase *usecase.UserUsecase
+	reader  *kafka.Reader
+}
+
+func NewUserEventConsumer(brokers []string, topic, groupID string, uc *usecase.UserUsecase) (*UserEventConsumer, error) {
+	if len(brokers) == 0 || brokers[0] == "" || topic == "" || groupID == "" {
+		return nil, errors.New("не указаны параметры подключения к Kafka")
+	}
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:  brokers,
+		Topic:    topic,
+		GroupID:  groupID,
+		MinBytes: 10e1,
+		MaxBytes: 10e6,
+	})
+	return &UserEventConsumer{usecase: uc, reader: reader}, nil
+}
+
+// Start - обрабатывает события до отмены ctx. Ошибка базы оставляет событие
+// неподтвержденным, и оно будет прочитано снова
+func (c *UserEventConsumer) Start(ctx context.Context) {
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logrus.Error("Ошибка чтения события из Kafka: ", err)
+			continue
+		}
+
+		if err := c.handle(ctx, msg.Value); err != nil {
+			logrus.Error("Ошибка обработки события о пользователе: ", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		if err := c.reader.CommitMessages(ctx, msg); err != nil {
+			logrus.Error("Ошибка подтверждения события: ", err)
+		}
+	}
+}
+
+func (c *UserEventConsumer) handle(ctx context.Context, data []byte) error {
+	var event UserEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		logrus.Warn("Пропущено нераспознанное событие: ", string(data))
+		return nil
+	}
+	switch event.Type {
+	case EventUserUnreachable:
+		err := c.usecase.MarkUnreachable(ctx, event.UserID, event.Reason, event.At)
+		if errors.Is(err, usecase.ErrInvalidReason) {
+			logrus.Warn("Пропущено событие с неизвестной причиной: ", string(data))
+			return nil
+		}
+		if err == nil {
+			logrus.Infof("Пользователь %d недоступен (%s), анкета скрыта из выдачи", event.UserID, event.Reason)
+		}
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,35 @@
+package entity
+
+// Типы значений атрибутов анкеты
+const (
+	AttributeInt       = "int"
+	AttributeString    = "string"
+	AttributeEnum      = "enum"
+	AttributeMultiEnum = "multi_enum"
+)
+
+// AttributeDefinition описывает атрибут анкеты в реестре: тип, допустимые
+// значения и можно ли по нему фильтровать поиск
+type AttributeDefinition struct {
+	Key           string            `json:"key"`
+	Label         string            `json:"label"`
+	Type          string            `json:"type"`
+	AllowedValues []string          `json:"allowed_values,omitempty"`
+	ValueLabels   map[string]string `json:"value_labels,omitempty"`
+	Min           *int              `json:"min,omitempty"`
+	Max           *int              `json:"max,omitempty"`
+	Searchable    bool              `json:"searchable"`
+}
+
+// AttributeValues - значения атрибутов пользователя: ключ -> значения.
+// У int, string и enum значение одно, у multi_enum может быть несколько.
+type AttributeValues map[string][]string
+
+// AttributeCondition - условие поиска по атрибуту. Для enum-атрибутов
+// заполняется Values (подходит любое), для int - Min и/или Max.
+type AttributeCondition struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values,omitempty"`
+	Min    *int     `json:"min,omitempty"`
+	Max    *int     `json:"max,omitempty"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+// Коды пола. Это стабильные значения API, человекочитаемые подписи живут в боте.
+const (
+	GenderMale        = "male"
+	GenderFemale      = "female"
+	GenderNonBinary   = "nonbinary"
+	GenderGenderfluid = "genderfluid"
+	GenderAgender     = "agender"
+)
+
+// Genders - все допустимые коды пола
+var Genders = []string{GenderMale, GenderFemale, GenderNonBinary, GenderGenderfluid, GenderAgender}
+
+// IsValidGender проверяет, что код пола известен
+func IsValidGender(gender string) bool {
+	for _, g := range Genders {
+		if g == gender {
+			return true
+		}
+	}
+	return false
+}
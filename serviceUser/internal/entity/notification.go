@@ -0,0 +1,37 @@
+package entity
+
+// Режимы доставки уведомлений
+const (
+	// NotifyInstant - каждое уведомление приходит сразу
+	NotifyInstant = "instant"
+	// NotifyDigest - уведомления копятся и приходят одной сводкой
+	NotifyDigest = "digest"
+)
+
+// DefaultTimeZone - часовой пояс, пока пользователь не выбрал свой
+const DefaultTimeZone = "Europe/Moscow"
+
+// NotificationTypes - типы уведомлений, которые пользователь может отключить.
+// Решения модерации и отзыв уже отправленных уведомлений не отключаются
+var NotificationTypes = []string{"like", "superlike", "match", "match_expiring", "inactivity_reminder"}
+
+// NotificationSettings - настройки уведомлений пользователя
+// @Description Notification settings: disabled types, quiet hours in the user's time zone and delivery mode
+type NotificationSettings struct {
+	// Disabled - отключенные типы из NotificationTypes
+	Disabled []string `json:"disabled"`
+	// QuietFrom и QuietTo - тихие часы по местному времени, с QuietFrom:00 до QuietTo:00.
+	// Окно может переходить через полночь (23-8); nil - тихих часов нет
+	QuietFrom *int `json:"quiet_from,omitempty"`
+	QuietTo   *int `json:"quiet_to,omitempty"`
+	// TimeZone - часовой пояс IANA, например Europe/Moscow
+	TimeZone string `json:"time_zone"`
+	// Mode - NotifyInstant или NotifyDigest
+	Mode string `json:"mode"`
+}
+
+// DefaultNotificationSettings - настройки пользователя, который их не менял:
+// все уведомления сразу и без тихих часов
+func DefaultNotificationSettings() NotificationSettings {
+	return NotificationSettings{Disabled: []string{}, TimeZone: DefaultTimeZone, Mode: NotifyInstant}
+}
@@ -0,0 +1,15 @@
+package entity
+
+// Prompt - вопрос из каталога, на который можно ответить в анкете
+type Prompt struct {
+	ID     int    `json:"id"`
+	Text   string `json:"text"`
+	Active bool   `json:"active"`
+}
+
+// PromptAnswer - ответ пользователя на вопрос из каталога
+type PromptAnswer struct {
+	PromptID int    `json:"prompt_id"`
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+// Question - вопрос анкеты совместимости с вариантами ответа
+type Question struct {
+	ID      int      `json:"id"`
+	Text    string   `json:"text"`
+	Options []string `json:"options"`
+	Active  bool     `json:"active"`
+}
+
+// Важность вопроса для пользователя
+const (
+	ImportanceIrrelevant = 0
+	ImportanceLittle     = 1
+	ImportanceSomewhat   = 2
+	ImportanceVery       = 3
+	ImportanceMandatory  = 4
+)
+
+// QuestionAnswer - ответ пользователя: свой вариант, варианты, которые
+// устроят у партнера, и насколько вопрос важен. Варианты - индексы в Question.Options
+type QuestionAnswer struct {
+	QuestionID int   `json:"question_id"`
+	Answer     int   `json:"answer"`
+	Acceptable []int `json:"acceptable"`
+	Importance int   `json:"importance"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+// DefaultRating - стартовый Elo-рейтинг анкеты
+const DefaultRating = 1500
+
+// GeoPoint - координаты пользователя
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// FeedLog - запись о выданной ленте: кому, в каком варианте эксперимента
+// и в каком порядке были показаны анкеты
+type FeedLog struct {
+	ViewerID     int64
+	Experiment   string
+	Variant      string
+	CandidateIDs []int64
+	Scores       []float64
+}
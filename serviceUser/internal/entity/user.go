@@ -1,11 +1,14 @@
 package entity
 
+import "time"
+
 // @Description User structure
 // @Param id path int true "User ID"
 // @Param name formData string true "User's name"
 // @Param age formData int true "User's age"
 // @Param city formData string true "City of the user"
-// @Param gender formData string true "Gender of the user"
+// @Param gender formData string true "Gender code of the user: male, female, nonbinary, genderfluid, agender"
+// @Param interested_in formData []string true "Gender codes the user is interested in"
 // @Param description formData string true "Description of the user"
 // @Param telegram_id formData int64 true "Telegram ID of the user"
 type User struct {
@@ -17,4 +20,41 @@ type User struct {
 	Gender      string `json:"gender,omitempty"`
 	Description string `json:"description"`
 	Photo       string `json:"photo"`
+	// InterestedIn - коды пола, которые интересны пользователю
+	InterestedIn []string `json:"interested_in"`
+	// Attributes - расширенные атрибуты анкеты из реестра (рост, языки и т.д.)
+	Attributes AttributeValues `json:"attributes,omitempty"`
+	// Prompts - ответы на вопросы-карточки, не больше трех
+	Prompts []PromptAnswer `json:"prompts,omitempty"`
+	// LastActiveAt - время последней активности в боте или в свайпах
+	LastActiveAt time.Time `json:"last_active_at"`
+	// LanguageCode - язык интерфейса из Telegram, по нему выбираются тексты бота
+	LanguageCode string `json:"language_code,omitempty"`
+	// UnreachableAt и UnreachableReason - бот не может писать пользователю
+	// (заблокирован, аккаунт удален); такая анкета не показывается в выдаче
+	UnreachableAt     *time.Time `json:"unreachable_at,omitempty"`
+	UnreachableReason string     `json:"unreachable_reason,omitempty"`
+	// Location - координаты, если пользователь ими поделился
+	Location *GeoPoint `json:"location,omitempty"`
+	// Compatibility - процент совместимости по анкете вопросов с тем, кто смотрит выдачу;
+	// nil, если общих ответов нет
+	Compatibility *int `json:"compatibility,omitempty"`
+	// Rating - Elo-рейтинг привлекательности, наружу не отдается
+	Rating float64 `json:"-"`
+}
+
+// InactiveUser - давно не заходивший пользователь и число новых анкет в его
+// городе, появившихся после его последнего визита
+type InactiveUser struct {
+	TelegramID   int64     `json:"telegram_id"`
+	City         string    `json:"city"`
+	LastActiveAt time.Time `json:"last_active_at"`
+	NewProfiles  int       `json:"new_profiles"`
 }
+
+// Причины, по которым бот не может доставить сообщение пользователю
+const (
+	UnreachableBlocked      = "blocked"
+	UnreachableChatNotFound = "chat_not_found"
+	UnreachableDeactivated  = "deactivated"
+)
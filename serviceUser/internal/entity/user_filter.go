@@ -1,8 +1,28 @@
 package entity
 
+import "time"
+
+// Варианты сортировки результатов поиска
+const (
+	SortByRecency = "recency" // сначала те, кто заходил последним
+	SortByRank    = "rank"    // ранжирование для зрителя из ExcludeFor
+)
+
 type UserFilter struct {
 	MinAge *int   `json:"min_age,omitempty"`
 	MaxAge *int   `json:"max_age,omitempty"`
 	City   string `json:"city,omitempty"`
-	Gender string `json:"gender,omitempty"`
+	// Genders - допустимые коды пола кандидата (то, кого ищет пользователь)
+	Genders []string `json:"genders,omitempty"`
+	// InterestedIn - пол ищущего: кандидат тоже должен им интересоваться
+	InterestedIn string        `json:"interested_in,omitempty"`
+	ActiveWithin time.Duration `json:"active_within,omitempty"`
+	SortBy       string        `json:"sort,omitempty"`
+	// Attributes - условия по атрибутам анкеты, все должны выполняться
+	Attributes []AttributeCondition `json:"attributes,omitempty"`
+	// TelegramIDs - выборка конкретных анкет (пакетное получение)
+	TelegramIDs []int64 `json:"telegram_ids,omitempty"`
+	// ExcludeFor - ID того, кто смотрит выдачу: его собственная анкета
+	// и скрытые им анкеты в результат не попадают
+	ExcludeFor int64 `json:"exclude_for,omitempty"`
 }
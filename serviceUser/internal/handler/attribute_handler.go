@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"service1/internal/entity"
+	"service1/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttributeHandler struct {
+	usecase *usecase.AttributeUsecase
+}
+
+func NewAttributeHandler(uc *usecase.AttributeUsecase, router *gin.Engine, adminToken string) *AttributeHandler {
+	h := &AttributeHandler{usecase: uc}
+	router.GET("/attributes", h.List)
+	router.PUT("/users/:id/attributes/:key", h.SetUserValues)
+	router.DELETE("/users/:id/attributes/:key", h.DeleteUserValues)
+
+	admin := router.Group("/admin", AdminAuth(adminToken))
+	admin.POST("/attributes", h.Create)
+	admin.PUT("/attributes/:key", h.Update)
+	admin.DELETE("/attributes/:key", h.Delete)
+	return h
+}
+
+// AdminAuth пропускает только запросы с верным X-Admin-Token.
+// Если токен не настроен, админские эндпоинты закрыты полностью.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got := c.GetHeader("X-Admin-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// @Summary List profile attributes
+// @Description List the attribute registry: types, allowed values and searchability
+// @Tags attributes
+// @Produce json
+// @Success 200 {array} entity.AttributeDefinition "Attribute definitions"
+// @Failure 500 {string} string "Internal server error"
+// @Router /attributes [get]
+func (h *AttributeHandler) List(c *gin.Context) {
+	defs, err := h.usecase.Definitions(c.Request.Context())
+	if err != nil {
+		log.Printf("Error listing attributes: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, defs)
+}
+
+// @Summary Create profile attribute
+// @Description Add a new attribute to the registry
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param attribute body entity.AttributeDefinition true "Attribute definition"
+// @Success 201 {object} entity.AttributeDefinition "Created attribute"
+// @Failure 400 {string} string "Bad request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {string} string "Internal server error"
+// @Router /admin/attributes [post]
+func (h *AttributeHandler) Create(c *gin.Context) {
+	var def entity.AttributeDefinition
+	if err := c.ShouldBindJSON(&def); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.usecase.CreateDefinition(c.Request.Context(), &def); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, def)
+}
+
+// @Summary Update profile attribute
+// @Description Update label, allowed values, range or searchability of an attribute. The type cannot change.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param key path string true "Attribute key"
+// @Param attribute body entity.AttributeDefinition true "Attribute definition"
+// @Success 200 {object} entity.AttributeDefinition "Updated attribute"
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Attribute not found"
+// @Router /admin/attributes/{key} [put]
+func (h *AttributeHandler) Update(c *gin.Context) {
+	var def entity.AttributeDefinition
+	if err := c.ShouldBindJSON(&def); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	def.Key = c.Param("key")
+	if err := h.usecase.UpdateDefinition(c.Request.Context(), &def); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, def)
+}
+
+// @Summary Delete profile attribute
+// @Description Delete an attribute and all stored values of it
+// @Tags admin
+// @Param X-Admin-Token header string true "Admin token"
+// @Param key path string true "Attribute key"
+// @Success 204 {string} string "Attribute deleted"
+// @Failure 404 {string} string "Attribute not found"
+// @Router /admin/attributes/{key} [delete]
+func (h *AttributeHandler) Delete(c *gin.Context) {
+	if err := h.usecase.DeleteDefinition(c.Request.Context(), c.Param("key")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Set user attribute
+// @Description Replace the values of one attribute of a user
+// @Tags attributes
+// @Accept json
+// @Param id path int true "Telegram ID"
+// @Param key path string true "Attribute key"
+// @Param values body object true "{\"values\": [\"relationship\"]}"
+// @Success 204 {string} string "Attribute saved"
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Attribute not found"
+// @Router /users/{id}/attributes/{key} [put]
+func (h *AttributeHandler) SetUserValues(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	var req struct {
+		Values []string `json:"values"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.usecase.SetUserValues(c.Request.Context(), id, c.Param("key"), req.Values); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Delete user attribute
+// @Description Remove the values of one attribute of a user
+// @Tags attributes
+// @Param id path int true "Telegram ID"
+// @Param key path string true "Attribute key"
+// @Success 204 {string} string "Attribute removed"
+// @Failure 400 {string} string "Bad request"
+// @Router /users/{id}/attributes/{key} [delete]
+func (h *AttributeHandler) DeleteUserValues(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if err := h.usecase.DeleteUserValues(c.Request.Context(), id, c.Param("key")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AttributeHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidAttribute):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAttributeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Error in attributes: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"service1/internal/entity"
+	"service1/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	usecase *usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(uc *usecase.NotificationUsecase, router *gin.Engine) *NotificationHandler {
+	h := &NotificationHandler{usecase: uc}
+	router.GET("/users/:id/notification-settings", h.Settings)
+	router.PUT("/users/:id/notification-settings", h.SaveSettings)
+	return h
+}
+
+// @Summary Get notification settings
+// @Description Get the notification settings of a user. Users who never changed them get the defaults.
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Telegram ID"
+// @Success 200 {object} entity.NotificationSettings "Notification settings"
+// @Failure 400 {string} string "Bad request"
+// @Router /users/{id}/notification-settings [get]
+func (h *NotificationHandler) Settings(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	settings, err := h.usecase.Settings(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// @Summary Save notification settings
+// @Description Replace the notification settings of a user
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path int true "Telegram ID"
+// @Param settings body entity.NotificationSettings true "{\"disabled\": [\"superlike\"], \"quiet_from\": 23, \"quiet_to\": 8, \"time_zone\": \"Europe/Moscow\", \"mode\": \"digest\"}"
+// @Success 200 {object} entity.NotificationSettings "Saved settings"
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "User not found"
+// @Router /users/{id}/notification-settings [put]
+func (h *NotificationHandler) SaveSettings(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	var req entity.NotificationSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	settings, err := h.usecase.SaveSettings(c.Request.Context(), id, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *NotificationHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSettings):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Error in notification settings: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
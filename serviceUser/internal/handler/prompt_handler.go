@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"service1/internal/entity"
+	"service1/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromptHandler struct {
+	usecase *usecase.PromptUsecase
+}
+
+func NewPromptHandler(uc *usecase.PromptUsecase, router *gin.Engine, adminToken string) *PromptHandler {
+	h := &PromptHandler{usecase: uc}
+	router.GET("/prompts", h.Catalogue)
+	router.GET("/users/:id/prompts", h.UserPrompts)
+	router.PUT("/users/:id/prompts/:prompt_id", h.Answer)
+	router.DELETE("/users/:id/prompts/:prompt_id", h.DeleteAnswer)
+
+	admin := router.Group("/admin", AdminAuth(adminToken))
+	admin.POST("/prompts", h.Create)
+	admin.PATCH("/prompts/:prompt_id", h.SetActive)
+	return h
+}
+
+// @Summary List prompts
+// @Description List the catalogue of prompts a user can answer
+// @Tags prompts
+// @Produce json
+// @Success 200 {array} entity.Prompt "Prompts"
+// @Failure 500 {string} string "Internal server error"
+// @Router /prompts [get]
+func (h *PromptHandler) Catalogue(c *gin.Context) {
+	prompts, err := h.usecase.Catalogue(c.Request.Context())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, prompts)
+}
+
+// @Summary Create prompt
+// @Description Add a prompt to the catalogue
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param prompt body object true "{\"text\": \"Идеальное свидание — это…\"}"
+// @Success 201 {object} entity.Prompt "Created prompt"
+// @Failure 400 {string} string "Bad request"
+// @Router /admin/prompts [post]
+func (h *PromptHandler) Create(c *gin.Context) {
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := h.usecase.CreatePrompt(c.Request.Context(), req.Text)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": id, "text": req.Text, "active": true})
+}
+
+// @Summary Enable or disable prompt
+// @Description Hide a prompt from the catalogue or bring it back. Existing answers stay in profiles.
+// @Tags admin
+// @Accept json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param prompt_id path int true "Prompt ID"
+// @Param prompt body object true "{\"active\": false}"
+// @Success 204 {string} string "Prompt updated"
+// @Failure 404 {string} string "Prompt not found"
+// @Router /admin/prompts/{prompt_id} [patch]
+func (h *PromptHandler) SetActive(c *gin.Context) {
+	promptID, err := strconv.Atoi(c.Param("prompt_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prompt ID"})
+		return
+	}
+	var req struct {
+		Active bool `json:"active"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.usecase.SetPromptActive(c.Request.Context(), promptID, req.Active); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get user prompts
+// @Description Get the prompt answers of a user
+// @Tags prompts
+// @Produce json
+// @Param id path int true "Telegram ID"
+// @Success 200 {array} entity.PromptAnswer "Prompt answers"
+// @Failure 400 {string} string "Bad request"
+// @Router /users/{id}/prompts [get]
+func (h *PromptHandler) UserPrompts(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	answers, err := h.usecase.UserPrompts(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if answers == nil {
+		answers = []entity.PromptAnswer{}
+	}
+	c.JSON(http.StatusOK, answers)
+}
+
+// @Summary Answer prompt
+// @Description Create or replace the answer to a prompt. A profile holds at most three answers.
+// @Tags prompts
+// @Accept json
+// @Param id path int true "Telegram ID"
+// @Param prompt_id path int true "Prompt ID"
+// @Param answer body object true "{\"answer\": \"Пикник на крыше\"}"
+// @Success 204 {string} string "Answer saved"
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Prompt not found"
+// @Failure 409 {string} string "Too many prompts"
+// @Router /users/{id}/prompts/{prompt_id} [put]
+func (h *PromptHandler) Answer(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	promptID, err := strconv.Atoi(c.Param("prompt_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prompt ID"})
+		return
+	}
+	var req struct {
+		Answer string `json:"answer"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.usecase.Answer(c.Request.Context(), id, promptID, req.Answer); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Delete prompt answer
+// @Description Remove the answer to a prompt from the profile
+// @Tags prompts
+// @Param id path int true "Telegram ID"
+// @Param prompt_id path int true "Prompt ID"
+// @Success 204 {string} string "Answer removed"
+// @Failure 404 {string} string "Prompt not found"
+// @Router /users/{id}/prompts/{prompt_id} [delete]
+func (h *PromptHandler) DeleteAnswer(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	promptID, err := strconv.Atoi(c.Param("prompt_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prompt ID"})
+		return
+	}
+	if err := h.usecase.DeleteAnswer(c.Request.Context(), id, promptID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PromptHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidPrompt):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrPromptNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrTooManyPrompts):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Error in prompts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"service1/internal/entity"
+	"service1/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuestionHandler struct {
+	usecase *usecase.QuestionUsecase
+}
+
+func NewQuestionHandler(uc *usecase.QuestionUsecase, router *gin.Engine, adminToken string) *QuestionHandler {
+	h := &QuestionHandler{usecase: uc}
+	router.GET("/questions", h.Catalogue)
+	router.GET("/users/:id/questions", h.UserAnswers)
+	router.GET("/users/:id/questions/next", h.Next)
+	router.PUT("/users/:id/questions/:question_id", h.Answer)
+	router.DELETE("/users/:id/questions/:question_id", h.DeleteAnswer)
+	router.GET("/users/:id/compatibility/:other_id", h.Compatibility)
+
+	admin := router.Group("/admin", AdminAuth(adminToken))
+	admin.POST("/questions", h.Create)
+	admin.PATCH("/questions/:question_id", h.SetActive)
+	return h
+}
+
+// @Summary List questions
+// @Description List the compatibility questionnaire
+// @Tags questions
+// @Produce json
+// @Success 200 {array} entity.Question "Questions"
+// @Failure 500 {string} string "Internal server error"
+// @Router /questions [get]
+func (h *QuestionHandler) Catalogue(c *gin.Context) {
+	questions, err := h.usecase.Catalogue(c.Request.Context())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, questions)
+}
+
+// @Summary Create question
+// @Description Add a multiple-choice question to the compatibility questionnaire
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param question body object true "{\"text\": \"Хочешь ли ты детей?\", \"options\": [\"Да\", \"Нет\"]}"
+// @Success 201 {object} entity.Question "Created question"
+// @Failure 400 {string} string "Bad request"
+// @Router /admin/questions [post]
+func (h *QuestionHandler) Create(c *gin.Context) {
+	var req struct {
+		Text    string   `json:"text"`
+		Options []string `json:"options"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := h.usecase.CreateQuestion(c.Request.Context(), req.Text, req.Options)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, entity.Question{ID: id, Text: req.Text, Options: req.Options, Active: true})
+}
+
+// @Summary Enable or disable question
+// @Description Hide a question from the questionnaire or bring it back. Existing answers still count.
+// @Tags admin
+// @Accept json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param question_id path int true "Question ID"
+// @Param question body object true "{\"active\": false}"
+// @Success 204 {string} string "Question updated"
+// @Failure 404 {string} string "Question not found"
+// @Router /admin/questions/{question_id} [patch]
+func (h *QuestionHandler) SetActive(c *gin.Context) {
+	questionID, err := strconv.Atoi(c.Param("question_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+	var req struct {
+		Active bool `json:"active"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.usecase.SetQuestionActive(c.Request.Context(), questionID, req.Active); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get user answers
+// @Description Get the questionnaire answers of a user
+// @Tags questions
+// @Produce json
+// @Param id path int true "Telegram ID"
+// @Success 200 {array} entity.QuestionAnswer "Answers"
+// @Failure 400 {string} string "Bad request"
+// @Router /users/{id}/questions [get]
+func (h *QuestionHandler) UserAnswers(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	answers, err := h.usecase.UserAnswers(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if answers == nil {
+		answers = []entity.QuestionAnswer{}
+	}
+	c.JSON(http.StatusOK, answers)
+}
+
+// @Summary Next questions
+// @Description Questions the user has not answered yet, a few at a time
+// @Tags questions
+// @Produce json
+// @Param id path int true "Telegram ID"
+// @Param limit query int false "How many questions to return, 3 by default, at most 10"
+// @Success 200 {array} entity.Question "Questions"
+// @Failure 400 {string} string "Bad request"
+// @Router /users/{id}/questions/next [get]
+func (h *QuestionHandler) Next(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+	questions, err := h.usecase.Next(c.Request.Context(), id, limit)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, questions)
+}
+
+// @Summary Answer question
+// @Description Create or replace the answer to a question: own option, acceptable partner options and importance 0-4
+// @Tags questions
+// @Accept json
+// @Param id path int true "Telegram ID"
+// @Param question_id path int true "Question ID"
+// @Param answer body object true "{\"answer\": 0, \"acceptable\": [0, 2], \"importance\": 3}"
+// @Success 204 {string} string "Answer saved"
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Question not found"
+// @Router /users/{id}/questions/{question_id} [put]
+func (h *QuestionHandler) Answer(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	questionID, err := strconv.Atoi(c.Param("question_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+	var answer entity.QuestionAnswer
+	if err := c.ShouldBindJSON(&answer); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	answer.QuestionID = questionID
+	if err := h.usecase.Answer(c.Request.Context(), id, answer); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Delete question answer
+// @Description Remove the answer to a question
+// @Tags questions
+// @Param id path int true "Telegram ID"
+// @Param question_id path int true "Question ID"
+// @Success 204 {string} string "Answer removed"
+// @Failure 404 {string} string "Question not found"
+// @Router /users/{id}/questions/{question_id} [delete]
+func (h *QuestionHandler) DeleteAnswer(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	questionID, err := strconv.Atoi(c.Param("question_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+	if err := h.usecase.DeleteAnswer(c.Request.Context(), id, questionID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Compatibility
+// @Description Match percentage of two users by their questionnaire answers
+// @Tags questions
+// @Produce json
+// @Param id path int true "Telegram ID"
+// @Param other_id path int true "Telegram ID of the other user"
+// @Success 200 {object} object "{\"compatibility\": 87, \"common_questions\": 5}; compatibility is null without common questions"
+// @Failure 400 {string} string "Bad request"
+// @Router /users/{id}/compatibility/{other_id} [get]
+func (h *QuestionHandler) Compatibility(c *gin.Context) {
+	id, err1 := strconv.ParseInt(c.Param("id"), 10, 64)
+	otherID, err2 := strconv.ParseInt(c.Param("other_id"), 10, 64)
+	if err1 != nil || err2 != nil || id <= 0 || otherID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	percent, common, err := h.usecase.Compatibility(c.Request.Context(), id, otherID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"compatibility": percent, "common_questions": common})
+}
+
+func (h *QuestionHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidQuestion):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrQuestionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Error in questions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -9,22 +9,33 @@ import (
 	"service1/internal/entity"
 	"service1/internal/usecase"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserHandler struct {
-	usecase *usecase.UserUsecase
+	usecase    *usecase.UserUsecase
+	attributes *usecase.AttributeUsecase
 }
 
-func NewUserHandler(usecase usecase.UserUsecase) (*UserHandler, *gin.Engine) {
-	h := UserHandler{usecase: &usecase}
+func NewUserHandler(usecase usecase.UserUsecase, attributes *usecase.AttributeUsecase) (*UserHandler, *gin.Engine) {
+	h := UserHandler{usecase: &usecase, attributes: attributes}
 	router := gin.New()
 	router.POST("/users", h.CreateUser)
 	router.GET("/users/:id", h.GetByID)
 	router.GET("/users/search", h.Search)
+	router.GET("/users/batch", h.GetByIDs)
+	router.GET("/users/inactive", h.Inactive)
 	router.PUT("/users/:id", h.Update)
 	router.DELETE("/users/:id", h.Delete)
+	router.POST("/users/:id/activity", h.TouchActivity)
+	router.PUT("/users/:id/hidden/:hidden_id", h.HideUser)
+	router.POST("/users/:id/rating", h.RateUser)
+	router.PUT("/users/:id/location", h.SetLocation)
+	router.PUT("/users/:id/language", h.SetLanguage)
+	router.POST("/users/:id/reactivate", h.Reactivate)
 
 	return &h, router
 }
@@ -45,6 +56,10 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		Description string `json:"description"`
 		Age         int    `json:"age"`
 		TelegramID  int64  `json:"telegram_id"`
+
+		InterestedIn []string `json:"interested_in"`
+		// LanguageCode - язык интерфейса из Telegram, необязательный
+		LanguageCode string `json:"language_code"`
 	}
 
 	if err := json.Unmarshal([]byte(jsonData), &req); err != nil {
@@ -88,12 +103,18 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		fileHeader.Filename,
 		req.Gender,
 		req.City,
+		req.InterestedIn,
 		req.Age,
 		file,
 		fileHeader.Size,
 		req.TelegramID,
+		req.LanguageCode,
 	)
 
+	if errors.Is(err, usecase.ErrInvalidGender) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		log.Printf("Error in creating user: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
@@ -121,7 +142,12 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 // @Param max_age query int false "Maximum age of user"
 // @Param description query string false "Description filter"
 // @Param city query string false "City filter"
-// @Param gender query string false "Gender filter"
+// @Param gender query string false "Comma-separated gender codes of candidates"
+// @Param interested_in query string false "Gender code of the seeker, candidates must be interested in it"
+// @Param active_within query string false "Only users active within this duration, e.g. 72h"
+// @Param sort query string false "Sort order: recency or rank (ranked for the viewer from exclude_for)"
+// @Param exclude_for query int false "Telegram ID of the viewer: excludes own and hidden profiles"
+// @Param attributes query string false "Searchable attributes, e.g. goal=relationship, height_min=170"
 // @Success 200 {array} usecase.User "List of users"
 // @Failure 400 {string} string "Bad request"
 // @Failure 500 {string} string "Internal server error"
@@ -132,6 +158,11 @@ func (h *UserHandler) Search(c *gin.Context) {
 		MaxAge *int   `form:"max_age,omitempty"`
 		City   string `form:"city,omitempty"`
 		Gender string `form:"gender,omitempty"`
+
+		InterestedIn string `form:"interested_in,omitempty"`
+		ActiveWithin string `form:"active_within,omitempty"`
+		Sort         string `form:"sort,omitempty"`
+		ExcludeFor   int64  `form:"exclude_for,omitempty"`
 	}
 
 	// Привязываем параметры запроса
@@ -142,9 +173,49 @@ func (h *UserHandler) Search(c *gin.Context) {
 	var filter entity.UserFilter
 	filter.MinAge = req.MinAge
 	filter.MaxAge = req.MaxAge
-	filter.Gender = req.Gender
 	filter.City = req.City
+	filter.InterestedIn = req.InterestedIn
+	filter.ExcludeFor = req.ExcludeFor
+	if req.Gender != "" {
+		filter.Genders = strings.Split(req.Gender, ",")
+	}
+
+	if req.ActiveWithin != "" {
+		activeWithin, err := time.ParseDuration(req.ActiveWithin)
+		if err != nil || activeWithin <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid active_within"})
+			return
+		}
+		filter.ActiveWithin = activeWithin
+	}
+	if req.Sort != "" && req.Sort != entity.SortByRecency && req.Sort != entity.SortByRank {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort"})
+		return
+	}
+	filter.SortBy = req.Sort
+
+	// Все остальные параметры - фильтры по атрибутам анкеты
+	attrParams := make(map[string][]string)
+	for param, values := range c.Request.URL.Query() {
+		if !usecase.IsReservedSearchParam(param) {
+			attrParams[param] = values
+		}
+	}
+	conditions, err := h.attributes.ParseConditions(c.Request.Context(), attrParams)
+	if errors.Is(err, usecase.ErrInvalidAttribute) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Attributes = conditions
 	users, err := h.usecase.Search(c.Request.Context(), filter)
+	if errors.Is(err, usecase.ErrInvalidGender) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
@@ -203,6 +274,8 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 // @Param age formData int true "Age of the user"
 // @Param description formData string true "Description of the user"
 // @Param telegram_id formData int64 true "Telegram ID"
+// @Param gender formData string true "Gender code"
+// @Param interested_in formData []string true "Gender codes the user is interested in"
 // @Param file formData file true "User's new photo"
 // @Success 200 {string} string "User updated"
 // @Failure 400 {string} string "Bad request"
@@ -222,6 +295,8 @@ func (h *UserHandler) Update(c *gin.Context) {
 		City        string `json:"city,omitempty"`
 		Gender      string `json:"gender,omitempty"`
 		Description string `json:"description"`
+
+		InterestedIn []string `json:"interested_in"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(400, gin.H{"error": err.Error()})
@@ -238,7 +313,11 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 	defer file.Close()
-	err = h.usecase.Update(c.Request.Context(), req.Name, req.Description, fileHeader.Filename, req.Gender, req.City, req.Age, id, file, fileHeader.Size, req.TelegramID)
+	err = h.usecase.Update(c.Request.Context(), req.Name, req.Description, fileHeader.Filename, req.Gender, req.City, req.InterestedIn, req.Age, id, file, fileHeader.Size, req.TelegramID)
+	if errors.Is(err, usecase.ErrInvalidGender) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -268,3 +347,254 @@ func (h *UserHandler) Delete(c *gin.Context) {
 	}
 	c.Status(http.StatusOK)
 }
+
+// @Summary Touch user activity
+// @Description Record that the user was active right now
+// @Tags users
+// @Produce json
+// @Param id path int true "Telegram ID"
+// @Success 204 {string} string "Activity recorded"
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /users/{id}/activity [post]
+func (h *UserHandler) TouchActivity(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.usecase.TouchActivity(c.Request.Context(), id); err != nil {
+		log.Printf("Error touching activity of user %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get users by IDs
+// @Description Batch lookup of profiles by Telegram IDs for other services
+// @Tags users
+// @Produce json
+// @Param ids query string true "Comma-separated Telegram IDs"
+// @Success 200 {array} entity.User "Found users"
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /users/batch [get]
+func (h *UserHandler) GetByIDs(c *gin.Context) {
+	var ids []int64
+	for _, part := range strings.Split(c.Query("ids"), ",") {
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	users, err := h.usecase.GetByIDs(c.Request.Context(), ids)
+	if errors.Is(err, usecase.ErrBatchTooLarge) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching users %v: %v", ids, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// @Summary Hide user from feed
+// @Description Permanently removes a profile from the user's search results
+// @Tags users
+// @Param id path int true "Telegram ID"
+// @Param hidden_id path int true "Telegram ID of the hidden profile"
+// @Success 204 {string} string "Hidden"
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /users/{id}/hidden/{hidden_id} [put]
+func (h *UserHandler) HideUser(c *gin.Context) {
+	id, err1 := strconv.ParseInt(c.Param("id"), 10, 64)
+	hiddenID, err2 := strconv.ParseInt(c.Param("hidden_id"), 10, 64)
+	if err1 != nil || err2 != nil || id <= 0 || hiddenID <= 0 || id == hiddenID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.usecase.HideUser(c.Request.Context(), id, hiddenID); err != nil {
+		log.Printf("Error hiding user %d for %d: %v", hiddenID, id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Rate user
+// @Description Updates the Elo attractiveness rating of a profile after a like or dislike. With undo the update of an undone swipe is reverted
+// @Tags users
+// @Accept json
+// @Param id path int true "Telegram ID of the rated profile"
+// @Param body body object true "rater_id, liked and optional undo"
+// @Success 204 {string} string "Rated"
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /users/{id}/rating [post]
+func (h *UserHandler) RateUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req struct {
+		RaterID int64 `json:"rater_id"`
+		Liked   bool  `json:"liked"`
+		Undo    bool  `json:"undo"`
+	}
+	if err != nil || id <= 0 || c.ShouldBindJSON(&req) != nil || req.RaterID <= 0 || req.RaterID == id {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	rate := h.usecase.RateUser
+	if req.Undo {
+		rate = h.usecase.RevertRating
+	}
+	if err := rate(c.Request.Context(), id, req.RaterID, req.Liked); err != nil {
+		log.Printf("Error rating user %d by %d: %v", id, req.RaterID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Set user location
+// @Description Stores coordinates used by the distance ranking signal
+// @Tags users
+// @Accept json
+// @Param id path int true "Telegram ID"
+// @Param body body entity.GeoPoint true "Coordinates"
+// @Success 204 {string} string "Location saved"
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /users/{id}/location [put]
+func (h *UserHandler) SetLocation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	var point entity.GeoPoint
+	if err := c.ShouldBindJSON(&point); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.usecase.SetLocation(c.Request.Context(), id, point)
+	if errors.Is(err, usecase.ErrInvalidLocation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Error setting location of user %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Set user language
+// @Description Stores the Telegram language_code used to localize bot messages
+// @Tags users
+// @Accept json
+// @Param id path int true "Telegram ID"
+// @Param body body object true "{\"language_code\": \"en\"}"
+// @Success 204 {string} string "Language saved"
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "User not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /users/{id}/language [put]
+func (h *UserHandler) SetLanguage(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	var body struct {
+		LanguageCode string `json:"language_code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.usecase.SetLanguage(c.Request.Context(), id, body.LanguageCode)
+	if errors.Is(err, usecase.ErrInvalidLanguage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, usecase.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Error setting language of user %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Reactivate user
+// @Description Bring back to search a user who blocked the bot and started it again
+// @Tags users
+// @Param id path int true "Telegram ID"
+// @Success 204 {string} string "User reactivated"
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /users/{id}/reactivate [post]
+func (h *UserHandler) Reactivate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if err := h.usecase.Reactivate(c.Request.Context(), id); err != nil {
+		log.Printf("Error reactivating user %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Inactive users with new profiles nearby
+// @Description Users inactive for inactive_for and the number of new profiles in their city since their last visit
+// @Tags users
+// @Produce json
+// @Param inactive_for query string false "Inactivity duration, default 168h"
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /users/inactive [get]
+func (h *UserHandler) Inactive(c *gin.Context) {
+	inactiveFor, err := time.ParseDuration(c.DefaultQuery("inactive_for", "168h"))
+	if err != nil || inactiveFor <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid inactive_for"})
+		return
+	}
+	limit, err1 := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	offset, err2 := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err1 != nil || err2 != nil || limit < 0 || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit or offset"})
+		return
+	}
+
+	users, hasMore, err := h.usecase.InactiveUsers(c.Request.Context(), inactiveFor, limit, offset)
+	if err != nil {
+		log.Printf("Error loading inactive users: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": users, "has_more": hasMore})
+}
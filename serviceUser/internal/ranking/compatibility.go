@@ -0,0 +1,71 @@
+package ranking
+
+import (
+	"math"
+	"service1/internal/entity"
+)
+
+// importanceWeights - вес вопроса по важности, как в OkCupid:
+// неважный вопрос не учитывается, обязательный перевешивает все остальные
+var importanceWeights = map[int]float64{
+	entity.ImportanceIrrelevant: 0,
+	entity.ImportanceLittle:     1,
+	entity.ImportanceSomewhat:   10,
+	entity.ImportanceVery:       50,
+	entity.ImportanceMandatory:  250,
+}
+
+// Compatibility - процент совместимости по общим вопросам анкеты.
+// Для каждого из двоих считается, насколько ответы другого его устраивают
+// (с учетом важности), итог - среднее геометрическое минус погрешность 1/n,
+// чтобы пара с одним общим вопросом не получала 100%.
+// common - число общих вопросов; при common == 0 процент не определен
+func Compatibility(a, b []entity.QuestionAnswer) (percent int, common int) {
+	byQuestion := make(map[int]entity.QuestionAnswer, len(b))
+	for _, answer := range b {
+		byQuestion[answer.QuestionID] = answer
+	}
+
+	var earnedA, possibleA, earnedB, possibleB float64
+	for _, answerA := range a {
+		answerB, ok := byQuestion[answerA.QuestionID]
+		if !ok {
+			continue
+		}
+		common++
+
+		wA := importanceWeights[answerA.Importance]
+		possibleA += wA
+		if containsInt(answerA.Acceptable, answerB.Answer) {
+			earnedA += wA
+		}
+		wB := importanceWeights[answerB.Importance]
+		possibleB += wB
+		if containsInt(answerB.Acceptable, answerA.Answer) {
+			earnedB += wB
+		}
+	}
+	if common == 0 {
+		return 0, 0
+	}
+
+	match := math.Sqrt(satisfaction(earnedA, possibleA)*satisfaction(earnedB, possibleB)) - 1/float64(common)
+	return int(math.Round(math.Max(match, 0) * 100)), common
+}
+
+// satisfaction - доля набранного веса; если все вопросы неважны, человека устраивает все
+func satisfaction(earned, possible float64) float64 {
+	if possible == 0 {
+		return 1
+	}
+	return earned / possible
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
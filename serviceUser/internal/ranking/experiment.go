@@ -0,0 +1,75 @@
+package ranking
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Variant - вариант ранжирования: веса скореров и доля трафика в процентах
+type Variant struct {
+	Name    string
+	Weights Weights
+	Traffic int
+}
+
+// Experiment - A/B-эксперимент над ранжированием. Пользователь попадает
+// в вариант детерминированно по Telegram ID, поэтому видит одну и ту же выдачу
+// между запросами и перезапусками
+type Experiment struct {
+	Name     string
+	Variants []Variant
+}
+
+// DefaultExperiment - контроль против варианта с большим весом Elo
+func DefaultExperiment() Experiment {
+	return Experiment{
+		Name: "ranking-v1",
+		Variants: []Variant{
+			{
+				Name:    "control",
+				Traffic: 50,
+				Weights: Weights{
+					ScorerActivity:     0.3,
+					ScorerCompleteness: 0.15,
+					ScorerPreference:   0.2,
+					ScorerDistance:     0.15,
+					ScorerSharedTags:   0.1,
+					ScorerElo:          0.1,
+
+					ScorerCompatibility: 0.15,
+				},
+			},
+			{
+				Name:    "elo_boost",
+				Traffic: 50,
+				Weights: Weights{
+					ScorerActivity:     0.25,
+					ScorerCompleteness: 0.1,
+					ScorerPreference:   0.15,
+					ScorerDistance:     0.1,
+					ScorerSharedTags:   0.1,
+					ScorerElo:          0.3,
+
+					ScorerCompatibility: 0.15,
+				},
+			},
+		},
+	}
+}
+
+// Assign - вариант пользователя. Бакет 0..99 считается по хэшу имени эксперимента
+// и Telegram ID, так что в разных экспериментах разбиение независимое.
+// Если доли в сумме меньше 100, остаток трафика получает первый вариант
+func (e Experiment) Assign(telegramID int64) Variant {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d", e.Name, telegramID)
+	bucket := int(h.Sum32() % 100)
+
+	for _, v := range e.Variants {
+		if bucket < v.Traffic {
+			return v
+		}
+		bucket -= v.Traffic
+	}
+	return e.Variants[0]
+}
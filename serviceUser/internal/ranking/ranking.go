@@ -0,0 +1,70 @@
+package ranking
+
+import (
+	"service1/internal/entity"
+	"sort"
+)
+
+// Scorer - один сигнал ранжирования. Score возвращает оценку кандидата
+// для зрителя в диапазоне [0, 1]
+type Scorer interface {
+	Name() string
+	Score(viewer, candidate *entity.User) float64
+}
+
+// Weights - вес каждого скорера по имени; скореры без веса не считаются
+type Weights map[string]float64
+
+// Scored - кандидат с итоговой оценкой
+type Scored struct {
+	User  entity.User
+	Score float64
+}
+
+// Ranker - взвешенная сумма оценок зарегистрированных скореров
+type Ranker struct {
+	scorers []Scorer
+}
+
+func NewRanker(scorers ...Scorer) *Ranker {
+	return &Ranker{scorers: scorers}
+}
+
+// Rank - сортирует кандидатов по убыванию оценки. При равной оценке
+// сохраняется исходный порядок, чтобы выдача была детерминированной
+func (r *Ranker) Rank(viewer *entity.User, candidates []entity.User, weights Weights) []Scored {
+	scored := make([]Scored, 0, len(candidates))
+	for i := range candidates {
+		scored = append(scored, Scored{User: candidates[i], Score: r.score(viewer, &candidates[i], weights)})
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+	return scored
+}
+
+func (r *Ranker) score(viewer, candidate *entity.User, weights Weights) float64 {
+	var total, weightSum float64
+	for _, s := range r.scorers {
+		w := weights[s.Name()]
+		if w <= 0 {
+			continue
+		}
+		total += w * clamp(s.Score(viewer, candidate))
+		weightSum += w
+	}
+	if weightSum == 0 {
+		return 0
+	}
+	return total / weightSum
+}
+
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,150 @@
+package ranking
+
+import (
+	"service1/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// constScorer - скорер с заранее заданными оценками по Telegram ID
+type constScorer struct {
+	name   string
+	scores map[int64]float64
+}
+
+func (s constScorer) Name() string { return s.name }
+
+func (s constScorer) Score(viewer, candidate *entity.User) float64 {
+	return s.scores[candidate.TelegramID]
+}
+
+func TestRanker_Rank(t *testing.T) {
+	ranker := NewRanker(
+		constScorer{name: "a", scores: map[int64]float64{1: 1, 2: 0, 3: 0.5}},
+		constScorer{name: "b", scores: map[int64]float64{1: 0, 2: 1, 3: 0.5}},
+	)
+	candidates := []entity.User{{TelegramID: 1}, {TelegramID: 2}, {TelegramID: 3}}
+	viewer := &entity.User{TelegramID: 100}
+
+	ids := func(scored []Scored) []int64 {
+		var out []int64
+		for _, s := range scored {
+			out = append(out, s.User.TelegramID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []int64{1, 3, 2}, ids(ranker.Rank(viewer, candidates, Weights{"a": 1})))
+	assert.Equal(t, []int64{2, 3, 1}, ids(ranker.Rank(viewer, candidates, Weights{"a": 1, "b": 3})))
+	// Равные оценки - исходный порядок
+	assert.Equal(t, []int64{1, 2, 3}, ids(ranker.Rank(viewer, candidates, Weights{"a": 1, "b": 1})))
+	// Без весов все оценки нулевые
+	scored := ranker.Rank(viewer, candidates, nil)
+	assert.Equal(t, 0.0, scored[0].Score)
+}
+
+func TestScorers(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	viewer := &entity.User{
+		Age: 25, Gender: entity.GenderMale, City: "Москва",
+		InterestedIn: []string{entity.GenderFemale},
+		Attributes:   entity.AttributeValues{"languages": {"ru", "en"}},
+	}
+
+	t.Run("activity decays by half life", func(t *testing.T) {
+		s := ActivityScorer{HalfLife: 24 * time.Hour, Now: func() time.Time { return now }}
+		assert.InDelta(t, 1, s.Score(viewer, &entity.User{LastActiveAt: now}), 1e-9)
+		assert.InDelta(t, 0.5, s.Score(viewer, &entity.User{LastActiveAt: now.Add(-24 * time.Hour)}), 1e-9)
+		assert.Equal(t, 0.0, s.Score(viewer, &entity.User{}))
+	})
+
+	t.Run("mutual preference", func(t *testing.T) {
+		s := PreferenceScorer{MaxAgeGap: 10}
+		fit := &entity.User{Age: 25, Gender: entity.GenderFemale, InterestedIn: []string{entity.GenderMale}}
+		oneSided := &entity.User{Age: 35, Gender: entity.GenderFemale, InterestedIn: []string{entity.GenderFemale}}
+		assert.InDelta(t, 1, s.Score(viewer, fit), 1e-9)
+		assert.InDelta(t, 0.35, s.Score(viewer, oneSided), 1e-9)
+	})
+
+	t.Run("distance by coordinates and by city", func(t *testing.T) {
+		s := DistanceScorer{MaxKm: 50}
+		withPoint := *viewer
+		withPoint.Location = &entity.GeoPoint{Latitude: 55.75, Longitude: 37.62}
+		near := &entity.User{Location: &entity.GeoPoint{Latitude: 55.76, Longitude: 37.63}}
+		far := &entity.User{Location: &entity.GeoPoint{Latitude: 59.93, Longitude: 30.33}}
+		assert.Greater(t, s.Score(&withPoint, near), 0.9)
+		assert.Equal(t, 0.0, s.Score(&withPoint, far))
+		assert.Equal(t, 1.0, s.Score(viewer, &entity.User{City: "Москва"}))
+		assert.Equal(t, 0.5, s.Score(viewer, &entity.User{City: "Казань"}))
+	})
+
+	t.Run("shared tags", func(t *testing.T) {
+		s := SharedTagsScorer{}
+		candidate := &entity.User{Attributes: entity.AttributeValues{"languages": {"en", "de"}}}
+		assert.InDelta(t, 1.0/3, s.Score(viewer, candidate), 1e-9)
+		assert.Equal(t, 0.0, s.Score(viewer, &entity.User{}))
+	})
+
+	t.Run("elo", func(t *testing.T) {
+		s := EloScorer{}
+		assert.InDelta(t, 0.5, s.Score(viewer, &entity.User{Rating: entity.DefaultRating}), 1e-9)
+		assert.Greater(t, s.Score(viewer, &entity.User{Rating: 1700}), 0.5)
+	})
+}
+
+func TestExperiment_Assign(t *testing.T) {
+	experiment := DefaultExperiment()
+
+	counts := make(map[string]int)
+	for id := int64(1); id <= 10000; id++ {
+		variant := experiment.Assign(id)
+		// Назначение детерминированное
+		assert.Equal(t, variant.Name, experiment.Assign(id).Name)
+		counts[variant.Name]++
+	}
+	assert.InDelta(t, 5000, counts["control"], 300)
+	assert.InDelta(t, 5000, counts["elo_boost"], 300)
+
+	single := Experiment{Name: "x", Variants: []Variant{{Name: "only", Traffic: 10}}}
+	assert.Equal(t, "only", single.Assign(42).Name)
+}
+
+func TestCompatibility(t *testing.T) {
+	answer := func(id, own int, acceptable []int, importance int) entity.QuestionAnswer {
+		return entity.QuestionAnswer{QuestionID: id, Answer: own, Acceptable: acceptable, Importance: importance}
+	}
+
+	// Нет общих вопросов - процент не определен
+	percent, common := Compatibility(
+		[]entity.QuestionAnswer{answer(1, 0, []int{0}, entity.ImportanceVery)},
+		[]entity.QuestionAnswer{answer(2, 0, []int{0}, entity.ImportanceVery)},
+	)
+	assert.Equal(t, 0, common)
+	assert.Equal(t, 0, percent)
+
+	// Полное совпадение по 10 вопросам: 100% минус погрешность 1/10
+	var a, b []entity.QuestionAnswer
+	for id := 1; id <= 10; id++ {
+		a = append(a, answer(id, 0, []int{0, 1}, entity.ImportanceSomewhat))
+		b = append(b, answer(id, 1, []int{0}, entity.ImportanceVery))
+	}
+	percent, common = Compatibility(a, b)
+	assert.Equal(t, 10, common)
+	assert.Equal(t, 90, percent)
+
+	// Проваленный обязательный вопрос перевешивает совпадения по остальным
+	a[0] = answer(1, 0, []int{0}, entity.ImportanceMandatory)
+	percent, _ = Compatibility(a, b)
+	assert.Less(t, percent, 50)
+
+	// Неважный вопрос не влияет на процент
+	a[0] = answer(1, 0, []int{0}, entity.ImportanceIrrelevant)
+	percent, _ = Compatibility(a, b)
+	assert.Equal(t, 90, percent)
+
+	// Совместимость симметрична
+	reversed, _ := Compatibility(b, a)
+	assert.Equal(t, percent, reversed)
+}
@@ -0,0 +1,189 @@
+package ranking
+
+import (
+	"math"
+	"service1/internal/entity"
+	"time"
+)
+
+// Имена скореров, по ним задаются веса в вариантах эксперимента
+const (
+	ScorerActivity     = "activity"
+	ScorerCompleteness = "completeness"
+	ScorerPreference   = "preference"
+	ScorerDistance     = "distance"
+	ScorerSharedTags   = "shared_tags"
+	ScorerElo          = "elo"
+	// ScorerCompatibility - процент совместимости по анкете вопросов
+	ScorerCompatibility = "compatibility"
+)
+
+// DefaultScorers - набор скореров, с которым работает выдача
+func DefaultScorers() []Scorer {
+	return []Scorer{
+		ActivityScorer{HalfLife: 72 * time.Hour, Now: time.Now},
+		CompletenessScorer{},
+		PreferenceScorer{MaxAgeGap: 10},
+		DistanceScorer{MaxKm: 50},
+		SharedTagsScorer{},
+		EloScorer{},
+		CompatibilityScorer{},
+	}
+}
+
+// ActivityScorer - чем недавнее активность, тем выше оценка; за HalfLife оценка падает вдвое
+type ActivityScorer struct {
+	HalfLife time.Duration
+	Now      func() time.Time
+}
+
+func (ActivityScorer) Name() string { return ScorerActivity }
+
+func (s ActivityScorer) Score(viewer, candidate *entity.User) float64 {
+	if candidate.LastActiveAt.IsZero() {
+		return 0
+	}
+	idle := s.Now().Sub(candidate.LastActiveAt)
+	if idle <= 0 {
+		return 1
+	}
+	return math.Exp2(-idle.Hours() / s.HalfLife.Hours())
+}
+
+// CompletenessScorer - заполненность анкеты: фото, описание, город, ответы на вопросы, атрибуты
+type CompletenessScorer struct{}
+
+func (CompletenessScorer) Name() string { return ScorerCompleteness }
+
+func (CompletenessScorer) Score(viewer, candidate *entity.User) float64 {
+	var score float64
+	if candidate.Photo != "" {
+		score += 0.3
+	}
+	// Длинное описание лучше короткого, но выше 200 символов разницы нет
+	score += 0.2 * math.Min(float64(len([]rune(candidate.Description)))/200, 1)
+	if candidate.City != "" {
+		score += 0.1
+	}
+	score += 0.25 * math.Min(float64(len(candidate.Prompts))/3, 1)
+	score += 0.15 * math.Min(float64(len(candidate.Attributes))/5, 1)
+	return score
+}
+
+// PreferenceScorer - взаимное соответствие предпочтениям: оба интересуются полом
+// друг друга, а разница в возрасте небольшая
+type PreferenceScorer struct {
+	MaxAgeGap int
+}
+
+func (PreferenceScorer) Name() string { return ScorerPreference }
+
+func (s PreferenceScorer) Score(viewer, candidate *entity.User) float64 {
+	var score float64
+	if contains(viewer.InterestedIn, candidate.Gender) {
+		score += 0.35
+	}
+	if contains(candidate.InterestedIn, viewer.Gender) {
+		score += 0.35
+	}
+	gap := math.Abs(float64(viewer.Age - candidate.Age))
+	score += 0.3 * math.Max(1-gap/float64(s.MaxAgeGap), 0)
+	return score
+}
+
+// DistanceScorer - близость по координатам; без координат сравниваются города
+type DistanceScorer struct {
+	MaxKm float64
+}
+
+func (DistanceScorer) Name() string { return ScorerDistance }
+
+func (s DistanceScorer) Score(viewer, candidate *entity.User) float64 {
+	if viewer.Location != nil && candidate.Location != nil {
+		km := haversineKm(*viewer.Location, *candidate.Location)
+		return math.Max(1-km/s.MaxKm, 0)
+	}
+	if viewer.City != "" && viewer.City == candidate.City {
+		return 1
+	}
+	// Расстояние неизвестно: нейтральная оценка
+	return 0.5
+}
+
+// SharedTagsScorer - доля общих значений атрибутов анкеты (языки, интересы и т.д.)
+type SharedTagsScorer struct{}
+
+func (SharedTagsScorer) Name() string { return ScorerSharedTags }
+
+func (SharedTagsScorer) Score(viewer, candidate *entity.User) float64 {
+	var shared, union int
+	seen := make(map[string]bool)
+	for key, values := range viewer.Attributes {
+		for _, v := range values {
+			seen[key+"="+v] = true
+			union++
+		}
+	}
+	for key, values := range candidate.Attributes {
+		for _, v := range values {
+			if seen[key+"="+v] {
+				shared++
+			} else {
+				union++
+			}
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// EloScorer - привлекательность по Elo-рейтингу: ожидаемый исход
+// против анкеты со стартовым рейтингом
+type EloScorer struct{}
+
+func (EloScorer) Name() string { return ScorerElo }
+
+func (EloScorer) Score(viewer, candidate *entity.User) float64 {
+	return ExpectedScore(candidate.Rating, entity.DefaultRating)
+}
+
+// CompatibilityScorer - совместимость по анкете вопросов. Процент заранее
+// посчитан для зрителя; если общих вопросов нет, оценка нейтральная
+type CompatibilityScorer struct{}
+
+func (CompatibilityScorer) Name() string { return ScorerCompatibility }
+
+func (CompatibilityScorer) Score(viewer, candidate *entity.User) float64 {
+	if candidate.Compatibility == nil {
+		return 0.5
+	}
+	return float64(*candidate.Compatibility) / 100
+}
+
+// EloK - шаг изменения рейтинга за один свайп
+const EloK = 32
+
+// ExpectedScore - вероятность, что анкета с рейтингом rating «выиграет» у opponent
+func ExpectedScore(rating, opponent float64) float64 {
+	return 1 / (1 + math.Pow(10, (opponent-rating)/400))
+}
+
+func contains(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func haversineKm(a, b entity.GeoPoint) float64 {
+	const earthRadiusKm = 6371
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
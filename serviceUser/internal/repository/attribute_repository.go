@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service1/internal/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNotFound - запись не найдена
+var ErrNotFound = errors.New("not found")
+
+type AttributeRepository struct {
+	Pool   *pgxpool.Pool
+	Logger *logrus.Logger
+}
+
+func NewAttributeRepository(pool *pgxpool.Pool, logger *logrus.Logger) *AttributeRepository {
+	return &AttributeRepository{Pool: pool, Logger: logger}
+}
+
+func (r *AttributeRepository) ListDefinitions(ctx context.Context) ([]entity.AttributeDefinition, error) {
+	query := `SELECT key, label, type, allowed_values, value_labels, min_value, max_value, searchable FROM attribute_definitions ORDER BY created_at, key`
+
+	rows, err := r.Pool.Query(ctx, query)
+	if err != nil {
+		r.Logger.Error("Error listing attribute definitions: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var defs []entity.AttributeDefinition
+	for rows.Next() {
+		var def entity.AttributeDefinition
+		if err := rows.Scan(&def.Key, &def.Label, &def.Type, &def.AllowedValues, &def.ValueLabels, &def.Min, &def.Max, &def.Searchable); err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+	return defs, rows.Err()
+}
+
+func (r *AttributeRepository) CreateDefinition(ctx context.Context, def *entity.AttributeDefinition) error {
+	query := `INSERT INTO attribute_definitions (key, label, type, allowed_values, value_labels, min_value, max_value, searchable) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	r.Logger.WithFields(logrus.Fields{
+		"key":  def.Key,
+		"type": def.Type,
+	}).Info("Executing CreateDefinition query")
+
+	_, err := r.Pool.Exec(ctx, query, def.Key, def.Label, def.Type, nonNil(def.AllowedValues), nonNilLabels(def.ValueLabels), def.Min, def.Max, def.Searchable)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"key": def.Key,
+		}).Error("Error creating attribute definition: ", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateDefinition меняет описание атрибута. Ключ и тип не меняются, чтобы
+// не ломать уже сохраненные значения.
+func (r *AttributeRepository) UpdateDefinition(ctx context.Context, def *entity.AttributeDefinition) error {
+	query := `UPDATE attribute_definitions SET label = $1, allowed_values = $2, value_labels = $3, min_value = $4, max_value = $5, searchable = $6 WHERE key = $7`
+
+	r.Logger.WithFields(logrus.Fields{
+		"key": def.Key,
+	}).Info("Executing UpdateDefinition query")
+
+	tag, err := r.Pool.Exec(ctx, query, def.Label, nonNil(def.AllowedValues), nonNilLabels(def.ValueLabels), def.Min, def.Max, def.Searchable, def.Key)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"key": def.Key,
+		}).Error("Error updating attribute definition: ", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *AttributeRepository) DeleteDefinition(ctx context.Context, key string) error {
+	query := `DELETE FROM attribute_definitions WHERE key = $1`
+
+	r.Logger.WithFields(logrus.Fields{
+		"key": key,
+	}).Info("Executing DeleteDefinition query")
+
+	tag, err := r.Pool.Exec(ctx, query, key)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"key": key,
+		}).Error("Error deleting attribute definition: ", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SetUserAttribute заменяет значения атрибута пользователя целиком
+func (r *AttributeRepository) SetUserAttribute(ctx context.Context, telegramID int64, key string, values []string, num *int) error {
+	r.Logger.WithFields(logrus.Fields{
+		"user_telegram_ID": telegramID,
+		"key":              key,
+		"values":           values,
+	}).Info("Executing SetUserAttribute query")
+
+	return pgx.BeginFunc(ctx, r.Pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM user_attributes WHERE telegram_id = $1 AND key = $2`, telegramID, key); err != nil {
+			return err
+		}
+		for _, value := range values {
+			if _, err := tx.Exec(ctx, `INSERT INTO user_attributes (telegram_id, key, value, num_value) VALUES ($1, $2, $3, $4)`, telegramID, key, value, num); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *AttributeRepository) DeleteUserAttribute(ctx context.Context, telegramID int64, key string) error {
+	query := `DELETE FROM user_attributes WHERE telegram_id = $1 AND key = $2`
+
+	r.Logger.WithFields(logrus.Fields{
+		"user_telegram_ID": telegramID,
+		"key":              key,
+	}).Info("Executing DeleteUserAttribute query")
+
+	_, err := r.Pool.Exec(ctx, query, telegramID, key)
+	return err
+}
+
+// loadAttributes достает значения атрибутов сразу для нескольких пользователей
+func loadAttributes(ctx context.Context, pool *pgxpool.Pool, telegramIDs []int64) (map[int64]entity.AttributeValues, error) {
+	result := make(map[int64]entity.AttributeValues, len(telegramIDs))
+	if len(telegramIDs) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT telegram_id, key, value FROM user_attributes WHERE telegram_id = ANY($1) ORDER BY telegram_id, key, value`
+	rows, err := pool.Query(ctx, query, telegramIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			telegramID int64
+			key, value string
+		)
+		if err := rows.Scan(&telegramID, &key, &value); err != nil {
+			return nil, err
+		}
+		if result[telegramID] == nil {
+			result[telegramID] = entity.AttributeValues{}
+		}
+		result[telegramID][key] = append(result[telegramID][key], value)
+	}
+	return result, rows.Err()
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func nonNilLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return map[string]string{}
+	}
+	return labels
+}
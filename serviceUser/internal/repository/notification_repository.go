@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service1/internal/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type NotificationRepository struct {
+	Pool   *pgxpool.Pool
+	Logger *logrus.Logger
+}
+
+func NewNotificationRepository(pool *pgxpool.Pool, logger *logrus.Logger) *NotificationRepository {
+	return &NotificationRepository{Pool: pool, Logger: logger}
+}
+
+// NotificationSettings - настройки уведомлений; если пользователь их не менял,
+// возвращаются настройки по умолчанию
+func (r *NotificationRepository) NotificationSettings(ctx context.Context, telegramID int64) (entity.NotificationSettings, error) {
+	query := `SELECT disabled, quiet_from, quiet_to, time_zone, mode FROM notification_settings WHERE telegram_id = $1`
+
+	s := entity.DefaultNotificationSettings()
+	err := r.Pool.QueryRow(ctx, query, telegramID).Scan(&s.Disabled, &s.QuietFrom, &s.QuietTo, &s.TimeZone, &s.Mode)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.DefaultNotificationSettings(), nil
+	}
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID": telegramID,
+		}).Error("Error getting notification settings: ", err)
+		return s, err
+	}
+	return s, nil
+}
+
+// SaveNotificationSettings - сохраняет настройки уведомлений. Анкеты нет - ErrNotFound
+func (r *NotificationRepository) SaveNotificationSettings(ctx context.Context, telegramID int64, s entity.NotificationSettings) error {
+	query := `
+		INSERT INTO notification_settings (telegram_id, disabled, quiet_from, quiet_to, time_zone, mode)
+		SELECT telegram_id, $2, $3, $4, $5, $6 FROM users WHERE telegram_id = $1
+		ON CONFLICT (telegram_id) DO UPDATE
+		SET disabled = EXCLUDED.disabled, quiet_from = EXCLUDED.quiet_from, quiet_to = EXCLUDED.quiet_to,
+			time_zone = EXCLUDED.time_zone, mode = EXCLUDED.mode, updated_at = now()`
+
+	tag, err := r.Pool.Exec(ctx, query, telegramID, s.Disabled, s.QuietFrom, s.QuietTo, s.TimeZone, s.Mode)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID": telegramID,
+		}).Error("Error saving notification settings: ", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service1/internal/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type PromptRepository struct {
+	Pool   *pgxpool.Pool
+	Logger *logrus.Logger
+}
+
+func NewPromptRepository(pool *pgxpool.Pool, logger *logrus.Logger) *PromptRepository {
+	return &PromptRepository{Pool: pool, Logger: logger}
+}
+
+func (r *PromptRepository) ListPrompts(ctx context.Context, onlyActive bool) ([]entity.Prompt, error) {
+	query := `SELECT id, text, active FROM prompts WHERE active OR NOT $1 ORDER BY id`
+
+	rows, err := r.Pool.Query(ctx, query, onlyActive)
+	if err != nil {
+		r.Logger.Error("Error listing prompts: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prompts []entity.Prompt
+	for rows.Next() {
+		var p entity.Prompt
+		if err := rows.Scan(&p.ID, &p.Text, &p.Active); err != nil {
+			return nil, err
+		}
+		prompts = append(prompts, p)
+	}
+	return prompts, rows.Err()
+}
+
+func (r *PromptRepository) GetPrompt(ctx context.Context, id int) (*entity.Prompt, error) {
+	query := `SELECT id, text, active FROM prompts WHERE id = $1`
+
+	p := &entity.Prompt{}
+	err := r.Pool.QueryRow(ctx, query, id).Scan(&p.ID, &p.Text, &p.Active)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"prompt_id": id,
+		}).Error("Error getting prompt: ", err)
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *PromptRepository) CreatePrompt(ctx context.Context, text string) (int, error) {
+	query := `INSERT INTO prompts (text) VALUES ($1) RETURNING id`
+
+	r.Logger.WithFields(logrus.Fields{
+		"text": text,
+	}).Info("Executing CreatePrompt query")
+
+	var id int
+	if err := r.Pool.QueryRow(ctx, query, text).Scan(&id); err != nil {
+		r.Logger.Error("Error creating prompt: ", err)
+		return 0, err
+	}
+	return id, nil
+}
+
+// SetPromptActive скрывает вопрос из каталога или возвращает его. Уже данные
+// ответы остаются в анкетах.
+func (r *PromptRepository) SetPromptActive(ctx context.Context, id int, active bool) error {
+	tag, err := r.Pool.Exec(ctx, `UPDATE prompts SET active = $1 WHERE id = $2`, active, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PromptRepository) UserPrompts(ctx context.Context, telegramID int64) ([]entity.PromptAnswer, error) {
+	answers, err := loadPrompts(ctx, r.Pool, []int64{telegramID})
+	if err != nil {
+		return nil, err
+	}
+	return answers[telegramID], nil
+}
+
+func (r *PromptRepository) UpsertUserPrompt(ctx context.Context, telegramID int64, promptID int, answer string) error {
+	query := `
+		INSERT INTO user_prompts (telegram_id, prompt_id, answer) VALUES ($1, $2, $3)
+		ON CONFLICT (telegram_id, prompt_id) DO UPDATE SET answer = EXCLUDED.answer, updated_at = now()`
+
+	r.Logger.WithFields(logrus.Fields{
+		"user_telegram_ID": telegramID,
+		"prompt_id":        promptID,
+	}).Info("Executing UpsertUserPrompt query")
+
+	_, err := r.Pool.Exec(ctx, query, telegramID, promptID, answer)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID": telegramID,
+		}).Error("Error saving prompt answer: ", err)
+	}
+	return err
+}
+
+func (r *PromptRepository) DeleteUserPrompt(ctx context.Context, telegramID int64, promptID int) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM user_prompts WHERE telegram_id = $1 AND prompt_id = $2`, telegramID, promptID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// loadPrompts достает ответы на вопросы сразу для нескольких пользователей
+func loadPrompts(ctx context.Context, pool *pgxpool.Pool, telegramIDs []int64) (map[int64][]entity.PromptAnswer, error) {
+	result := make(map[int64][]entity.PromptAnswer, len(telegramIDs))
+	if len(telegramIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT up.telegram_id, p.id, p.text, up.answer
+		FROM user_prompts up
+		JOIN prompts p ON p.id = up.prompt_id
+		WHERE up.telegram_id = ANY($1)
+		ORDER BY up.telegram_id, up.created_at, p.id`
+	rows, err := pool.Query(ctx, query, telegramIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			telegramID int64
+			answer     entity.PromptAnswer
+		)
+		if err := rows.Scan(&telegramID, &answer.PromptID, &answer.Question, &answer.Answer); err != nil {
+			return nil, err
+		}
+		result[telegramID] = append(result[telegramID], answer)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"service1/internal/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type QuestionRepository struct {
+	Pool   *pgxpool.Pool
+	Logger *logrus.Logger
+}
+
+func NewQuestionRepository(pool *pgxpool.Pool, logger *logrus.Logger) *QuestionRepository {
+	return &QuestionRepository{Pool: pool, Logger: logger}
+}
+
+func (r *QuestionRepository) ListQuestions(ctx context.Context, onlyActive bool) ([]entity.Question, error) {
+	query := `SELECT id, text, options, active FROM questions WHERE active OR NOT $1 ORDER BY id`
+
+	rows, err := r.Pool.Query(ctx, query, onlyActive)
+	if err != nil {
+		r.Logger.Error("Error listing questions: ", err)
+		return nil, err
+	}
+	return collectQuestions(rows)
+}
+
+func (r *QuestionRepository) GetQuestion(ctx context.Context, id int) (*entity.Question, error) {
+	query := `SELECT id, text, options, active FROM questions WHERE id = $1`
+
+	q := &entity.Question{}
+	err := r.Pool.QueryRow(ctx, query, id).Scan(&q.ID, &q.Text, &q.Options, &q.Active)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"question_id": id,
+		}).Error("Error getting question: ", err)
+		return nil, err
+	}
+	return q, nil
+}
+
+// UnansweredQuestions - активные вопросы, на которые пользователь еще не ответил, по порядку
+func (r *QuestionRepository) UnansweredQuestions(ctx context.Context, telegramID int64, limit int) ([]entity.Question, error) {
+	query := `
+		SELECT q.id, q.text, q.options, q.active
+		FROM questions q
+		WHERE q.active AND NOT EXISTS (
+			SELECT 1 FROM user_questions uq WHERE uq.telegram_id = $1 AND uq.question_id = q.id
+		)
+		ORDER BY q.id
+		LIMIT $2`
+
+	rows, err := r.Pool.Query(ctx, query, telegramID, limit)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID": telegramID,
+		}).Error("Error listing unanswered questions: ", err)
+		return nil, err
+	}
+	return collectQuestions(rows)
+}
+
+func (r *QuestionRepository) CreateQuestion(ctx context.Context, text string, options []string) (int, error) {
+	query := `INSERT INTO questions (text, options) VALUES ($1, $2) RETURNING id`
+
+	r.Logger.WithFields(logrus.Fields{
+		"text": text,
+	}).Info("Executing CreateQuestion query")
+
+	var id int
+	if err := r.Pool.QueryRow(ctx, query, text, options).Scan(&id); err != nil {
+		r.Logger.Error("Error creating question: ", err)
+		return 0, err
+	}
+	return id, nil
+}
+
+// SetQuestionActive скрывает вопрос или возвращает его. Данные ответы
+// продолжают учитываться в совместимости.
+func (r *QuestionRepository) SetQuestionActive(ctx context.Context, id int, active bool) error {
+	tag, err := r.Pool.Exec(ctx, `UPDATE questions SET active = $1 WHERE id = $2`, active, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *QuestionRepository) UserAnswers(ctx context.Context, telegramIDs []int64) (map[int64][]entity.QuestionAnswer, error) {
+	return loadQuestionAnswers(ctx, r.Pool, telegramIDs)
+}
+
+func (r *QuestionRepository) UpsertAnswer(ctx context.Context, telegramID int64, answer entity.QuestionAnswer) error {
+	query := `
+		INSERT INTO user_questions (telegram_id, question_id, answer, acceptable, importance) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (telegram_id, question_id) DO UPDATE SET
+			answer = EXCLUDED.answer, acceptable = EXCLUDED.acceptable, importance = EXCLUDED.importance, updated_at = now()`
+
+	r.Logger.WithFields(logrus.Fields{
+		"user_telegram_ID": telegramID,
+		"question_id":      answer.QuestionID,
+	}).Info("Executing UpsertAnswer query")
+
+	_, err := r.Pool.Exec(ctx, query, telegramID, answer.QuestionID, answer.Answer, answer.Acceptable, answer.Importance)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID": telegramID,
+		}).Error("Error saving question answer: ", err)
+	}
+	return err
+}
+
+func (r *QuestionRepository) DeleteAnswer(ctx context.Context, telegramID int64, questionID int) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM user_questions WHERE telegram_id = $1 AND question_id = $2`, telegramID, questionID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func collectQuestions(rows pgx.Rows) ([]entity.Question, error) {
+	defer rows.Close()
+	questions := []entity.Question{}
+	for rows.Next() {
+		var q entity.Question
+		if err := rows.Scan(&q.ID, &q.Text, &q.Options, &q.Active); err != nil {
+			return nil, err
+		}
+		questions = append(questions, q)
+	}
+	return questions, rows.Err()
+}
+
+// loadQuestionAnswers достает ответы анкеты совместимости сразу для нескольких пользователей
+func loadQuestionAnswers(ctx context.Context, pool *pgxpool.Pool, telegramIDs []int64) (map[int64][]entity.QuestionAnswer, error) {
+	result := make(map[int64][]entity.QuestionAnswer, len(telegramIDs))
+	if len(telegramIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT telegram_id, question_id, answer, acceptable, importance
+		FROM user_questions
+		WHERE telegram_id = ANY($1)
+		ORDER BY telegram_id, question_id`
+	rows, err := pool.Query(ctx, query, telegramIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			telegramID int64
+			answer     entity.QuestionAnswer
+			acceptable []int16
+			value      int16
+			importance int16
+		)
+		if err := rows.Scan(&telegramID, &answer.QuestionID, &value, &acceptable, &importance); err != nil {
+			return nil, err
+		}
+		answer.Answer = int(value)
+		answer.Importance = int(importance)
+		for _, a := range acceptable {
+			answer.Acceptable = append(answer.Acceptable, int(a))
+		}
+		result[telegramID] = append(result[telegramID], answer)
+	}
+	return result, rows.Err()
+}
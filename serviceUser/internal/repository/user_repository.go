@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"service1/internal/entity"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirupsen/logrus"
@@ -19,7 +20,7 @@ func NewUserRepository(pool *pgxpool.Pool, logger *logrus.Logger) *UserRepositor
 }
 
 func (r *UserRepository) CreateUser(ctx context.Context, user *entity.User) (int, error) {
-	query := `INSERT INTO users (name, age, description, photo, telegram_id, city, gender) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	query := `INSERT INTO users (name, age, description, photo, telegram_id, city, gender, interested_in, language_code) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
 	var id int
 
 	r.Logger.WithFields(logrus.Fields{
@@ -30,9 +31,11 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *entity.User) (int
 		"telegram_id": user.TelegramID,
 		"city":        user.City,
 		"gender":      user.Gender,
+		"interested":  user.InterestedIn,
+		"language":    user.LanguageCode,
 	}).Info("Executing CreateUser query")
 
-	err := r.Pool.QueryRow(ctx, query, user.Name, user.Age, user.Description, user.Photo, user.TelegramID, user.City, user.Gender).Scan(&id)
+	err := r.Pool.QueryRow(ctx, query, user.Name, user.Age, user.Description, user.Photo, user.TelegramID, user.City, user.Gender, user.InterestedIn, user.LanguageCode).Scan(&id)
 	if err != nil {
 		r.Logger.WithFields(logrus.Fields{
 			"user": user.Name,
@@ -47,7 +50,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *entity.User) (int
 }
 
 func (r *UserRepository) SearchUsers(ctx context.Context, filter entity.UserFilter) ([]entity.User, error) {
-	query := `SELECT id, telegram_id, name, age, city, gender, description, photo FROM users WHERE 1=1`
+	query := `SELECT id, telegram_id, name, age, city, gender, description, photo, last_active_at, interested_in, rating, latitude, longitude FROM users WHERE 1=1`
 	args := []interface{}{}
 	argIndex := 1
 
@@ -66,11 +69,60 @@ func (r *UserRepository) SearchUsers(ctx context.Context, filter entity.UserFilt
 		args = append(args, filter.City)
 		argIndex++
 	}
-	if filter.Gender != "" {
-		query += fmt.Sprintf(" AND gender = $%d", argIndex)
-		args = append(args, filter.Gender)
+	if len(filter.Genders) > 0 {
+		query += fmt.Sprintf(" AND gender = ANY($%d)", argIndex)
+		args = append(args, filter.Genders)
 		argIndex++
 	}
+	if filter.InterestedIn != "" {
+		query += fmt.Sprintf(" AND $%d = ANY(interested_in)", argIndex)
+		args = append(args, filter.InterestedIn)
+		argIndex++
+	}
+	if len(filter.TelegramIDs) > 0 {
+		query += fmt.Sprintf(" AND telegram_id = ANY($%d)", argIndex)
+		args = append(args, filter.TelegramIDs)
+		argIndex++
+	}
+	// В выдачу не попадают пользователи, которым бот не может писать;
+	// при загрузке конкретных анкет по ID они остаются
+	if len(filter.TelegramIDs) == 0 {
+		query += " AND unreachable_at IS NULL"
+	}
+	if filter.ExcludeFor > 0 {
+		query += fmt.Sprintf(" AND telegram_id <> $%d AND NOT EXISTS (SELECT 1 FROM hidden_users h WHERE h.telegram_id = $%d AND h.hidden_id = users.telegram_id)", argIndex, argIndex)
+		args = append(args, filter.ExcludeFor)
+		argIndex++
+	}
+	if filter.ActiveWithin > 0 {
+		query += fmt.Sprintf(" AND last_active_at >= $%d", argIndex)
+		args = append(args, time.Now().Add(-filter.ActiveWithin))
+		argIndex++
+	}
+	for _, cond := range filter.Attributes {
+		sub := fmt.Sprintf(" AND EXISTS (SELECT 1 FROM user_attributes ua WHERE ua.telegram_id = users.telegram_id AND ua.key = $%d", argIndex)
+		args = append(args, cond.Key)
+		argIndex++
+		if len(cond.Values) > 0 {
+			sub += fmt.Sprintf(" AND ua.value = ANY($%d)", argIndex)
+			args = append(args, cond.Values)
+			argIndex++
+		}
+		if cond.Min != nil {
+			sub += fmt.Sprintf(" AND ua.num_value >= $%d", argIndex)
+			args = append(args, *cond.Min)
+			argIndex++
+		}
+		if cond.Max != nil {
+			sub += fmt.Sprintf(" AND ua.num_value <= $%d", argIndex)
+			args = append(args, *cond.Max)
+			argIndex++
+		}
+		query += sub + ")"
+	}
+	if filter.SortBy == entity.SortByRecency {
+		query += " ORDER BY last_active_at DESC"
+	}
 
 	rows, err := r.Pool.Query(ctx, query, args...)
 	if err != nil {
@@ -80,30 +132,72 @@ func (r *UserRepository) SearchUsers(ctx context.Context, filter entity.UserFilt
 	defer rows.Close()
 	var users []entity.User
 	for rows.Next() {
-		var user entity.User
-		if err := rows.Scan(&user.ID, &user.TelegramID, &user.Name, &user.Age, &user.City, &user.Gender, &user.Description, &user.Photo); err != nil {
+		var (
+			user     entity.User
+			lat, lon *float64
+		)
+		if err := rows.Scan(&user.ID, &user.TelegramID, &user.Name, &user.Age, &user.City, &user.Gender, &user.Description, &user.Photo, &user.LastActiveAt, &user.InterestedIn, &user.Rating, &lat, &lon); err != nil {
 			return nil, err
 		}
+		user.Location = geoPoint(lat, lon)
 		users = append(users, user)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.TelegramID)
+	}
+	attributes, err := loadAttributes(ctx, r.Pool, ids)
+	if err != nil {
+		return nil, err
+	}
+	prompts, err := loadPrompts(ctx, r.Pool, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		users[i].Attributes = attributes[users[i].TelegramID]
+		users[i].Prompts = prompts[users[i].TelegramID]
+	}
 	return users, nil
 }
 
 func (r *UserRepository) GetUserByID(ctx context.Context, telegram_id int64) (*entity.User, error) {
-	query := `SELECT id, name, age, description, photo, telegram_id, city, gender FROM users WHERE telegram_id = $1`
+	query := `SELECT id, name, age, description, photo, telegram_id, city, gender, last_active_at, interested_in, rating, latitude, longitude, language_code, unreachable_at, unreachable_reason FROM users WHERE telegram_id = $1`
 	user := &entity.User{}
+	var lat, lon *float64
+	var unreachableReason *string
 
 	r.Logger.WithFields(logrus.Fields{
 		"user_telegram_ID": telegram_id,
 	}).Info("Executing GetUserByID query")
 
-	err := r.Pool.QueryRow(ctx, query, telegram_id).Scan(&user.ID, &user.Name, &user.Age, &user.Description, &user.Photo, &user.TelegramID, &user.City, &user.Gender)
+	err := r.Pool.QueryRow(ctx, query, telegram_id).Scan(&user.ID, &user.Name, &user.Age, &user.Description, &user.Photo, &user.TelegramID, &user.City, &user.Gender, &user.LastActiveAt, &user.InterestedIn, &user.Rating, &lat, &lon, &user.LanguageCode, &user.UnreachableAt, &unreachableReason)
 	if err != nil {
 		r.Logger.WithFields(logrus.Fields{
 			"user_telegram_ID": telegram_id,
 		}).Error("Error getting user by ID: ", err)
 		return nil, err
 	}
+	user.Location = geoPoint(lat, lon)
+	if unreachableReason != nil {
+		user.UnreachableReason = *unreachableReason
+	}
+
+	attributes, err := loadAttributes(ctx, r.Pool, []int64{user.TelegramID})
+	if err != nil {
+		return nil, err
+	}
+	user.Attributes = attributes[user.TelegramID]
+
+	prompts, err := loadPrompts(ctx, r.Pool, []int64{user.TelegramID})
+	if err != nil {
+		return nil, err
+	}
+	user.Prompts = prompts[user.TelegramID]
 
 	r.Logger.WithFields(logrus.Fields{
 		"user_telegram_ID": telegram_id,
@@ -112,7 +206,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, telegram_id int64) (*e
 }
 
 func (r *UserRepository) UpdateUser(ctx context.Context, id int, user *entity.User) error {
-	query := `UPDATE users SET name = $1, age = $2, description = $3, photo = $4, telegram_id = $5, city = $6, gender = $7 WHERE id = $8`
+	query := `UPDATE users SET name = $1, age = $2, description = $3, photo = $4, telegram_id = $5, city = $6, gender = $7, interested_in = $8 WHERE id = $9`
 
 	r.Logger.WithFields(logrus.Fields{
 		"userID":      id,
@@ -123,9 +217,10 @@ func (r *UserRepository) UpdateUser(ctx context.Context, id int, user *entity.Us
 		"telegram_id": user.TelegramID,
 		"city":        user.City,
 		"gender":      user.Gender,
+		"interested":  user.InterestedIn,
 	}).Info("Executing UpdateUser query")
 
-	_, err := r.Pool.Exec(ctx, query, user.Name, user.Age, user.Description, user.Photo, user.TelegramID, user.City, user.Gender, id)
+	_, err := r.Pool.Exec(ctx, query, user.Name, user.Age, user.Description, user.Photo, user.TelegramID, user.City, user.Gender, user.InterestedIn, id)
 	if err != nil {
 		r.Logger.WithFields(logrus.Fields{
 			"userID": id,
@@ -159,3 +254,207 @@ func (r *UserRepository) DeleteUser(ctx context.Context, id int64) error {
 	}).Info("User deleted successfully")
 	return nil
 }
+
+// TouchUsers пачкой обновляет last_active_at. Время сдвигается только вперед,
+// поэтому повторный сброс старых данных ничего не ломает.
+func (r *UserRepository) TouchUsers(ctx context.Context, activity map[int64]time.Time) error {
+	if len(activity) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(activity))
+	times := make([]time.Time, 0, len(activity))
+	for id, ts := range activity {
+		ids = append(ids, id)
+		times = append(times, ts)
+	}
+
+	query := `
+		UPDATE users u
+		SET last_active_at = v.ts
+		FROM unnest($1::bigint[], $2::timestamptz[]) AS v(telegram_id, ts)
+		WHERE u.telegram_id = v.telegram_id AND v.ts > u.last_active_at`
+
+	r.Logger.WithFields(logrus.Fields{
+		"users": len(ids),
+	}).Info("Executing TouchUsers query")
+
+	_, err := r.Pool.Exec(ctx, query, ids, times)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"users": len(ids),
+		}).Error("Error updating last activity: ", err)
+		return err
+	}
+	return nil
+}
+
+// HideUser - навсегда убирает hiddenID из выдачи пользователя telegramID
+func (r *UserRepository) HideUser(ctx context.Context, telegramID, hiddenID int64) error {
+	query := `INSERT INTO hidden_users (telegram_id, hidden_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	_, err := r.Pool.Exec(ctx, query, telegramID, hiddenID)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID": telegramID,
+			"hidden_ID":        hiddenID,
+		}).Error("Error hiding user: ", err)
+	}
+	return err
+}
+
+// UpdateRating - Elo-обновление рейтинга анкеты targetID после свайпа raterID.
+// Лайк - «победа» анкеты, дизлайк - «поражение»; противник - рейтинг того, кто свайпал.
+// Считается одним запросом, чтобы параллельные свайпы не затирали друг друга
+func (r *UserRepository) UpdateRating(ctx context.Context, targetID, raterID int64, score, k float64) error {
+	query := `
+		UPDATE users
+		SET rating = rating + $4 * ($3 - 1 / (1 + power(10,
+				(COALESCE((SELECT rater.rating FROM users rater WHERE rater.telegram_id = $2), 1500) - rating) / 400.0))),
+			rating_count = rating_count + 1
+		WHERE telegram_id = $1`
+	_, err := r.Pool.Exec(ctx, query, targetID, raterID, score, k)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID":  targetID,
+			"rater_telegram_ID": raterID,
+		}).Error("Error updating rating: ", err)
+	}
+	return err
+}
+
+// RevertRating - обратное Elo-обновление после отмены свайпа. Ожидаемый исход
+// считается по текущим рейтингам: за окно отмены они меняются мало, поэтому
+// откат совпадает с исходным изменением с точностью до долей пункта
+func (r *UserRepository) RevertRating(ctx context.Context, targetID, raterID int64, score, k float64) error {
+	query := `
+		UPDATE users
+		SET rating = rating - $4 * ($3 - 1 / (1 + power(10,
+				(COALESCE((SELECT rater.rating FROM users rater WHERE rater.telegram_id = $2), 1500) - rating) / 400.0))),
+			rating_count = GREATEST(rating_count - 1, 0)
+		WHERE telegram_id = $1`
+	_, err := r.Pool.Exec(ctx, query, targetID, raterID, score, k)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID":  targetID,
+			"rater_telegram_ID": raterID,
+		}).Error("Error reverting rating: ", err)
+	}
+	return err
+}
+
+// SetLocation - сохраняет координаты пользователя
+func (r *UserRepository) SetLocation(ctx context.Context, telegramID int64, point entity.GeoPoint) error {
+	_, err := r.Pool.Exec(ctx, `UPDATE users SET latitude = $2, longitude = $3 WHERE telegram_id = $1`,
+		telegramID, point.Latitude, point.Longitude)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID": telegramID,
+		}).Error("Error setting location: ", err)
+	}
+	return err
+}
+
+// SetLanguage - сохраняет язык интерфейса пользователя. Анкеты нет - ErrNotFound
+func (r *UserRepository) SetLanguage(ctx context.Context, telegramID int64, languageCode string) error {
+	tag, err := r.Pool.Exec(ctx, `UPDATE users SET language_code = $2 WHERE telegram_id = $1`, telegramID, languageCode)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID": telegramID,
+		}).Error("Error setting language: ", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MarkUnreachable - бот не может писать пользователю, анкета уходит из выдачи.
+// Время первой ошибки не перезаписывается повторными событиями
+func (r *UserRepository) MarkUnreachable(ctx context.Context, telegramID int64, reason string, at time.Time) error {
+	_, err := r.Pool.Exec(ctx, `
+		UPDATE users SET unreachable_at = COALESCE(unreachable_at, $3), unreachable_reason = $2
+		WHERE telegram_id = $1`, telegramID, reason, at)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID": telegramID,
+		}).Error("Error marking user unreachable: ", err)
+	}
+	return err
+}
+
+// Reactivate - пользователь снова запустил бота; false - анкета и так была активна
+func (r *UserRepository) Reactivate(ctx context.Context, telegramID int64) (bool, error) {
+	tag, err := r.Pool.Exec(ctx, `
+		UPDATE users SET unreachable_at = NULL, unreachable_reason = NULL
+		WHERE telegram_id = $1 AND unreachable_at IS NOT NULL`, telegramID)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"user_telegram_ID": telegramID,
+		}).Error("Error reactivating user: ", err)
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// InactiveWithNewProfiles - пользователи, не заходившие с inactiveSince, у
+// которых в городе с тех пор появились подходящие им по полу анкеты
+func (r *UserRepository) InactiveWithNewProfiles(ctx context.Context, inactiveSince time.Time, limit, offset int) ([]entity.InactiveUser, error) {
+	query := `
+		SELECT u.telegram_id, u.city, u.last_active_at, n.count
+		FROM users u
+		CROSS JOIN LATERAL (
+			SELECT count(*) AS count
+			FROM users o
+			WHERE o.city = u.city
+				AND o.created_at > u.last_active_at
+				AND o.telegram_id <> u.telegram_id
+				AND o.unreachable_at IS NULL
+				AND (cardinality(u.interested_in) = 0 OR o.gender = ANY(u.interested_in))
+				AND NOT EXISTS (SELECT 1 FROM hidden_users h WHERE h.telegram_id = u.telegram_id AND h.hidden_id = o.telegram_id)
+		) n
+		WHERE u.last_active_at < $1 AND u.unreachable_at IS NULL AND n.count > 0
+		ORDER BY u.telegram_id
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.Pool.Query(ctx, query, inactiveSince, limit, offset)
+	if err != nil {
+		r.Logger.Error("Error loading inactive users: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []entity.InactiveUser{}
+	for rows.Next() {
+		var user entity.InactiveUser
+		if err := rows.Scan(&user.TelegramID, &user.City, &user.LastActiveAt, &user.NewProfiles); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// LogFeed - записывает выданную ленту в журнал для офлайн-анализа
+func (r *UserRepository) LogFeed(ctx context.Context, entry entity.FeedLog) error {
+	query := `INSERT INTO feed_log (viewer_id, experiment, variant, candidate_ids, scores) VALUES ($1, $2, $3, $4, $5)`
+	_, err := r.Pool.Exec(ctx, query, entry.ViewerID, entry.Experiment, entry.Variant, entry.CandidateIDs, entry.Scores)
+	if err != nil {
+		r.Logger.WithFields(logrus.Fields{
+			"viewer_telegram_ID": entry.ViewerID,
+		}).Error("Error logging feed: ", err)
+	}
+	return err
+}
+
+func geoPoint(lat, lon *float64) *entity.GeoPoint {
+	if lat == nil || lon == nil {
+		return nil
+	}
+	return &entity.GeoPoint{Latitude: *lat, Longitude: *lon}
+}
+
+// QuestionAnswers - ответы анкеты совместимости для расчета процента в выдаче
+func (r *UserRepository) QuestionAnswers(ctx context.Context, telegramIDs []int64) (map[int64][]entity.QuestionAnswer, error) {
+	return loadQuestionAnswers(ctx, r.Pool, telegramIDs)
+}
@@ -13,6 +13,13 @@ type RedisStorage interface {
 	Get(ctx context.Context, key string) *redis.StringCmd
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
 	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	HSet(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+	HGet(ctx context.Context, key, field string) *redis.StringCmd
+	HGetAll(ctx context.Context, key string) *redis.MapStringStringCmd
+	HSetNX(ctx context.Context, key, field string, value interface{}) *redis.BoolCmd
+	Incr(ctx context.Context, key string) *redis.IntCmd
+	Exists(ctx context.Context, keys ...string) *redis.IntCmd
+	Rename(ctx context.Context, key, newkey string) *redis.StatusCmd
 }
 
 type redisStorage struct {
@@ -43,3 +50,31 @@ func (r *redisStorage) Set(ctx context.Context, key string, value interface{}, e
 func (r *redisStorage) Del(ctx context.Context, keys ...string) *redis.IntCmd {
 	return r.Client.Del(ctx, keys...)
 }
+
+func (r *redisStorage) HSet(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	return r.Client.HSet(ctx, key, values...)
+}
+
+func (r *redisStorage) HGet(ctx context.Context, key, field string) *redis.StringCmd {
+	return r.Client.HGet(ctx, key, field)
+}
+
+func (r *redisStorage) HGetAll(ctx context.Context, key string) *redis.MapStringStringCmd {
+	return r.Client.HGetAll(ctx, key)
+}
+
+func (r *redisStorage) HSetNX(ctx context.Context, key, field string, value interface{}) *redis.BoolCmd {
+	return r.Client.HSetNX(ctx, key, field, value)
+}
+
+func (r *redisStorage) Incr(ctx context.Context, key string) *redis.IntCmd {
+	return r.Client.Incr(ctx, key)
+}
+
+func (r *redisStorage) Exists(ctx context.Context, keys ...string) *redis.IntCmd {
+	return r.Client.Exists(ctx, keys...)
+}
+
+func (r *redisStorage) Rename(ctx context.Context, key, newkey string) *redis.StatusCmd {
+	return r.Client.Rename(ctx, key, newkey)
+}
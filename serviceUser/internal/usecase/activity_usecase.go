@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// activityPendingKey - хэш telegram_id -> unix-время последней активности,
+	// который еще не сброшен в Postgres
+	activityPendingKey = "activity:pending"
+	// activityFlushingKey - префикс снимка pending-хэша на время сброса.
+	// У каждого сброса свой снимок, поэтому два экземпляра сервиса не затирают
+	// данные друг друга
+	activityFlushingKey = "activity:flushing"
+	// activityFlushSeqKey - счетчик для уникальных имен снимков
+	activityFlushSeqKey = "activity:flush:seq"
+)
+
+// TouchActivity отмечает, что пользователь был активен. Запись идет только в
+// Redis, в Postgres данные попадают пачкой через FlushActivity.
+func (u *UserUsecase) TouchActivity(ctx context.Context, telegramID int64) error {
+	if telegramID <= 0 {
+		return errors.New("invalid id")
+	}
+	return u.redisStorage.HSet(ctx, activityPendingKey, strconv.FormatInt(telegramID, 10), time.Now().Unix()).Err()
+}
+
+// FlushActivity переносит накопленную активность из Redis в Postgres.
+// Хэш сначала атомарно переименовывается в снимок с уникальным именем, чтобы
+// новые отметки не потерялись во время сброса. Если записать снимок не удалось,
+// он возвращается в pending-хэш и попадет в следующий сброс.
+func (u *UserUsecase) FlushActivity(ctx context.Context) error {
+	// Пустой хэш Redis удаляет, поэтому отсутствие ключа значит, что сбрасывать нечего
+	exists, err := u.redisStorage.Exists(ctx, activityPendingKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot activity: %w", err)
+	}
+	if exists == 0 {
+		return nil
+	}
+
+	seq, err := u.redisStorage.Incr(ctx, activityFlushSeqKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot activity: %w", err)
+	}
+	snapshotKey := fmt.Sprintf("%s:%d", activityFlushingKey, seq)
+
+	if err := u.redisStorage.Rename(ctx, activityPendingKey, snapshotKey).Err(); err != nil {
+		return fmt.Errorf("failed to snapshot activity: %w", err)
+	}
+
+	pending, err := u.redisStorage.HGetAll(ctx, snapshotKey).Result()
+	if err != nil {
+		// Снимок целиком остается в Redis: без его содержимого вернуть его нечем
+		return fmt.Errorf("failed to read activity: %w", err)
+	}
+
+	activity := make(map[int64]time.Time, len(pending))
+	for field, value := range pending {
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		ts, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		activity[id] = time.Unix(ts, 0)
+	}
+
+	if err := u.repo.TouchUsers(ctx, activity); err != nil {
+		u.restoreActivity(ctx, snapshotKey, pending)
+		return fmt.Errorf("failed to flush activity: %w", err)
+	}
+
+	keys := make([]string, 0, len(activity))
+	for id := range activity {
+		keys = append(keys, fmt.Sprintf("user:%d", id))
+	}
+	keys = append(keys, snapshotKey)
+	_ = u.redisStorage.Del(ctx, keys...)
+
+	return nil
+}
+
+// restoreActivity возвращает несброшенный снимок в pending-хэш. Отметки,
+// появившиеся во время сброса, новее снимка, поэтому они не перезаписываются.
+// Снимок удаляется, только если вернуть удалось все поля.
+func (u *UserUsecase) restoreActivity(ctx context.Context, snapshotKey string, pending map[string]string) {
+	for field, value := range pending {
+		if err := u.redisStorage.HSetNX(ctx, activityPendingKey, field, value).Err(); err != nil {
+			logrus.Warn("Не удалось вернуть активность в Redis, снимок ", snapshotKey, " сохранен: ", err)
+			return
+		}
+	}
+	_ = u.redisStorage.Del(ctx, snapshotKey)
+}
+
+// RunActivityFlusher периодически вызывает FlushActivity до отмены контекста.
+func (u *UserUsecase) RunActivityFlusher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := u.FlushActivity(ctx); err != nil {
+				logrus.Warn("Не удалось сбросить активность пользователей: ", err)
+			}
+		}
+	}
+}
+
+// pendingActivity возвращает еще не сброшенное время активности пользователя.
+func (u *UserUsecase) pendingActivity(ctx context.Context, telegramID int64) (time.Time, bool) {
+	value, err := u.redisStorage.HGet(ctx, activityPendingKey, strconv.FormatInt(telegramID, 10)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			logrus.Warn("Не удалось прочитать активность из Redis: ", err)
+		}
+		return time.Time{}, false
+	}
+	ts, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(ts, 0), true
+}
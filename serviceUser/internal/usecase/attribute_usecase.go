@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"service1/internal/entity"
+	"service1/internal/storage"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type AttributeRepository interface {
+	ListDefinitions(ctx context.Context) ([]entity.AttributeDefinition, error)
+	CreateDefinition(ctx context.Context, def *entity.AttributeDefinition) error
+	UpdateDefinition(ctx context.Context, def *entity.AttributeDefinition) error
+	DeleteDefinition(ctx context.Context, key string) error
+	SetUserAttribute(ctx context.Context, telegramID int64, key string, values []string, num *int) error
+	DeleteUserAttribute(ctx context.Context, telegramID int64, key string) error
+}
+
+var (
+	// ErrInvalidAttribute - атрибут или его значение не проходит проверку по реестру
+	ErrInvalidAttribute = errors.New("invalid attribute")
+	// ErrAttributeNotFound - атрибута нет в реестре
+	ErrAttributeNotFound = errors.New("attribute not found")
+)
+
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,63}$`)
+
+// reservedSearchParams - параметры /users/search, которые не могут быть ключами атрибутов
+var reservedSearchParams = map[string]bool{
+	"min_age": true, "max_age": true, "city": true, "gender": true,
+	"interested_in": true, "active_within": true, "sort": true, "exclude_for": true,
+}
+
+// AttributeUsecase - реестр расширенных атрибутов анкеты и их значения у пользователей
+type AttributeUsecase struct {
+	repo         AttributeRepository
+	redisStorage storage.RedisStorage
+}
+
+func NewAttributeUsecase(repo AttributeRepository, redisStorage storage.RedisStorage) *AttributeUsecase {
+	if repo == nil {
+		panic("AttributeRepository cannot be nil")
+	}
+	if redisStorage == nil {
+		panic("RedisStorage cannot be nil")
+	}
+	return &AttributeUsecase{repo: repo, redisStorage: redisStorage}
+}
+
+func (u *AttributeUsecase) Definitions(ctx context.Context) ([]entity.AttributeDefinition, error) {
+	return u.repo.ListDefinitions(ctx)
+}
+
+func (u *AttributeUsecase) CreateDefinition(ctx context.Context, def *entity.AttributeDefinition) error {
+	if !attributeKeyPattern.MatchString(def.Key) || reservedSearchParams[def.Key] ||
+		strings.HasSuffix(def.Key, "_min") || strings.HasSuffix(def.Key, "_max") {
+		return fmt.Errorf("%w: bad key %q", ErrInvalidAttribute, def.Key)
+	}
+	if err := validateDefinition(def); err != nil {
+		return err
+	}
+	return u.repo.CreateDefinition(ctx, def)
+}
+
+func (u *AttributeUsecase) UpdateDefinition(ctx context.Context, def *entity.AttributeDefinition) error {
+	current, err := u.definition(ctx, def.Key)
+	if err != nil {
+		return err
+	}
+	// Тип менять нельзя: сохраненные значения перестанут ему соответствовать
+	def.Type = current.Type
+	if err := validateDefinition(def); err != nil {
+		return err
+	}
+	return u.repo.UpdateDefinition(ctx, def)
+}
+
+func (u *AttributeUsecase) DeleteDefinition(ctx context.Context, key string) error {
+	if _, err := u.definition(ctx, key); err != nil {
+		return err
+	}
+	return u.repo.DeleteDefinition(ctx, key)
+}
+
+// SetUserValues проверяет значения по реестру и сохраняет их пользователю
+func (u *AttributeUsecase) SetUserValues(ctx context.Context, telegramID int64, key string, values []string) error {
+	if telegramID <= 0 {
+		return errors.New("invalid id")
+	}
+	def, err := u.definition(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	var num *int
+	switch def.Type {
+	case entity.AttributeInt:
+		if len(values) != 1 {
+			return fmt.Errorf("%w: %s expects a single number", ErrInvalidAttribute, key)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(values[0]))
+		if err != nil {
+			return fmt.Errorf("%w: %s expects a number", ErrInvalidAttribute, key)
+		}
+		if (def.Min != nil && n < *def.Min) || (def.Max != nil && n > *def.Max) {
+			return fmt.Errorf("%w: %s is out of range", ErrInvalidAttribute, key)
+		}
+		values = []string{strconv.Itoa(n)}
+		num = &n
+	case entity.AttributeString:
+		if len(values) != 1 || strings.TrimSpace(values[0]) == "" || len([]rune(values[0])) > 255 {
+			return fmt.Errorf("%w: %s expects a non-empty string up to 255 characters", ErrInvalidAttribute, key)
+		}
+	case entity.AttributeEnum, entity.AttributeMultiEnum:
+		if len(values) == 0 || (def.Type == entity.AttributeEnum && len(values) != 1) {
+			return fmt.Errorf("%w: wrong number of values for %s", ErrInvalidAttribute, key)
+		}
+		seen := make(map[string]bool, len(values))
+		for _, v := range values {
+			if !contains(def.AllowedValues, v) || seen[v] {
+				return fmt.Errorf("%w: %q is not allowed for %s", ErrInvalidAttribute, v, key)
+			}
+			seen[v] = true
+		}
+	}
+
+	if err := u.repo.SetUserAttribute(ctx, telegramID, key, values, num); err != nil {
+		return err
+	}
+	_ = u.redisStorage.Del(ctx, fmt.Sprintf("user:%d", telegramID))
+	return nil
+}
+
+func (u *AttributeUsecase) DeleteUserValues(ctx context.Context, telegramID int64, key string) error {
+	if telegramID <= 0 {
+		return errors.New("invalid id")
+	}
+	if err := u.repo.DeleteUserAttribute(ctx, telegramID, key); err != nil {
+		return err
+	}
+	_ = u.redisStorage.Del(ctx, fmt.Sprintf("user:%d", telegramID))
+	return nil
+}
+
+// ParseConditions превращает параметры запроса поиска в условия по атрибутам.
+// Поддерживается "goal=relationship", "languages=ru,en" и для чисел
+// "height=180", "height_min=170", "height_max=190". Точное значение числа
+// вместе с границами - ошибка: параметры приходят без порядка, и итог зависел бы от него.
+func (u *AttributeUsecase) ParseConditions(ctx context.Context, params map[string][]string) ([]entity.AttributeCondition, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+	defs, err := u.repo.ListDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registry := make(map[string]entity.AttributeDefinition, len(defs))
+	for _, def := range defs {
+		registry[def.Key] = def
+	}
+
+	byKey := make(map[string]*entity.AttributeCondition)
+	exact, ranged := make(map[string]bool), make(map[string]bool)
+	var order []string
+	for param, raw := range params {
+		if len(raw) == 0 || raw[0] == "" {
+			continue
+		}
+		key, bound := param, ""
+		if _, ok := registry[key]; !ok {
+			switch {
+			case strings.HasSuffix(param, "_min"):
+				key, bound = strings.TrimSuffix(param, "_min"), "min"
+			case strings.HasSuffix(param, "_max"):
+				key, bound = strings.TrimSuffix(param, "_max"), "max"
+			}
+		}
+		def, ok := registry[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown filter %q", ErrInvalidAttribute, param)
+		}
+		if !def.Searchable {
+			return nil, fmt.Errorf("%w: %q is not searchable", ErrInvalidAttribute, key)
+		}
+
+		cond, ok := byKey[key]
+		if !ok {
+			cond = &entity.AttributeCondition{Key: key}
+			byKey[key] = cond
+			order = append(order, key)
+		}
+
+		if def.Type == entity.AttributeInt {
+			n, err := strconv.Atoi(raw[0])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q expects a number", ErrInvalidAttribute, param)
+			}
+			if bound == "" {
+				exact[key] = true
+			} else {
+				ranged[key] = true
+			}
+			if exact[key] && ranged[key] {
+				return nil, fmt.Errorf("%w: %q cannot combine an exact value with _min/_max", ErrInvalidAttribute, key)
+			}
+			if bound != "max" {
+				cond.Min = &n
+			}
+			if bound != "min" {
+				cond.Max = &n
+			}
+			continue
+		}
+		if bound != "" {
+			return nil, fmt.Errorf("%w: %q supports only exact values", ErrInvalidAttribute, key)
+		}
+		for _, value := range strings.Split(raw[0], ",") {
+			if def.Type != entity.AttributeString && !contains(def.AllowedValues, value) {
+				return nil, fmt.Errorf("%w: %q is not allowed for %s", ErrInvalidAttribute, value, key)
+			}
+			cond.Values = append(cond.Values, value)
+		}
+	}
+
+	// Порядок фиксированный, чтобы одинаковые запросы попадали в один ключ кэша
+	sort.Strings(order)
+	conditions := make([]entity.AttributeCondition, 0, len(order))
+	for _, key := range order {
+		conditions = append(conditions, *byKey[key])
+	}
+	return conditions, nil
+}
+
+// IsReservedSearchParam сообщает, что параметр поиска не относится к атрибутам
+func IsReservedSearchParam(param string) bool {
+	return reservedSearchParams[param]
+}
+
+func (u *AttributeUsecase) definition(ctx context.Context, key string) (*entity.AttributeDefinition, error) {
+	defs, err := u.repo.ListDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range defs {
+		if defs[i].Key == key {
+			return &defs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrAttributeNotFound, key)
+}
+
+func validateDefinition(def *entity.AttributeDefinition) error {
+	if strings.TrimSpace(def.Label) == "" {
+		return fmt.Errorf("%w: label is required", ErrInvalidAttribute)
+	}
+	switch def.Type {
+	case entity.AttributeInt:
+		if len(def.AllowedValues) > 0 {
+			return fmt.Errorf("%w: int attribute cannot have allowed values", ErrInvalidAttribute)
+		}
+		if def.Min != nil && def.Max != nil && *def.Min > *def.Max {
+			return fmt.Errorf("%w: min is greater than max", ErrInvalidAttribute)
+		}
+	case entity.AttributeString:
+		if len(def.AllowedValues) > 0 || def.Min != nil || def.Max != nil {
+			return fmt.Errorf("%w: string attribute takes no constraints", ErrInvalidAttribute)
+		}
+	case entity.AttributeEnum, entity.AttributeMultiEnum:
+		if len(def.AllowedValues) == 0 {
+			return fmt.Errorf("%w: allowed values are required", ErrInvalidAttribute)
+		}
+		if def.Min != nil || def.Max != nil {
+			return fmt.Errorf("%w: enum attribute takes no range", ErrInvalidAttribute)
+		}
+		for _, v := range def.AllowedValues {
+			if v == "" || strings.Contains(v, ",") {
+				return fmt.Errorf("%w: bad allowed value %q", ErrInvalidAttribute, v)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidAttribute, def.Type)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
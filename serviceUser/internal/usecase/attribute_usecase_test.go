@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"service1/internal/entity"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAttributeRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttributeRepository) ListDefinitions(ctx context.Context) ([]entity.AttributeDefinition, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeRepository) CreateDefinition(ctx context.Context, def *entity.AttributeDefinition) error {
+	args := m.Called(ctx, def)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepository) UpdateDefinition(ctx context.Context, def *entity.AttributeDefinition) error {
+	args := m.Called(ctx, def)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepository) DeleteDefinition(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepository) SetUserAttribute(ctx context.Context, telegramID int64, key string, values []string, num *int) error {
+	args := m.Called(ctx, telegramID, key, values, num)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepository) DeleteUserAttribute(ctx context.Context, telegramID int64, key string) error {
+	args := m.Called(ctx, telegramID, key)
+	return args.Error(0)
+}
+
+func testDefinitions() []entity.AttributeDefinition {
+	minHeight, maxHeight := 100, 250
+	return []entity.AttributeDefinition{
+		{Key: "height", Label: "Рост", Type: entity.AttributeInt, Min: &minHeight, Max: &maxHeight, Searchable: true},
+		{Key: "goal", Label: "Цель", Type: entity.AttributeEnum, AllowedValues: []string{"relationship", "friendship"}, Searchable: true},
+		{Key: "languages", Label: "Языки", Type: entity.AttributeMultiEnum, AllowedValues: []string{"ru", "en"}, Searchable: true},
+		{Key: "pet", Label: "Питомец", Type: entity.AttributeString},
+	}
+}
+
+func TestAttributeUsecase_ParseConditions(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockAttributeRepository)
+	repo.On("ListDefinitions", ctx).Return(testDefinitions(), nil)
+	usecase := NewAttributeUsecase(repo, new(MockRedisStorage))
+
+	t.Run("Success", func(t *testing.T) {
+		conditions, err := usecase.ParseConditions(ctx, map[string][]string{
+			"goal":       {"relationship"},
+			"height_min": {"170"},
+			"height_max": {"190"},
+			"languages":  {"ru,en"},
+		})
+
+		minHeight, maxHeight := 170, 190
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.AttributeCondition{
+			{Key: "goal", Values: []string{"relationship"}},
+			{Key: "height", Min: &minHeight, Max: &maxHeight},
+			{Key: "languages", Values: []string{"ru", "en"}},
+		}, conditions)
+	})
+
+	t.Run("Unknown filter", func(t *testing.T) {
+		_, err := usecase.ParseConditions(ctx, map[string][]string{"zodiac": {"leo"}})
+		assert.ErrorIs(t, err, ErrInvalidAttribute)
+	})
+
+	t.Run("Not searchable", func(t *testing.T) {
+		_, err := usecase.ParseConditions(ctx, map[string][]string{"pet": {"cat"}})
+		assert.ErrorIs(t, err, ErrInvalidAttribute)
+	})
+
+	t.Run("Value not allowed", func(t *testing.T) {
+		_, err := usecase.ParseConditions(ctx, map[string][]string{"goal": {"marriage"}})
+		assert.ErrorIs(t, err, ErrInvalidAttribute)
+	})
+
+	t.Run("Exact value mixed with range", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			_, err := usecase.ParseConditions(ctx, map[string][]string{
+				"height":     {"180"},
+				"height_min": {"170"},
+				"height_max": {"190"},
+			})
+			assert.ErrorIs(t, err, ErrInvalidAttribute)
+		}
+	})
+
+	t.Run("Exact value", func(t *testing.T) {
+		conditions, err := usecase.ParseConditions(ctx, map[string][]string{"height": {"180"}})
+
+		height := 180
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.AttributeCondition{{Key: "height", Min: &height, Max: &height}}, conditions)
+	})
+}
+
+func TestAttributeUsecase_SetUserValues(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success int", func(t *testing.T) {
+		repo := new(MockAttributeRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewAttributeUsecase(repo, redisStorage)
+
+		height := 180
+		repo.On("ListDefinitions", ctx).Return(testDefinitions(), nil)
+		repo.On("SetUserAttribute", ctx, int64(7), "height", []string{"180"}, &height).Return(nil)
+		redisStorage.On("Del", ctx, []string{"user:7"}).Return(redis.NewIntResult(1, nil))
+
+		err := usecase.SetUserValues(ctx, 7, "height", []string{" 180 "})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		redisStorage.AssertExpectations(t)
+	})
+
+	t.Run("Out of range", func(t *testing.T) {
+		repo := new(MockAttributeRepository)
+		usecase := NewAttributeUsecase(repo, new(MockRedisStorage))
+		repo.On("ListDefinitions", ctx).Return(testDefinitions(), nil)
+
+		err := usecase.SetUserValues(ctx, 7, "height", []string{"300"})
+
+		assert.ErrorIs(t, err, ErrInvalidAttribute)
+		repo.AssertNotCalled(t, "SetUserAttribute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Enum takes one value", func(t *testing.T) {
+		repo := new(MockAttributeRepository)
+		usecase := NewAttributeUsecase(repo, new(MockRedisStorage))
+		repo.On("ListDefinitions", ctx).Return(testDefinitions(), nil)
+
+		err := usecase.SetUserValues(ctx, 7, "goal", []string{"relationship", "friendship"})
+
+		assert.ErrorIs(t, err, ErrInvalidAttribute)
+	})
+
+	t.Run("Unknown attribute", func(t *testing.T) {
+		repo := new(MockAttributeRepository)
+		usecase := NewAttributeUsecase(repo, new(MockRedisStorage))
+		repo.On("ListDefinitions", ctx).Return(testDefinitions(), nil)
+
+		err := usecase.SetUserValues(ctx, 7, "zodiac", []string{"leo"})
+
+		assert.ErrorIs(t, err, ErrAttributeNotFound)
+	})
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service1/internal/entity"
+	"service1/internal/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+type NotificationRepository interface {
+	NotificationSettings(ctx context.Context, telegramID int64) (entity.NotificationSettings, error)
+	SaveNotificationSettings(ctx context.Context, telegramID int64, s entity.NotificationSettings) error
+}
+
+var (
+	// ErrInvalidSettings - неизвестный тип или режим, неверный час или часовой пояс
+	ErrInvalidSettings = errors.New("invalid notification settings")
+	// ErrUserNotFound - анкеты с таким Telegram ID нет
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// NotificationUsecase - настройки уведомлений пользователей
+type NotificationUsecase struct {
+	repo NotificationRepository
+}
+
+func NewNotificationUsecase(repo NotificationRepository) *NotificationUsecase {
+	if repo == nil {
+		panic("NotificationRepository cannot be nil")
+	}
+	return &NotificationUsecase{repo: repo}
+}
+
+func (u *NotificationUsecase) Settings(ctx context.Context, telegramID int64) (entity.NotificationSettings, error) {
+	if telegramID <= 0 {
+		return entity.NotificationSettings{}, errors.New("invalid id")
+	}
+	return u.repo.NotificationSettings(ctx, telegramID)
+}
+
+// SaveSettings проверяет и сохраняет настройки целиком. Пустые часовой пояс и
+// режим заменяются значениями по умолчанию
+func (u *NotificationUsecase) SaveSettings(ctx context.Context, telegramID int64, s entity.NotificationSettings) (entity.NotificationSettings, error) {
+	if telegramID <= 0 {
+		return s, errors.New("invalid id")
+	}
+	s, err := normalizeSettings(s)
+	if err != nil {
+		return s, err
+	}
+	err = u.repo.SaveNotificationSettings(ctx, telegramID, s)
+	if errors.Is(err, repository.ErrNotFound) {
+		return s, fmt.Errorf("%w: %d", ErrUserNotFound, telegramID)
+	}
+	return s, err
+}
+
+func normalizeSettings(s entity.NotificationSettings) (entity.NotificationSettings, error) {
+	disabled := []string{}
+	for _, t := range s.Disabled {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !slices.Contains(entity.NotificationTypes, t) {
+			return s, fmt.Errorf("%w: unknown notification type %q", ErrInvalidSettings, t)
+		}
+		if !slices.Contains(disabled, t) {
+			disabled = append(disabled, t)
+		}
+	}
+	s.Disabled = disabled
+
+	if (s.QuietFrom == nil) != (s.QuietTo == nil) {
+		return s, fmt.Errorf("%w: quiet_from and quiet_to must be set together", ErrInvalidSettings)
+	}
+	if s.QuietFrom != nil {
+		if !validHour(*s.QuietFrom) || !validHour(*s.QuietTo) || *s.QuietFrom == *s.QuietTo {
+			return s, fmt.Errorf("%w: quiet hours must be two different hours 0-23", ErrInvalidSettings)
+		}
+	}
+
+	if s.TimeZone == "" {
+		s.TimeZone = entity.DefaultTimeZone
+	}
+	if _, err := time.LoadLocation(s.TimeZone); err != nil || s.TimeZone == "Local" {
+		return s, fmt.Errorf("%w: unknown time zone %q", ErrInvalidSettings, s.TimeZone)
+	}
+
+	switch s.Mode {
+	case "":
+		s.Mode = entity.NotifyInstant
+	case entity.NotifyInstant, entity.NotifyDigest:
+	default:
+		return s, fmt.Errorf("%w: unknown mode %q", ErrInvalidSettings, s.Mode)
+	}
+	return s, nil
+}
+
+func validHour(h int) bool {
+	return h >= 0 && h <= 23
+}
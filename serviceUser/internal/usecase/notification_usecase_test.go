@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"service1/internal/entity"
+	"service1/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) NotificationSettings(ctx context.Context, telegramID int64) (entity.NotificationSettings, error) {
+	args := m.Called(ctx, telegramID)
+	return args.Get(0).(entity.NotificationSettings), args.Error(1)
+}
+
+func (m *MockNotificationRepository) SaveNotificationSettings(ctx context.Context, telegramID int64, s entity.NotificationSettings) error {
+	args := m.Called(ctx, telegramID, s)
+	return args.Error(0)
+}
+
+func hour(h int) *int {
+	return &h
+}
+
+func TestNotificationUsecase_SaveSettings(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockNotificationRepository)
+		usecase := NewNotificationUsecase(repo)
+
+		want := entity.NotificationSettings{
+			Disabled:  []string{"superlike"},
+			QuietFrom: hour(23),
+			QuietTo:   hour(8),
+			TimeZone:  "Asia/Novosibirsk",
+			Mode:      entity.NotifyDigest,
+		}
+		repo.On("SaveNotificationSettings", ctx, int64(7), want).Return(nil)
+
+		saved, err := usecase.SaveSettings(ctx, 7, entity.NotificationSettings{
+			Disabled:  []string{" SuperLike", "superlike"},
+			QuietFrom: hour(23),
+			QuietTo:   hour(8),
+			TimeZone:  "Asia/Novosibirsk",
+			Mode:      entity.NotifyDigest,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, saved)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		repo := new(MockNotificationRepository)
+		usecase := NewNotificationUsecase(repo)
+
+		repo.On("SaveNotificationSettings", ctx, int64(7), entity.DefaultNotificationSettings()).Return(nil)
+
+		saved, err := usecase.SaveSettings(ctx, 7, entity.NotificationSettings{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.DefaultNotificationSettings(), saved)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		invalid := map[string]entity.NotificationSettings{
+			"unknown type":    {Disabled: []string{"profile_rejected"}},
+			"only quiet from": {QuietFrom: hour(23)},
+			"hour range":      {QuietFrom: hour(23), QuietTo: hour(24)},
+			"empty window":    {QuietFrom: hour(8), QuietTo: hour(8)},
+			"time zone":       {TimeZone: "Mars/Olympus"},
+			"local time zone": {TimeZone: "Local"},
+			"mode":            {Mode: "weekly"},
+		}
+		for name, s := range invalid {
+			t.Run(name, func(t *testing.T) {
+				repo := new(MockNotificationRepository)
+				usecase := NewNotificationUsecase(repo)
+
+				_, err := usecase.SaveSettings(ctx, 7, s)
+
+				assert.ErrorIs(t, err, ErrInvalidSettings)
+				repo.AssertNotCalled(t, "SaveNotificationSettings", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		repo := new(MockNotificationRepository)
+		usecase := NewNotificationUsecase(repo)
+
+		repo.On("SaveNotificationSettings", ctx, int64(7), mock.Anything).Return(repository.ErrNotFound)
+
+		_, err := usecase.SaveSettings(ctx, 7, entity.NotificationSettings{})
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
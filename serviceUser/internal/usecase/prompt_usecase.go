@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service1/internal/entity"
+	"service1/internal/repository"
+	"service1/internal/storage"
+	"strings"
+)
+
+// MaxPromptAnswers - сколько вопросов-карточек можно заполнить в анкете
+const MaxPromptAnswers = 3
+
+// maxPromptAnswerLength - ограничение длины ответа, чтобы карточка помещалась в подпись к фото
+const maxPromptAnswerLength = 200
+
+type PromptRepository interface {
+	ListPrompts(ctx context.Context, onlyActive bool) ([]entity.Prompt, error)
+	GetPrompt(ctx context.Context, id int) (*entity.Prompt, error)
+	CreatePrompt(ctx context.Context, text string) (int, error)
+	SetPromptActive(ctx context.Context, id int, active bool) error
+	UserPrompts(ctx context.Context, telegramID int64) ([]entity.PromptAnswer, error)
+	UpsertUserPrompt(ctx context.Context, telegramID int64, promptID int, answer string) error
+	DeleteUserPrompt(ctx context.Context, telegramID int64, promptID int) error
+}
+
+var (
+	// ErrInvalidPrompt - пустой или слишком длинный вопрос либо ответ
+	ErrInvalidPrompt = errors.New("invalid prompt")
+	// ErrPromptNotFound - вопроса нет в каталоге или у пользователя нет ответа на него
+	ErrPromptNotFound = errors.New("prompt not found")
+	// ErrTooManyPrompts - в анкете уже заполнено MaxPromptAnswers вопросов
+	ErrTooManyPrompts = errors.New("too many prompts")
+)
+
+// PromptUsecase - каталог вопросов-карточек и ответы пользователей на них
+type PromptUsecase struct {
+	repo         PromptRepository
+	redisStorage storage.RedisStorage
+}
+
+func NewPromptUsecase(repo PromptRepository, redisStorage storage.RedisStorage) *PromptUsecase {
+	if repo == nil {
+		panic("PromptRepository cannot be nil")
+	}
+	if redisStorage == nil {
+		panic("RedisStorage cannot be nil")
+	}
+	return &PromptUsecase{repo: repo, redisStorage: redisStorage}
+}
+
+// Catalogue возвращает вопросы, на которые сейчас можно ответить
+func (u *PromptUsecase) Catalogue(ctx context.Context) ([]entity.Prompt, error) {
+	return u.repo.ListPrompts(ctx, true)
+}
+
+func (u *PromptUsecase) CreatePrompt(ctx context.Context, text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || len([]rune(text)) > 255 {
+		return 0, fmt.Errorf("%w: text must be 1-255 characters", ErrInvalidPrompt)
+	}
+	return u.repo.CreatePrompt(ctx, text)
+}
+
+func (u *PromptUsecase) SetPromptActive(ctx context.Context, id int, active bool) error {
+	err := u.repo.SetPromptActive(ctx, id, active)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %d", ErrPromptNotFound, id)
+	}
+	return err
+}
+
+func (u *PromptUsecase) UserPrompts(ctx context.Context, telegramID int64) ([]entity.PromptAnswer, error) {
+	if telegramID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	return u.repo.UserPrompts(ctx, telegramID)
+}
+
+// Answer сохраняет ответ на вопрос. Новый вопрос нельзя добавить, если
+// в анкете уже MaxPromptAnswers ответов, но существующий ответ можно изменить.
+func (u *PromptUsecase) Answer(ctx context.Context, telegramID int64, promptID int, answer string) error {
+	if telegramID <= 0 {
+		return errors.New("invalid id")
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" || len([]rune(answer)) > maxPromptAnswerLength {
+		return fmt.Errorf("%w: answer must be 1-%d characters", ErrInvalidPrompt, maxPromptAnswerLength)
+	}
+
+	prompt, err := u.repo.GetPrompt(ctx, promptID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !prompt.Active) {
+		return fmt.Errorf("%w: %d", ErrPromptNotFound, promptID)
+	}
+	if err != nil {
+		return err
+	}
+
+	current, err := u.repo.UserPrompts(ctx, telegramID)
+	if err != nil {
+		return err
+	}
+	answered := false
+	for _, a := range current {
+		if a.PromptID == promptID {
+			answered = true
+			break
+		}
+	}
+	if !answered && len(current) >= MaxPromptAnswers {
+		return ErrTooManyPrompts
+	}
+
+	if err := u.repo.UpsertUserPrompt(ctx, telegramID, promptID, answer); err != nil {
+		return err
+	}
+	_ = u.redisStorage.Del(ctx, fmt.Sprintf("user:%d", telegramID))
+	return nil
+}
+
+func (u *PromptUsecase) DeleteAnswer(ctx context.Context, telegramID int64, promptID int) error {
+	if telegramID <= 0 {
+		return errors.New("invalid id")
+	}
+	err := u.repo.DeleteUserPrompt(ctx, telegramID, promptID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %d", ErrPromptNotFound, promptID)
+	}
+	if err != nil {
+		return err
+	}
+	_ = u.redisStorage.Del(ctx, fmt.Sprintf("user:%d", telegramID))
+	return nil
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"service1/internal/entity"
+	"service1/internal/repository"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPromptRepository struct {
+	mock.Mock
+}
+
+func (m *MockPromptRepository) ListPrompts(ctx context.Context, onlyActive bool) ([]entity.Prompt, error) {
+	args := m.Called(ctx, onlyActive)
+	return args.Get(0).([]entity.Prompt), args.Error(1)
+}
+
+func (m *MockPromptRepository) GetPrompt(ctx context.Context, id int) (*entity.Prompt, error) {
+	args := m.Called(ctx, id)
+	prompt, _ := args.Get(0).(*entity.Prompt)
+	return prompt, args.Error(1)
+}
+
+func (m *MockPromptRepository) CreatePrompt(ctx context.Context, text string) (int, error) {
+	args := m.Called(ctx, text)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPromptRepository) SetPromptActive(ctx context.Context, id int, active bool) error {
+	args := m.Called(ctx, id, active)
+	return args.Error(0)
+}
+
+func (m *MockPromptRepository) UserPrompts(ctx context.Context, telegramID int64) ([]entity.PromptAnswer, error) {
+	args := m.Called(ctx, telegramID)
+	return args.Get(0).([]entity.PromptAnswer), args.Error(1)
+}
+
+func (m *MockPromptRepository) UpsertUserPrompt(ctx context.Context, telegramID int64, promptID int, answer string) error {
+	args := m.Called(ctx, telegramID, promptID, answer)
+	return args.Error(0)
+}
+
+func (m *MockPromptRepository) DeleteUserPrompt(ctx context.Context, telegramID int64, promptID int) error {
+	args := m.Called(ctx, telegramID, promptID)
+	return args.Error(0)
+}
+
+func TestPromptUsecase_Answer(t *testing.T) {
+	ctx := context.Background()
+	threeAnswers := []entity.PromptAnswer{{PromptID: 1}, {PromptID: 2}, {PromptID: 3}}
+
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockPromptRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewPromptUsecase(repo, redisStorage)
+
+		repo.On("GetPrompt", ctx, 4).Return(&entity.Prompt{ID: 4, Active: true}, nil)
+		repo.On("UserPrompts", ctx, int64(7)).Return([]entity.PromptAnswer{{PromptID: 1}}, nil)
+		repo.On("UpsertUserPrompt", ctx, int64(7), 4, "Пикник на крыше").Return(nil)
+		redisStorage.On("Del", ctx, []string{"user:7"}).Return(redis.NewIntResult(1, nil))
+
+		err := usecase.Answer(ctx, 7, 4, "  Пикник на крыше ")
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		redisStorage.AssertExpectations(t)
+	})
+
+	t.Run("Limit reached", func(t *testing.T) {
+		repo := new(MockPromptRepository)
+		usecase := NewPromptUsecase(repo, new(MockRedisStorage))
+
+		repo.On("GetPrompt", ctx, 4).Return(&entity.Prompt{ID: 4, Active: true}, nil)
+		repo.On("UserPrompts", ctx, int64(7)).Return(threeAnswers, nil)
+
+		err := usecase.Answer(ctx, 7, 4, "ответ")
+
+		assert.ErrorIs(t, err, ErrTooManyPrompts)
+		repo.AssertNotCalled(t, "UpsertUserPrompt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Edit existing answer at limit", func(t *testing.T) {
+		repo := new(MockPromptRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewPromptUsecase(repo, redisStorage)
+
+		repo.On("GetPrompt", ctx, 2).Return(&entity.Prompt{ID: 2, Active: true}, nil)
+		repo.On("UserPrompts", ctx, int64(7)).Return(threeAnswers, nil)
+		repo.On("UpsertUserPrompt", ctx, int64(7), 2, "новый ответ").Return(nil)
+		redisStorage.On("Del", ctx, []string{"user:7"}).Return(redis.NewIntResult(1, nil))
+
+		assert.NoError(t, usecase.Answer(ctx, 7, 2, "новый ответ"))
+	})
+
+	t.Run("Unknown prompt", func(t *testing.T) {
+		repo := new(MockPromptRepository)
+		usecase := NewPromptUsecase(repo, new(MockRedisStorage))
+		repo.On("GetPrompt", ctx, 99).Return(nil, repository.ErrNotFound)
+
+		assert.ErrorIs(t, usecase.Answer(ctx, 7, 99, "ответ"), ErrPromptNotFound)
+	})
+
+	t.Run("Too long", func(t *testing.T) {
+		usecase := NewPromptUsecase(new(MockPromptRepository), new(MockRedisStorage))
+
+		assert.ErrorIs(t, usecase.Answer(ctx, 7, 1, strings.Repeat("а", maxPromptAnswerLength+1)), ErrInvalidPrompt)
+	})
+}
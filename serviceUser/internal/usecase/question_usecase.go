@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service1/internal/entity"
+	"service1/internal/ranking"
+	"service1/internal/repository"
+	"strings"
+)
+
+// Сколько вопросов без ответа отдается за один раз
+const (
+	DefaultQuestionBatch = 3
+	MaxQuestionBatch     = 10
+)
+
+// Ограничения на вопросы анкеты совместимости
+const (
+	minQuestionOptions    = 2
+	maxQuestionOptions    = 6
+	maxQuestionOptionLen  = 64
+	maxQuestionTextLength = 255
+)
+
+type QuestionRepository interface {
+	ListQuestions(ctx context.Context, onlyActive bool) ([]entity.Question, error)
+	GetQuestion(ctx context.Context, id int) (*entity.Question, error)
+	UnansweredQuestions(ctx context.Context, telegramID int64, limit int) ([]entity.Question, error)
+	CreateQuestion(ctx context.Context, text string, options []string) (int, error)
+	SetQuestionActive(ctx context.Context, id int, active bool) error
+	UserAnswers(ctx context.Context, telegramIDs []int64) (map[int64][]entity.QuestionAnswer, error)
+	UpsertAnswer(ctx context.Context, telegramID int64, answer entity.QuestionAnswer) error
+	DeleteAnswer(ctx context.Context, telegramID int64, questionID int) error
+}
+
+var (
+	// ErrInvalidQuestion - некорректный вопрос или ответ на него
+	ErrInvalidQuestion = errors.New("invalid question")
+	// ErrQuestionNotFound - вопроса нет или у пользователя нет ответа на него
+	ErrQuestionNotFound = errors.New("question not found")
+)
+
+// QuestionUsecase - анкета совместимости: каталог вопросов, ответы и процент совместимости
+type QuestionUsecase struct {
+	repo QuestionRepository
+}
+
+func NewQuestionUsecase(repo QuestionRepository) *QuestionUsecase {
+	if repo == nil {
+		panic("QuestionRepository cannot be nil")
+	}
+	return &QuestionUsecase{repo: repo}
+}
+
+// Catalogue возвращает вопросы, на которые сейчас можно ответить
+func (u *QuestionUsecase) Catalogue(ctx context.Context) ([]entity.Question, error) {
+	return u.repo.ListQuestions(ctx, true)
+}
+
+// Next - следующие вопросы без ответа, чтобы отвечать на них по несколько за раз
+func (u *QuestionUsecase) Next(ctx context.Context, telegramID int64, limit int) ([]entity.Question, error) {
+	if telegramID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	if limit <= 0 {
+		limit = DefaultQuestionBatch
+	}
+	if limit > MaxQuestionBatch {
+		limit = MaxQuestionBatch
+	}
+	return u.repo.UnansweredQuestions(ctx, telegramID, limit)
+}
+
+func (u *QuestionUsecase) CreateQuestion(ctx context.Context, text string, options []string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || len([]rune(text)) > maxQuestionTextLength {
+		return 0, fmt.Errorf("%w: text must be 1-%d characters", ErrInvalidQuestion, maxQuestionTextLength)
+	}
+	if len(options) < minQuestionOptions || len(options) > maxQuestionOptions {
+		return 0, fmt.Errorf("%w: a question needs %d-%d options", ErrInvalidQuestion, minQuestionOptions, maxQuestionOptions)
+	}
+	seen := make(map[string]bool, len(options))
+	for i, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" || len([]rune(option)) > maxQuestionOptionLen || seen[option] {
+			return 0, fmt.Errorf("%w: option %d must be unique and 1-%d characters", ErrInvalidQuestion, i, maxQuestionOptionLen)
+		}
+		seen[option] = true
+		options[i] = option
+	}
+	return u.repo.CreateQuestion(ctx, text, options)
+}
+
+func (u *QuestionUsecase) SetQuestionActive(ctx context.Context, id int, active bool) error {
+	err := u.repo.SetQuestionActive(ctx, id, active)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %d", ErrQuestionNotFound, id)
+	}
+	return err
+}
+
+func (u *QuestionUsecase) UserAnswers(ctx context.Context, telegramID int64) ([]entity.QuestionAnswer, error) {
+	if telegramID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	answers, err := u.repo.UserAnswers(ctx, []int64{telegramID})
+	if err != nil {
+		return nil, err
+	}
+	return answers[telegramID], nil
+}
+
+// Answer сохраняет ответ на вопрос. Свой ответ и подходящие варианты - индексы
+// вариантов вопроса; для неважного вопроса подходящие варианты можно не указывать
+func (u *QuestionUsecase) Answer(ctx context.Context, telegramID int64, answer entity.QuestionAnswer) error {
+	if telegramID <= 0 {
+		return errors.New("invalid id")
+	}
+	question, err := u.repo.GetQuestion(ctx, answer.QuestionID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !question.Active) {
+		return fmt.Errorf("%w: %d", ErrQuestionNotFound, answer.QuestionID)
+	}
+	if err != nil {
+		return err
+	}
+
+	options := len(question.Options)
+	if answer.Answer < 0 || answer.Answer >= options {
+		return fmt.Errorf("%w: answer must be an option index below %d", ErrInvalidQuestion, options)
+	}
+	if answer.Importance < entity.ImportanceIrrelevant || answer.Importance > entity.ImportanceMandatory {
+		return fmt.Errorf("%w: importance must be %d-%d", ErrInvalidQuestion, entity.ImportanceIrrelevant, entity.ImportanceMandatory)
+	}
+	if len(answer.Acceptable) == 0 {
+		if answer.Importance != entity.ImportanceIrrelevant {
+			return fmt.Errorf("%w: acceptable answers are required", ErrInvalidQuestion)
+		}
+		// Неважный вопрос: подходит любой ответ
+		for i := 0; i < options; i++ {
+			answer.Acceptable = append(answer.Acceptable, i)
+		}
+	}
+	seen := make(map[int]bool, len(answer.Acceptable))
+	for _, a := range answer.Acceptable {
+		if a < 0 || a >= options || seen[a] {
+			return fmt.Errorf("%w: acceptable answers must be unique option indexes", ErrInvalidQuestion)
+		}
+		seen[a] = true
+	}
+	return u.repo.UpsertAnswer(ctx, telegramID, answer)
+}
+
+func (u *QuestionUsecase) DeleteAnswer(ctx context.Context, telegramID int64, questionID int) error {
+	if telegramID <= 0 {
+		return errors.New("invalid id")
+	}
+	err := u.repo.DeleteAnswer(ctx, telegramID, questionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %d", ErrQuestionNotFound, questionID)
+	}
+	return err
+}
+
+// Compatibility - процент совместимости двух пользователей; nil, если общих вопросов нет
+func (u *QuestionUsecase) Compatibility(ctx context.Context, a, b int64) (*int, int, error) {
+	if a <= 0 || b <= 0 {
+		return nil, 0, errors.New("invalid id")
+	}
+	answers, err := u.repo.UserAnswers(ctx, []int64{a, b})
+	if err != nil {
+		return nil, 0, err
+	}
+	percent, common := ranking.Compatibility(answers[a], answers[b])
+	if common == 0 {
+		return nil, 0, nil
+	}
+	return &percent, common, nil
+}
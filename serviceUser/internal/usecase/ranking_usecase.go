@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"service1/internal/entity"
+	"service1/internal/ranking"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidLocation - координаты вне допустимого диапазона
+var ErrInvalidLocation = errors.New("invalid location")
+
+// rankFeed - упорядочивает выдачу для зрителя по варианту эксперимента, в который он
+// попал, и пишет ленту в журнал. Если анкету зрителя загрузить не удалось,
+// выдача возвращается без ранжирования
+func (u *UserUsecase) rankFeed(ctx context.Context, viewerID int64, users []entity.User) []entity.User {
+	viewer, err := u.repo.GetUserByID(ctx, viewerID)
+	if err != nil {
+		logrus.Warn("Не удалось загрузить анкету для ранжирования: ", err)
+		return users
+	}
+
+	variant := u.experiment.Assign(viewerID)
+	scored := u.ranker.Rank(viewer, users, variant.Weights)
+
+	ranked := make([]entity.User, 0, len(scored))
+	entry := entity.FeedLog{
+		ViewerID:     viewerID,
+		Experiment:   u.experiment.Name,
+		Variant:      variant.Name,
+		CandidateIDs: make([]int64, 0, len(scored)),
+		Scores:       make([]float64, 0, len(scored)),
+	}
+	for _, s := range scored {
+		ranked = append(ranked, s.User)
+		entry.CandidateIDs = append(entry.CandidateIDs, s.User.TelegramID)
+		entry.Scores = append(entry.Scores, s.Score)
+	}
+	if err := u.repo.LogFeed(ctx, entry); err != nil {
+		logrus.Warn("Не удалось записать ленту в журнал: ", err)
+	}
+	return ranked
+}
+
+// attachCompatibility - проставляет кандидатам процент совместимости со зрителем
+// по анкете вопросов. Ошибка загрузки ответов не ломает выдачу
+func (u *UserUsecase) attachCompatibility(ctx context.Context, viewerID int64, users []entity.User) {
+	if len(users) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(users)+1)
+	ids = append(ids, viewerID)
+	for _, user := range users {
+		ids = append(ids, user.TelegramID)
+	}
+	answers, err := u.repo.QuestionAnswers(ctx, ids)
+	if err != nil {
+		logrus.Warn("Не удалось загрузить ответы анкеты совместимости: ", err)
+		return
+	}
+	if len(answers[viewerID]) == 0 {
+		return
+	}
+	for i := range users {
+		if percent, common := ranking.Compatibility(answers[viewerID], answers[users[i].TelegramID]); common > 0 {
+			users[i].Compatibility = &percent
+		}
+	}
+}
+
+// RateUser - обновляет Elo-рейтинг анкеты targetID по исходу свайпа raterID
+func (u *UserUsecase) RateUser(ctx context.Context, targetID, raterID int64, liked bool) error {
+	if targetID <= 0 || raterID <= 0 || targetID == raterID {
+		return errors.New("invalid id")
+	}
+	score := 0.0
+	if liked {
+		score = 1
+	}
+	return u.repo.UpdateRating(ctx, targetID, raterID, score, ranking.EloK)
+}
+
+// RevertRating - откатывает Elo-обновление после отмены свайпа raterID
+func (u *UserUsecase) RevertRating(ctx context.Context, targetID, raterID int64, liked bool) error {
+	if targetID <= 0 || raterID <= 0 || targetID == raterID {
+		return errors.New("invalid id")
+	}
+	score := 0.0
+	if liked {
+		score = 1
+	}
+	return u.repo.RevertRating(ctx, targetID, raterID, score, ranking.EloK)
+}
+
+// SetLocation - сохраняет координаты пользователя для скорера расстояния
+func (u *UserUsecase) SetLocation(ctx context.Context, telegramID int64, point entity.GeoPoint) error {
+	if telegramID <= 0 {
+		return errors.New("invalid id")
+	}
+	if point.Latitude < -90 || point.Latitude > 90 || point.Longitude < -180 || point.Longitude > 180 {
+		return ErrInvalidLocation
+	}
+	return u.repo.SetLocation(ctx, telegramID, point)
+}
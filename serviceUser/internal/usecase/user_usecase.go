@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"mime/multipart"
 	"service1/internal/entity"
+	"service1/internal/ranking"
+	"service1/internal/repository"
 	"service1/internal/storage"
+	"strings"
 	"time"
 )
 
@@ -17,12 +20,28 @@ type UserRepository interface {
 	UpdateUser(ctx context.Context, id int, user *entity.User) error
 	DeleteUser(ctx context.Context, id int64) error
 	SearchUsers(ctx context.Context, filter entity.UserFilter) ([]entity.User, error)
+	TouchUsers(ctx context.Context, activity map[int64]time.Time) error
+	HideUser(ctx context.Context, telegramID, hiddenID int64) error
+	UpdateRating(ctx context.Context, targetID, raterID int64, score, k float64) error
+	RevertRating(ctx context.Context, targetID, raterID int64, score, k float64) error
+	SetLocation(ctx context.Context, telegramID int64, point entity.GeoPoint) error
+	SetLanguage(ctx context.Context, telegramID int64, languageCode string) error
+	MarkUnreachable(ctx context.Context, telegramID int64, reason string, at time.Time) error
+	Reactivate(ctx context.Context, telegramID int64) (bool, error)
+	InactiveWithNewProfiles(ctx context.Context, inactiveSince time.Time, limit, offset int) ([]entity.InactiveUser, error)
+	LogFeed(ctx context.Context, entry entity.FeedLog) error
+	QuestionAnswers(ctx context.Context, telegramIDs []int64) (map[int64][]entity.QuestionAnswer, error)
 }
 
+// ErrInvalidGender - неизвестный код пола или ориентации
+var ErrInvalidGender = errors.New("invalid gender code")
+
 type UserUsecase struct {
 	repo         UserRepository
 	fileStorage  storage.FileStorage
 	redisStorage storage.RedisStorage
+	ranker       *ranking.Ranker
+	experiment   ranking.Experiment
 }
 
 func NewUserUsecase(repo UserRepository, fileStorage storage.FileStorage, redisStorage storage.RedisStorage) *UserUsecase {
@@ -36,10 +55,18 @@ func NewUserUsecase(repo UserRepository, fileStorage storage.FileStorage, redisS
 		panic("RedisStorage cannot be nil")
 	}
 
-	return &UserUsecase{repo: repo, fileStorage: fileStorage, redisStorage: redisStorage}
+	return &UserUsecase{
+		repo:         repo,
+		fileStorage:  fileStorage,
+		redisStorage: redisStorage,
+		ranker:       ranking.NewRanker(ranking.DefaultScorers()...),
+		experiment:   ranking.DefaultExperiment(),
+	}
 }
 
-func (u *UserUsecase) Create(ctx context.Context, name, description, fileName, gender, city string, age int, file multipart.File, filesize, telegramId int64) (int, error) {
+// Create - новая анкета. languageCode - язык интерфейса из Telegram; неизвестный
+// код не мешает регистрации, анкета остается без языка
+func (u *UserUsecase) Create(ctx context.Context, name, description, fileName, gender, city string, interestedIn []string, age int, file multipart.File, filesize, telegramId int64, languageCode string) (int, error) {
 	if name == "" {
 		return 0, errors.New("name is required")
 	}
@@ -49,6 +76,9 @@ func (u *UserUsecase) Create(ctx context.Context, name, description, fileName, g
 	if description == "" {
 		return 0, errors.New("description is required")
 	}
+	if err := validateOrientation(gender, interestedIn); err != nil {
+		return 0, err
+	}
 	url, err := u.fileStorage.UploadFile(ctx, file, fileName, filesize)
 	if err != nil {
 		return 0, err
@@ -61,11 +91,24 @@ func (u *UserUsecase) Create(ctx context.Context, name, description, fileName, g
 		TelegramID:  telegramId,
 		Gender:      gender,
 		City:        city,
+
+		InterestedIn: interestedIn,
+	}
+	if validLanguageCode(languageCode) {
+		user.LanguageCode = strings.ToLower(languageCode)
 	}
 	return u.repo.CreateUser(ctx, user)
 }
 
 func (u *UserUsecase) Search(ctx context.Context, filter entity.UserFilter) ([]entity.User, error) {
+	for _, g := range filter.Genders {
+		if !entity.IsValidGender(g) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidGender, g)
+		}
+	}
+	if filter.InterestedIn != "" && !entity.IsValidGender(filter.InterestedIn) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGender, filter.InterestedIn)
+	}
 	cacheKey := fmt.Sprintf("search:%+v", filter)
 
 	cachedData, err := u.redisStorage.Get(ctx, cacheKey).Result()
@@ -85,8 +128,20 @@ func (u *UserUsecase) Search(ctx context.Context, filter entity.UserFilter) ([]e
 	if err != nil {
 		return nil, err
 	}
-	u.redisStorage.Set(ctx, cacheKey, data, 24*time.Hour)
+	// Выдача с учетом активности или скрытых анкет быстро устаревает, поэтому кэшируем ее ненадолго
+	ttl := 24 * time.Hour
+	if filter.ActiveWithin > 0 || filter.SortBy == entity.SortByRecency || filter.ExcludeFor > 0 {
+		ttl = time.Minute
+	}
+	u.redisStorage.Set(ctx, cacheKey, data, ttl)
 
+	// Совместимость зависит от зрителя, поэтому считается уже после кэша
+	if filter.ExcludeFor > 0 {
+		u.attachCompatibility(ctx, filter.ExcludeFor, users)
+	}
+	if filter.SortBy == entity.SortByRank && filter.ExcludeFor > 0 {
+		return u.rankFeed(ctx, filter.ExcludeFor, users), nil
+	}
 	return users, nil
 }
 
@@ -107,6 +162,9 @@ func (u *UserUsecase) GetByID(ctx context.Context, telegram_id int64) (*entity.U
 	if err != nil {
 		return nil, err
 	}
+	if ts, ok := u.pendingActivity(ctx, telegram_id); ok && ts.After(user.LastActiveAt) {
+		user.LastActiveAt = ts
+	}
 
 	userJSON, err := json.Marshal(user)
 	if err != nil {
@@ -117,7 +175,144 @@ func (u *UserUsecase) GetByID(ctx context.Context, telegram_id int64) (*entity.U
 	return user, nil
 }
 
-func (u *UserUsecase) Update(ctx context.Context, name, description, fileName, gender, city string, age, id int, file multipart.File, filesize, telegramId int64) error {
+// HideUser - убирает hiddenID из выдачи пользователя telegramID навсегда
+func (u *UserUsecase) HideUser(ctx context.Context, telegramID, hiddenID int64) error {
+	if telegramID <= 0 || hiddenID <= 0 || telegramID == hiddenID {
+		return errors.New("invalid id")
+	}
+	return u.repo.HideUser(ctx, telegramID, hiddenID)
+}
+
+// ErrInvalidLanguage - код языка не похож на language_code из Telegram
+var ErrInvalidLanguage = errors.New("invalid language code")
+
+// SetLanguage - сохраняет язык интерфейса из Telegram (ru, en, pt-br)
+func (u *UserUsecase) SetLanguage(ctx context.Context, telegramID int64, languageCode string) error {
+	if telegramID <= 0 {
+		return errors.New("invalid id")
+	}
+	if !validLanguageCode(languageCode) {
+		return ErrInvalidLanguage
+	}
+	err := u.repo.SetLanguage(ctx, telegramID, strings.ToLower(languageCode))
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %d", ErrUserNotFound, telegramID)
+	}
+	if err != nil {
+		return err
+	}
+	_ = u.redisStorage.Del(ctx, fmt.Sprintf("user:%d", telegramID))
+	return nil
+}
+
+// ErrInvalidReason - неизвестная причина недоступности пользователя
+var ErrInvalidReason = errors.New("invalid unreachable reason")
+
+// MarkUnreachable - бот не смог доставить сообщение: пользователь заблокировал
+// бота, удалил аккаунт или чат не найден. Анкета перестает показываться в выдаче
+func (u *UserUsecase) MarkUnreachable(ctx context.Context, telegramID int64, reason string, at time.Time) error {
+	if telegramID <= 0 {
+		return errors.New("invalid id")
+	}
+	switch reason {
+	case entity.UnreachableBlocked, entity.UnreachableChatNotFound, entity.UnreachableDeactivated:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidReason, reason)
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if err := u.repo.MarkUnreachable(ctx, telegramID, reason, at); err != nil {
+		return err
+	}
+	_ = u.redisStorage.Del(ctx, fmt.Sprintf("user:%d", telegramID))
+	return nil
+}
+
+// Reactivate - пользователь снова запустил бота, анкета возвращается в выдачу
+func (u *UserUsecase) Reactivate(ctx context.Context, telegramID int64) error {
+	if telegramID <= 0 {
+		return errors.New("invalid id")
+	}
+	changed, err := u.repo.Reactivate(ctx, telegramID)
+	if err != nil {
+		return err
+	}
+	if changed {
+		_ = u.redisStorage.Del(ctx, fmt.Sprintf("user:%d", telegramID))
+	}
+	return nil
+}
+
+// Ограничения выборки неактивных пользователей за один запрос
+const (
+	DefaultInactiveLimit = 100
+	MaxInactiveLimit     = 500
+)
+
+// InactiveUsers - кто не заходил дольше inactiveFor, и сколько новых анкет
+// появилось в его городе. hasMore сообщает, есть ли следующая страница
+func (u *UserUsecase) InactiveUsers(ctx context.Context, inactiveFor time.Duration, limit, offset int) (users []entity.InactiveUser, hasMore bool, err error) {
+	if limit <= 0 {
+		limit = DefaultInactiveLimit
+	}
+	limit = min(limit, MaxInactiveLimit)
+	offset = max(offset, 0)
+
+	users, err = u.repo.InactiveWithNewProfiles(ctx, time.Now().Add(-inactiveFor), limit+1, offset)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(users) > limit {
+		users, hasMore = users[:limit], true
+	}
+	return users, hasMore, nil
+}
+
+func validLanguageCode(code string) bool {
+	if len(code) < 2 || len(code) > 16 {
+		return false
+	}
+	for _, r := range code {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxBatchSize - сколько анкет можно запросить одним пакетом
+const MaxBatchSize = 100
+
+// ErrBatchTooLarge - в пакетном запросе слишком много идентификаторов
+var ErrBatchTooLarge = errors.New("too many ids in batch")
+
+// GetByIDs - пакетное получение анкет для других сервисов.
+// Отсутствующие анкеты просто не попадают в результат
+func (u *UserUsecase) GetByIDs(ctx context.Context, ids []int64) ([]entity.User, error) {
+	if len(ids) == 0 {
+		return []entity.User{}, nil
+	}
+	if len(ids) > MaxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+
+	users, err := u.repo.SearchUsers(ctx, entity.UserFilter{TelegramIDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if ts, ok := u.pendingActivity(ctx, users[i].TelegramID); ok && ts.After(users[i].LastActiveAt) {
+			users[i].LastActiveAt = ts
+		}
+	}
+	if users == nil {
+		users = []entity.User{}
+	}
+	return users, nil
+}
+
+func (u *UserUsecase) Update(ctx context.Context, name, description, fileName, gender, city string, interestedIn []string, age, id int, file multipart.File, filesize, telegramId int64) error {
 	if id <= 0 {
 		return errors.New("invalid id")
 	}
@@ -136,6 +331,9 @@ func (u *UserUsecase) Update(ctx context.Context, name, description, fileName, g
 	if gender == "" {
 		return errors.New("gender is required")
 	}
+	if err := validateOrientation(gender, interestedIn); err != nil {
+		return err
+	}
 
 	url, err := u.fileStorage.UploadFile(ctx, file, fileName, filesize)
 	if err != nil {
@@ -149,6 +347,8 @@ func (u *UserUsecase) Update(ctx context.Context, name, description, fileName, g
 		TelegramID:  telegramId,
 		Gender:      gender,
 		City:        city,
+
+		InterestedIn: interestedIn,
 	}
 
 	cacheKey := fmt.Sprintf("user:%d", id)
@@ -172,3 +372,24 @@ func (u *UserUsecase) Delete(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// validateOrientation проверяет пол пользователя и список тех, кто ему интересен
+func validateOrientation(gender string, interestedIn []string) error {
+	if !entity.IsValidGender(gender) {
+		return fmt.Errorf("%w: %q", ErrInvalidGender, gender)
+	}
+	if len(interestedIn) == 0 {
+		return errors.New("interested_in is required")
+	}
+	seen := make(map[string]bool, len(interestedIn))
+	for _, g := range interestedIn {
+		if !entity.IsValidGender(g) {
+			return fmt.Errorf("%w: %q", ErrInvalidGender, g)
+		}
+		if seen[g] {
+			return fmt.Errorf("duplicate interested_in value %q", g)
+		}
+		seen[g] = true
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"mime/multipart"
 	"service1/internal/entity"
+	"service1/internal/repository"
 	"testing"
 	"time"
 
@@ -43,6 +44,63 @@ func (m *MockRepository) SearchUsers(ctx context.Context, filter entity.UserFilt
 	return args.Get(0).([]entity.User), args.Error(1)
 }
 
+func (m *MockRepository) HideUser(ctx context.Context, telegramID, hiddenID int64) error {
+	args := m.Called(ctx, telegramID, hiddenID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateRating(ctx context.Context, targetID, raterID int64, score, k float64) error {
+	args := m.Called(ctx, targetID, raterID, score, k)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RevertRating(ctx context.Context, targetID, raterID int64, score, k float64) error {
+	args := m.Called(ctx, targetID, raterID, score, k)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetLocation(ctx context.Context, telegramID int64, point entity.GeoPoint) error {
+	args := m.Called(ctx, telegramID, point)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetLanguage(ctx context.Context, telegramID int64, languageCode string) error {
+	args := m.Called(ctx, telegramID, languageCode)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MarkUnreachable(ctx context.Context, telegramID int64, reason string, at time.Time) error {
+	args := m.Called(ctx, telegramID, reason, at)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Reactivate(ctx context.Context, telegramID int64) (bool, error) {
+	args := m.Called(ctx, telegramID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) InactiveWithNewProfiles(ctx context.Context, inactiveSince time.Time, limit, offset int) ([]entity.InactiveUser, error) {
+	args := m.Called(ctx, inactiveSince, limit, offset)
+	users, _ := args.Get(0).([]entity.InactiveUser)
+	return users, args.Error(1)
+}
+
+func (m *MockRepository) LogFeed(ctx context.Context, entry entity.FeedLog) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockRepository) QuestionAnswers(ctx context.Context, telegramIDs []int64) (map[int64][]entity.QuestionAnswer, error) {
+	args := m.Called(ctx, telegramIDs)
+	answers, _ := args.Get(0).(map[int64][]entity.QuestionAnswer)
+	return answers, args.Error(1)
+}
+
+func (m *MockRepository) TouchUsers(ctx context.Context, activity map[int64]time.Time) error {
+	args := m.Called(ctx, activity)
+	return args.Error(0)
+}
+
 type MockFileStorage struct {
 	mock.Mock
 }
@@ -71,6 +129,41 @@ func (m *MockRedisStorage) Del(ctx context.Context, keys ...string) *redis.IntCm
 	return args.Get(0).(*redis.IntCmd)
 }
 
+func (m *MockRedisStorage) HSet(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	args := m.Called(ctx, key, values)
+	return args.Get(0).(*redis.IntCmd)
+}
+
+func (m *MockRedisStorage) HGet(ctx context.Context, key, field string) *redis.StringCmd {
+	args := m.Called(ctx, key, field)
+	return args.Get(0).(*redis.StringCmd)
+}
+
+func (m *MockRedisStorage) HGetAll(ctx context.Context, key string) *redis.MapStringStringCmd {
+	args := m.Called(ctx, key)
+	return args.Get(0).(*redis.MapStringStringCmd)
+}
+
+func (m *MockRedisStorage) HSetNX(ctx context.Context, key, field string, value interface{}) *redis.BoolCmd {
+	args := m.Called(ctx, key, field, value)
+	return args.Get(0).(*redis.BoolCmd)
+}
+
+func (m *MockRedisStorage) Incr(ctx context.Context, key string) *redis.IntCmd {
+	args := m.Called(ctx, key)
+	return args.Get(0).(*redis.IntCmd)
+}
+
+func (m *MockRedisStorage) Exists(ctx context.Context, keys ...string) *redis.IntCmd {
+	args := m.Called(ctx, keys)
+	return args.Get(0).(*redis.IntCmd)
+}
+
+func (m *MockRedisStorage) Rename(ctx context.Context, key, newkey string) *redis.StatusCmd {
+	args := m.Called(ctx, key, newkey)
+	return args.Get(0).(*redis.StatusCmd)
+}
+
 func TestUserUsecase_Create(t *testing.T) {
 	repo := new(MockRepository)
 	fileStorage := new(MockFileStorage)
@@ -87,13 +180,14 @@ func TestUserUsecase_Create(t *testing.T) {
 		age := 25
 		file := new(multipart.File)
 		fileSize := int64(1024)
-		gender := "men"
+		gender := "male"
 		city := "moscow"
+		interestedIn := []string{"female"}
 
 		fileStorage.On("UploadFile", ctx, file, fileName, fileSize).Return("http://example.com/photo.jpg", nil)
 		repo.On("CreateUser", ctx, mock.AnythingOfType("*entity.User")).Return(1, nil)
 
-		userID, err := usecase.Create(ctx, name, description, fileName, gender, city, age, *file, fileSize, telegramID)
+		userID, err := usecase.Create(ctx, name, description, fileName, gender, city, interestedIn, age, *file, fileSize, telegramID, "ru")
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, userID)
@@ -113,10 +207,11 @@ func TestUserUsecase_Create(t *testing.T) {
 		file := new(multipart.File)
 		fileSize := int64(1024)
 		telegramID := int64(321312312)
-		gender := "men"
+		gender := "male"
 		city := "moscow"
+		interestedIn := []string{"female"}
 		fileStorage.On("UploadFile", ctx, file, fileName, fileSize).Return("", errors.New("upload error"))
-		userID, err := usecase.Create(ctx, name, description, fileName, gender, city, age, *file, fileSize, telegramID)
+		userID, err := usecase.Create(ctx, name, description, fileName, gender, city, interestedIn, age, *file, fileSize, telegramID, "ru")
 
 		// Проверяем, что произошла ошибка
 		assert.Error(t, err)
@@ -201,14 +296,15 @@ func TestUserUsecase_Update(t *testing.T) {
 		file := new(multipart.File)
 		filesize := int64(1024)
 		telegramID := int64(321312312)
-		gender := "men"
+		gender := "male"
 		city := "moscow"
+		interestedIn := []string{"female"}
 
 		fileStorage.On("UploadFile", ctx, file, filename, filesize).Return("http://example.com/photo.jpg", nil)
 		redisStorage.On("Del", fmt.Sprintf("user:%d", id)).Return(nil)
 		repo.On("UpdateUser", ctx, id, mock.AnythingOfType("*entity.User")).Return(nil)
 
-		err := usecase.Update(ctx, name, description, filename, gender, city, age, id, *file, filesize, telegramID)
+		err := usecase.Update(ctx, name, description, filename, gender, city, interestedIn, age, id, *file, filesize, telegramID)
 
 		assert.NoError(t, err)
 
@@ -228,12 +324,13 @@ func TestUserUsecase_Update(t *testing.T) {
 		file := new(multipart.File)
 		filesize := int64(1024)
 		telegramID := int64(321312312)
-		gender := "men"
+		gender := "male"
 		city := "moscow"
+		interestedIn := []string{"female"}
 
 		fileStorage.On("UploadFile", ctx, file, filename, filesize).Return("http://example.com/photo.jpg", nil)
 
-		err := usecase.Update(ctx, name, description, filename, gender, city, age, id, *file, filesize, telegramID)
+		err := usecase.Update(ctx, name, description, filename, gender, city, interestedIn, age, id, *file, filesize, telegramID)
 
 		assert.Error(t, err)
 
@@ -314,3 +411,264 @@ func TestUserUsecase_Search(t *testing.T) {
 		repo.AssertExpectations(t)
 	})
 }
+
+func TestUserUsecase_FlushActivity(t *testing.T) {
+	snapshotKey := activityFlushingKey + ":7"
+
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), redisStorage)
+		ctx := context.Background()
+
+		redisStorage.On("Exists", ctx, []string{activityPendingKey}).Return(redis.NewIntResult(1, nil))
+		redisStorage.On("Incr", ctx, activityFlushSeqKey).Return(redis.NewIntResult(7, nil))
+		redisStorage.On("Rename", ctx, activityPendingKey, snapshotKey).Return(redis.NewStatusResult("OK", nil))
+		redisStorage.On("HGetAll", ctx, snapshotKey).Return(redis.NewMapStringStringResult(map[string]string{"42": "1700000000"}, nil))
+		repo.On("TouchUsers", ctx, map[int64]time.Time{42: time.Unix(1700000000, 0)}).Return(nil)
+		redisStorage.On("Del", ctx, []string{"user:42", snapshotKey}).Return(redis.NewIntResult(2, nil))
+
+		err := usecase.FlushActivity(ctx)
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		redisStorage.AssertExpectations(t)
+	})
+
+	t.Run("Nothing to flush", func(t *testing.T) {
+		repo := new(MockRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), redisStorage)
+		ctx := context.Background()
+
+		redisStorage.On("Exists", ctx, []string{activityPendingKey}).Return(redis.NewIntResult(0, nil))
+
+		err := usecase.FlushActivity(ctx)
+
+		assert.NoError(t, err)
+		repo.AssertNotCalled(t, "TouchUsers", mock.Anything, mock.Anything)
+		redisStorage.AssertNotCalled(t, "Rename", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail on Rename", func(t *testing.T) {
+		repo := new(MockRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), redisStorage)
+		ctx := context.Background()
+
+		redisStorage.On("Exists", ctx, []string{activityPendingKey}).Return(redis.NewIntResult(1, nil))
+		redisStorage.On("Incr", ctx, activityFlushSeqKey).Return(redis.NewIntResult(7, nil))
+		redisStorage.On("Rename", ctx, activityPendingKey, snapshotKey).Return(redis.NewStatusResult("", errors.New("redis down")))
+
+		err := usecase.FlushActivity(ctx)
+
+		assert.Error(t, err)
+		repo.AssertNotCalled(t, "TouchUsers", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail on TouchUsers", func(t *testing.T) {
+		repo := new(MockRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), redisStorage)
+		ctx := context.Background()
+
+		redisStorage.On("Exists", ctx, []string{activityPendingKey}).Return(redis.NewIntResult(1, nil))
+		redisStorage.On("Incr", ctx, activityFlushSeqKey).Return(redis.NewIntResult(7, nil))
+		redisStorage.On("Rename", ctx, activityPendingKey, snapshotKey).Return(redis.NewStatusResult("OK", nil))
+		redisStorage.On("HGetAll", ctx, snapshotKey).Return(redis.NewMapStringStringResult(map[string]string{"42": "1700000000"}, nil))
+		repo.On("TouchUsers", ctx, mock.Anything).Return(errors.New("db down"))
+		redisStorage.On("HSetNX", ctx, activityPendingKey, "42", "1700000000").Return(redis.NewBoolResult(true, nil))
+		redisStorage.On("Del", ctx, []string{snapshotKey}).Return(redis.NewIntResult(1, nil))
+
+		err := usecase.FlushActivity(ctx)
+
+		assert.Error(t, err)
+		redisStorage.AssertExpectations(t)
+		redisStorage.AssertNotCalled(t, "Del", ctx, []string{"user:42", snapshotKey})
+	})
+
+	t.Run("Snapshot kept when restore fails", func(t *testing.T) {
+		repo := new(MockRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), redisStorage)
+		ctx := context.Background()
+
+		redisStorage.On("Exists", ctx, []string{activityPendingKey}).Return(redis.NewIntResult(1, nil))
+		redisStorage.On("Incr", ctx, activityFlushSeqKey).Return(redis.NewIntResult(7, nil))
+		redisStorage.On("Rename", ctx, activityPendingKey, snapshotKey).Return(redis.NewStatusResult("OK", nil))
+		redisStorage.On("HGetAll", ctx, snapshotKey).Return(redis.NewMapStringStringResult(map[string]string{"42": "1700000000"}, nil))
+		repo.On("TouchUsers", ctx, mock.Anything).Return(errors.New("db down"))
+		redisStorage.On("HSetNX", ctx, activityPendingKey, "42", "1700000000").Return(redis.NewBoolResult(false, errors.New("redis down")))
+
+		err := usecase.FlushActivity(ctx)
+
+		assert.Error(t, err)
+		redisStorage.AssertNotCalled(t, "Del", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserUsecase_GetByIDs(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), redisStorage)
+		ctx := context.Background()
+
+		repo.On("SearchUsers", ctx, entity.UserFilter{TelegramIDs: []int64{1, 2}}).Return([]entity.User{
+			{TelegramID: 1, LastActiveAt: time.Unix(100, 0)},
+			{TelegramID: 2, LastActiveAt: time.Unix(100, 0)},
+		}, nil)
+		redisStorage.On("HGet", ctx, activityPendingKey, "1").Return(redis.NewStringResult("200", nil))
+		redisStorage.On("HGet", ctx, activityPendingKey, "2").Return(redis.NewStringResult("", redis.Nil))
+
+		users, err := usecase.GetByIDs(ctx, []int64{1, 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, time.Unix(200, 0), users[0].LastActiveAt)
+		assert.Equal(t, time.Unix(100, 0), users[1].LastActiveAt)
+	})
+
+	t.Run("Too many ids", func(t *testing.T) {
+		repo := new(MockRepository)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), new(MockRedisStorage))
+
+		_, err := usecase.GetByIDs(context.Background(), make([]int64, MaxBatchSize+1))
+
+		assert.ErrorIs(t, err, ErrBatchTooLarge)
+		repo.AssertNotCalled(t, "SearchUsers", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserUsecase_SetLanguage(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), redisStorage)
+		ctx := context.Background()
+
+		repo.On("SetLanguage", ctx, int64(1), "pt-br").Return(nil)
+		redisStorage.On("Del", ctx, []string{"user:1"}).Return(redis.NewIntResult(1, nil))
+
+		err := usecase.SetLanguage(ctx, 1, "pt-BR")
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		redisStorage.AssertExpectations(t)
+	})
+
+	t.Run("User not registered", func(t *testing.T) {
+		repo := new(MockRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), redisStorage)
+		ctx := context.Background()
+
+		repo.On("SetLanguage", ctx, int64(1), "en").Return(repository.ErrNotFound)
+
+		err := usecase.SetLanguage(ctx, 1, "en")
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		redisStorage.AssertNotCalled(t, "Del", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid code", func(t *testing.T) {
+		repo := new(MockRepository)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), new(MockRedisStorage))
+
+		for _, code := range []string{"", "r", "ru_RU", "<script>"} {
+			err := usecase.SetLanguage(context.Background(), 1, code)
+			assert.ErrorIs(t, err, ErrInvalidLanguage, code)
+		}
+		repo.AssertNotCalled(t, "SetLanguage", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserUsecase_MarkUnreachable(t *testing.T) {
+	at := time.Date(2026, 10, 19, 3, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), redisStorage)
+		ctx := context.Background()
+
+		repo.On("MarkUnreachable", ctx, int64(1), entity.UnreachableBlocked, at).Return(nil)
+		redisStorage.On("Del", ctx, []string{"user:1"}).Return(redis.NewIntResult(1, nil))
+
+		err := usecase.MarkUnreachable(ctx, 1, entity.UnreachableBlocked, at)
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		redisStorage.AssertExpectations(t)
+	})
+
+	t.Run("Unknown reason", func(t *testing.T) {
+		repo := new(MockRepository)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), new(MockRedisStorage))
+
+		err := usecase.MarkUnreachable(context.Background(), 1, "timeout", at)
+
+		assert.ErrorIs(t, err, ErrInvalidReason)
+		repo.AssertNotCalled(t, "MarkUnreachable", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserUsecase_Reactivate(t *testing.T) {
+	t.Run("Was unreachable", func(t *testing.T) {
+		repo := new(MockRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), redisStorage)
+		ctx := context.Background()
+
+		repo.On("Reactivate", ctx, int64(1)).Return(true, nil)
+		redisStorage.On("Del", ctx, []string{"user:1"}).Return(redis.NewIntResult(1, nil))
+
+		assert.NoError(t, usecase.Reactivate(ctx, 1))
+		redisStorage.AssertExpectations(t)
+	})
+
+	t.Run("Already active", func(t *testing.T) {
+		repo := new(MockRepository)
+		redisStorage := new(MockRedisStorage)
+		usecase := NewUserUsecase(repo, new(MockFileStorage), redisStorage)
+		ctx := context.Background()
+
+		repo.On("Reactivate", ctx, int64(1)).Return(false, nil)
+
+		assert.NoError(t, usecase.Reactivate(ctx, 1))
+		redisStorage.AssertNotCalled(t, "Del", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserUsecase_InactiveUsers(t *testing.T) {
+	repo := new(MockRepository)
+	usecase := NewUserUsecase(repo, new(MockFileStorage), new(MockRedisStorage))
+	ctx := context.Background()
+
+	before := time.Now().Add(-7 * 24 * time.Hour)
+	since := mock.MatchedBy(func(at time.Time) bool {
+		return !at.Before(before) && at.Before(before.Add(time.Minute))
+	})
+	repo.On("InactiveWithNewProfiles", ctx, since, DefaultInactiveLimit+1, 0).
+		Return([]entity.InactiveUser{{TelegramID: 1, NewProfiles: 3}}, nil)
+	repo.On("InactiveWithNewProfiles", ctx, since, 3, 10).
+		Return([]entity.InactiveUser{{TelegramID: 2}, {TelegramID: 3}, {TelegramID: 4}}, nil)
+
+	users, hasMore, err := usecase.InactiveUsers(ctx, 7*24*time.Hour, 0, -5)
+	assert.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, []entity.InactiveUser{{TelegramID: 1, NewProfiles: 3}}, users)
+
+	users, hasMore, err = usecase.InactiveUsers(ctx, 7*24*time.Hour, 2, 10)
+	assert.NoError(t, err)
+	assert.True(t, hasMore)
+	assert.Len(t, users, 2)
+}
+
+func TestValidateOrientation(t *testing.T) {
+	assert.NoError(t, validateOrientation("female", []string{"male", "nonbinary"}))
+	assert.ErrorIs(t, validateOrientation("Девушка", []string{"male"}), ErrInvalidGender)
+	assert.ErrorIs(t, validateOrientation("male", []string{"Парень"}), ErrInvalidGender)
+	assert.Error(t, validateOrientation("male", nil))
+	assert.Error(t, validateOrientation("male", []string{"female", "female"}))
+}
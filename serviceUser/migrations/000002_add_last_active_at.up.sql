@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN last_active_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX idx_users_last_active_at ON users (last_active_at DESC);
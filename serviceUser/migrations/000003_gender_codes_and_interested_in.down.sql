@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_users_interested_in;
+
+ALTER TABLE users DROP COLUMN IF EXISTS interested_in;
+
+UPDATE users SET gender = CASE gender
+    WHEN 'male' THEN 'Парень'
+    WHEN 'female' THEN 'Девушка'
+    ELSE gender
+END;
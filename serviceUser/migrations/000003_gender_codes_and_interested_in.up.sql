@@ -0,0 +1,16 @@
+UPDATE users SET gender = CASE gender
+    WHEN 'Парень' THEN 'male'
+    WHEN 'Девушка' THEN 'female'
+    ELSE gender
+END;
+
+ALTER TABLE users ADD COLUMN interested_in TEXT[] NOT NULL DEFAULT '{}';
+
+-- Раньше поиск просто менял пол на противоположный, сохраняем это поведение
+UPDATE users SET interested_in = CASE gender
+    WHEN 'male' THEN ARRAY['female']
+    WHEN 'female' THEN ARRAY['male']
+    ELSE ARRAY['male', 'female']
+END;
+
+CREATE INDEX idx_users_interested_in ON users USING GIN (interested_in);
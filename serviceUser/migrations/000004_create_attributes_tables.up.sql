@@ -0,0 +1,32 @@
+CREATE TABLE attribute_definitions (
+    key VARCHAR(64) PRIMARY KEY,
+    label VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('int', 'string', 'enum', 'multi_enum')),
+    allowed_values TEXT[] NOT NULL DEFAULT '{}',
+    value_labels JSONB NOT NULL DEFAULT '{}',
+    min_value INT,
+    max_value INT,
+    searchable BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Одна строка на значение: у multi_enum их может быть несколько
+CREATE TABLE user_attributes (
+    telegram_id BIGINT NOT NULL REFERENCES users (telegram_id) ON DELETE CASCADE,
+    key VARCHAR(64) NOT NULL REFERENCES attribute_definitions (key) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    num_value INT,
+    PRIMARY KEY (telegram_id, key, value)
+);
+
+CREATE INDEX idx_user_attributes_key_value ON user_attributes (key, value);
+CREATE INDEX idx_user_attributes_key_num ON user_attributes (key, num_value);
+
+INSERT INTO attribute_definitions (key, label, type, allowed_values, value_labels, min_value, max_value, searchable) VALUES
+    ('height', 'Рост, см', 'int', '{}', '{}', 100, 250, TRUE),
+    ('languages', 'Языки', 'multi_enum', ARRAY['ru', 'en', 'de', 'fr', 'es', 'zh'],
+        '{"ru": "Русский", "en": "Английский", "de": "Немецкий", "fr": "Французский", "es": "Испанский", "zh": "Китайский"}', NULL, NULL, TRUE),
+    ('education', 'Образование', 'enum', ARRAY['secondary', 'college', 'bachelor', 'master', 'phd'],
+        '{"secondary": "Среднее", "college": "Среднее специальное", "bachelor": "Бакалавриат", "master": "Магистратура", "phd": "Аспирантура"}', NULL, NULL, TRUE),
+    ('goal', 'Цель знакомства', 'enum', ARRAY['relationship', 'friendship', 'casual', 'undecided'],
+        '{"relationship": "Отношения", "friendship": "Дружба", "casual": "Общение", "undecided": "Пока не решил(а)"}', NULL, NULL, TRUE);
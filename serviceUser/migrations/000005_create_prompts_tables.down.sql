@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_prompts;
+DROP TABLE IF EXISTS prompts;
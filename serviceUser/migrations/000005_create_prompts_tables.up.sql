@@ -0,0 +1,23 @@
+CREATE TABLE prompts (
+    id SERIAL PRIMARY KEY,
+    text VARCHAR(255) NOT NULL UNIQUE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE user_prompts (
+    telegram_id BIGINT NOT NULL REFERENCES users (telegram_id) ON DELETE CASCADE,
+    prompt_id INT NOT NULL REFERENCES prompts (id) ON DELETE CASCADE,
+    answer TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (telegram_id, prompt_id)
+);
+
+INSERT INTO prompts (text) VALUES
+    ('Идеальное свидание — это…'),
+    ('Я точно сойдусь с тем, кто…'),
+    ('Самый спонтанный поступок в моей жизни…'),
+    ('В выходные меня можно найти…'),
+    ('Мой главный зеленый флаг…'),
+    ('Лучший способ меня рассмешить…');
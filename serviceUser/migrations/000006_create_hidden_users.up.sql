@@ -0,0 +1,7 @@
+-- Анкеты, которые навсегда скрыты из выдачи пользователя (например, после разрыва пары)
+CREATE TABLE hidden_users (
+    telegram_id BIGINT NOT NULL,
+    hidden_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (telegram_id, hidden_id)
+);
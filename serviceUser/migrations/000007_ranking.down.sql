@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS feed_log;
+ALTER TABLE users DROP COLUMN IF EXISTS longitude;
+ALTER TABLE users DROP COLUMN IF EXISTS latitude;
+ALTER TABLE users DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE users DROP COLUMN IF EXISTS rating;
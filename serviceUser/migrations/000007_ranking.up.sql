@@ -0,0 +1,20 @@
+-- Elo-рейтинг привлекательности, обновляется по исходам лайков и дизлайков
+ALTER TABLE users ADD COLUMN rating DOUBLE PRECISION NOT NULL DEFAULT 1500;
+ALTER TABLE users ADD COLUMN rating_count INT NOT NULL DEFAULT 0;
+
+-- Координаты для скорера расстояния, необязательные
+ALTER TABLE users ADD COLUMN latitude DOUBLE PRECISION;
+ALTER TABLE users ADD COLUMN longitude DOUBLE PRECISION;
+
+-- Журнал выданных лент: по нему офлайн сравниваются варианты ранжирования
+CREATE TABLE feed_log (
+    id BIGSERIAL PRIMARY KEY,
+    viewer_id BIGINT NOT NULL,
+    experiment TEXT NOT NULL,
+    variant TEXT NOT NULL,
+    candidate_ids BIGINT[] NOT NULL,
+    scores DOUBLE PRECISION[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_feed_log_viewer_created_at ON feed_log (viewer_id, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_questions;
+DROP TABLE IF EXISTS questions;
@@ -0,0 +1,28 @@
+-- Анкета совместимости: вопросы с вариантами ответа
+CREATE TABLE questions (
+    id SERIAL PRIMARY KEY,
+    text VARCHAR(255) NOT NULL UNIQUE,
+    options TEXT[] NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Ответ пользователя: свой вариант, подходящие варианты партнера и важность вопроса (0-4)
+CREATE TABLE user_questions (
+    telegram_id BIGINT NOT NULL REFERENCES users (telegram_id) ON DELETE CASCADE,
+    question_id INT NOT NULL REFERENCES questions (id) ON DELETE CASCADE,
+    answer SMALLINT NOT NULL,
+    acceptable SMALLINT[] NOT NULL,
+    importance SMALLINT NOT NULL CHECK (importance BETWEEN 0 AND 4),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (telegram_id, question_id)
+);
+
+INSERT INTO questions (text, options) VALUES
+    ('Хочешь ли ты детей?', ARRAY['Да', 'Нет', 'Пока не знаю']),
+    ('Как ты относишься к курению?', ARRAY['Курю', 'Не курю, но не против', 'Против']),
+    ('Как ты предпочитаешь проводить выходные?', ARRAY['Дома', 'С друзьями', 'В поездках']),
+    ('Насколько для тебя важна религия?', ARRAY['Очень важна', 'Немного', 'Совсем не важна']),
+    ('Ты жаворонок или сова?', ARRAY['Жаворонок', 'Сова', 'По-разному']),
+    ('Как часто ты хочешь видеться в начале отношений?', ARRAY['Каждый день', 'Пару раз в неделю', 'Раз в неделю']);
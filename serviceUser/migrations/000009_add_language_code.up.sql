@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN language_code VARCHAR(16) NOT NULL DEFAULT '';
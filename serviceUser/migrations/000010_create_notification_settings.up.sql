@@ -0,0 +1,10 @@
+-- Настройки уведомлений: отключенные типы, тихие часы по местному времени и режим доставки
+CREATE TABLE notification_settings (
+    telegram_id BIGINT PRIMARY KEY REFERENCES users (telegram_id) ON DELETE CASCADE,
+    disabled TEXT[] NOT NULL DEFAULT '{}',
+    quiet_from SMALLINT,
+    quiet_to SMALLINT,
+    time_zone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow',
+    mode VARCHAR(16) NOT NULL DEFAULT 'instant',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
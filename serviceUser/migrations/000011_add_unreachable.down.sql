@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS unreachable_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS unreachable_at;
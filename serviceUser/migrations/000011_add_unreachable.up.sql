@@ -0,0 +1,4 @@
+-- Пользователь заблокировал бота или удалил аккаунт: анкета не показывается в выдаче,
+-- пока он снова не запустит бота командой /start
+ALTER TABLE users ADD COLUMN unreachable_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN unreachable_reason VARCHAR(32);
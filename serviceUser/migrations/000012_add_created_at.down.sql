@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_city_created_at;
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
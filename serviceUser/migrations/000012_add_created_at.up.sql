@@ -0,0 +1,6 @@
+-- Время создания старых анкет неизвестно: считаем их давними, чтобы
+-- напоминание о новых анкетах в городе не сработало на всех сразу
+ALTER TABLE users ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT 'epoch';
+ALTER TABLE users ALTER COLUMN created_at SET DEFAULT now();
+
+CREATE INDEX idx_users_city_created_at ON users (city, created_at);